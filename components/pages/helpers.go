@@ -1,18 +1,98 @@
 package pages
 
 import (
+  "fmt"
+  "fontseca.dev/components/ui"
+  "fontseca.dev/model"
+  "fontseca.dev/shortcode"
+  "fontseca.dev/transfer"
   "github.com/gomarkdown/markdown"
+  "github.com/gomarkdown/markdown/ast"
   "github.com/gomarkdown/markdown/html"
   "github.com/gomarkdown/markdown/parser"
+  stdhtml "html"
+  "io"
 )
 
-var extensions = parser.CommonExtensions
+// extensions must be the ones the service builds the tables of contents
+// with, so that the IDs of the headings match.
+var extensions = parser.CommonExtensions | parser.AutoHeadingIDs
 var htmlFlags = html.CommonFlags | html.HrefTargetBlank
-var opts = html.RendererOptions{Flags: htmlFlags}
+var opts = html.RendererOptions{Flags: htmlFlags, RenderNodeHook: renderNode}
 
+// tableOfContentsMinHeadings is the number of headings an article must
+// exceed to get a table of contents.
+const tableOfContentsMinHeadings = 3
+
+// renderNode renders the nodes whose HTML differs from gomarkdown's:
+// fenced code blocks are highlighted, and a link to each heading with an
+// ID is appended to it.
+func renderNode(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+  switch n := node.(type) {
+  case *ast.CodeBlock:
+    if n.IsFenced {
+      renderCodeBlock(w, n)
+      return ast.GoToNext, true
+    }
+  case *ast.Heading:
+    if !entering && "" != n.HeadingID {
+      fmt.Fprintf(w, `<a class="heading-anchor" href="#%s" title="Link to this section">#</a>`, stdhtml.EscapeString(n.HeadingID))
+    }
+  }
+
+  return ast.GoToNext, false
+}
+
+// countHeadings counts the headings in a table of contents.
+func countHeadings(headings []*model.Heading) (count int) {
+  for _, h := range headings {
+    count += 1 + countHeadings(h.Children)
+  }
+  return count
+}
+
+// md2html renders Markdown with shortcodes into HTML. The result is passed
+// through the sanitizer, so content coming from collaborators cannot run
+// scripts, be it raw HTML or the arguments of a shortcode. Images of the
+// media library are then given a srcset, and every image loads lazily.
 func md2html(md string) string {
+  return ui.ResponsiveImages(sanitizer.Sanitize(shortcode.Render(md, renderMarkdown)))
+}
+
+// RenderVersion is the version of md2html. Bump it whenever the HTML it
+// renders changes, or the way the service computes the tables of contents
+// and word counts does, so that every stored rendering is made again.
+const RenderVersion = 2
+
+// Markdown renders the content of articles for the service to store it.
+type Markdown struct{}
+
+func (Markdown) Render(md string) string {
+  return md2html(md)
+}
+
+func (Markdown) Version() int {
+  return RenderVersion
+}
+
+func renderMarkdown(md string) string {
   var p = parser.NewWithExtensions(extensions)
   var renderer = html.NewRenderer(opts)
   var data = markdown.ToHTML([]byte(md), p, renderer)
   return string(data)
 }
+
+func sortLabel(sort transfer.ArticleSort) string {
+  switch sort {
+  default:
+    return "Newest"
+  case transfer.SortOldest:
+    return "Oldest"
+  case transfer.SortMostViewed:
+    return "Most viewed"
+  case transfer.SortRecentlyModified:
+    return "Recently modified"
+  case transfer.SortReadingTime:
+    return "Quickest to read"
+  }
+}
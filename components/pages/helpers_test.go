@@ -0,0 +1,60 @@
+package pages
+
+import (
+  "github.com/stretchr/testify/assert"
+  "strings"
+  "testing"
+)
+
+func TestMd2html(t *testing.T) {
+  t.Run("removes scripts", func(t *testing.T) {
+    for _, md := range []string{
+      "<script>alert(1)</script>",
+      `<img src="x.png" onerror="alert(1)">`,
+      "[Lorem](javascript:alert(1))",
+      `<a href="#" onclick="alert(1)">Lorem</a>`,
+      `<iframe src="https://example.com/embed"></iframe>`,
+      `<svg><script>alert(1)</script></svg>`,
+    } {
+      html := md2html(md)
+      assert.NotContains(t, html, "alert", md)
+      assert.NotContains(t, html, "example.com", md)
+    }
+  })
+
+  t.Run("keeps figures", func(t *testing.T) {
+    md := `<figure><img src="/public/images/lorem.png" alt="Lorem"><figcaption><div class="caption"><p>Lorem ipsum.</p></div></figcaption></figure>`
+    assert.Contains(t, md2html(md), `<figure><img src="/public/images/lorem.png" alt="Lorem" loading="lazy"><figcaption><div class="caption"><p>Lorem ipsum.</p></div></figcaption></figure>`)
+  })
+
+  t.Run("makes media images responsive", func(t *testing.T) {
+    hash := strings.Repeat("ab", 32)
+    html := md2html("![Lorem](/media/" + hash + ")")
+    assert.Contains(t, html, `src="/img/`+hash+`?w=960"`)
+    assert.Contains(t, html, `srcset="/img/`+hash+`?w=320 320w, `)
+    assert.Contains(t, html, `/img/`+hash+`?w=1920 1920w"`)
+    assert.Contains(t, html, `sizes="`)
+    assert.Contains(t, html, `loading="lazy"`)
+  })
+
+  t.Run("keeps trusted embeds", func(t *testing.T) {
+    html := md2html(`<div class="video"><iframe src="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ" width="560" height="315" allowfullscreen></iframe></div>`)
+    assert.Contains(t, html, `<div class="video">`)
+    assert.Contains(t, html, `<iframe src="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ" width="560" height="315"`)
+  })
+
+  t.Run("anchors headings", func(t *testing.T) {
+    html := md2html("## Año nuevo\n\n## Año nuevo\n\n### `go vet`")
+    assert.Contains(t, html, `<h2 id="año-nuevo">`)
+    assert.Contains(t, html, `<h2 id="año-nuevo-1">`)
+    assert.Contains(t, html, `<h3 id="go-vet">`)
+  })
+
+  t.Run("keeps Markdown", func(t *testing.T) {
+    html := md2html("## Lorem\n\n[Ipsum](https://example.com) **dolor**.\n\n```go\nfmt.Println()\n```")
+    assert.Contains(t, html, `<h2 id="lorem">Lorem<a class="heading-anchor" href="#lorem" title="Link to this section">#</a></h2>`)
+    assert.Contains(t, html, `<a href="https://example.com" target="_blank" rel="noopener">Ipsum</a>`)
+    assert.Contains(t, html, "<strong>dolor</strong>")
+    assert.Contains(t, html, `<code class="language-go">`)
+  })
+}
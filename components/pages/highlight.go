@@ -0,0 +1,147 @@
+package pages
+
+import (
+  "bytes"
+  "fmt"
+  "github.com/alecthomas/chroma/v2"
+  chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+  "github.com/alecthomas/chroma/v2/lexers"
+  "github.com/alecthomas/chroma/v2/styles"
+  "github.com/gomarkdown/markdown/ast"
+  "html"
+  "io"
+  "log/slog"
+  "strconv"
+  "strings"
+)
+
+// codeBlockInfo holds the options of a fenced code block, given after the
+// language in its info string, which must be braced to have spaces:
+//
+//	```{go main.go hl=3,5-7 numbers}
+//
+// where 'main.go' is a file name, shown as the caption of the block,
+// 'hl=3,5-7' are the lines to highlight, and 'numbers' adds line numbers.
+type codeBlockInfo struct {
+  Language   string
+  FileName   string
+  Highlights [][2]int
+  Numbers    bool
+}
+
+func parseCodeBlockInfo(info string) *codeBlockInfo {
+  block := &codeBlockInfo{}
+
+  for i, field := range strings.Fields(info) {
+    switch {
+    case "numbers" == field || "linenos" == field:
+      block.Numbers = true
+    case strings.HasPrefix(field, "hl="):
+      block.Highlights = append(block.Highlights, parseLineRanges(strings.TrimPrefix(field, "hl="))...)
+    case strings.HasPrefix(field, "title="):
+      block.FileName = strings.Trim(strings.TrimPrefix(field, "title="), `"'`)
+    case 0 == i:
+      block.Language = strings.ToLower(field)
+    case "" == block.FileName:
+      block.FileName = field
+    }
+  }
+
+  return block
+}
+
+// parseLineRanges parses comma-separated line numbers or ranges of lines,
+// like '3,5-7'. Malformed ranges are ignored.
+func parseLineRanges(ranges string) [][2]int {
+  parsed := make([][2]int, 0)
+
+  for _, r := range strings.Split(ranges, ",") {
+    start, end, isRange := strings.Cut(strings.TrimSpace(r), "-")
+    if !isRange {
+      end = start
+    }
+
+    from, err := strconv.Atoi(start)
+    if nil != err || 0 >= from {
+      continue
+    }
+
+    to, err := strconv.Atoi(end)
+    if nil != err || to < from {
+      continue
+    }
+
+    parsed = append(parsed, [2]int{from, to})
+  }
+
+  return parsed
+}
+
+// codePreWrapper wraps highlighted code the way gomarkdown wraps plain
+// code, so that both are styled and sanitized alike.
+type codePreWrapper struct {
+  language string
+}
+
+func (p codePreWrapper) Start(code bool, _ string) string {
+  if !code {
+    return `<pre class="chroma">`
+  }
+
+  if "" == p.language {
+    return `<pre class="chroma"><code>`
+  }
+
+  return fmt.Sprintf(`<pre class="chroma"><code class="language-%s">`, html.EscapeString(p.language))
+}
+
+func (p codePreWrapper) End(code bool) string {
+  if !code {
+    return `</pre>`
+  }
+
+  return `</code></pre>`
+}
+
+// renderCodeBlock renders a fenced code block highlighted with CSS
+// classes, which are defined in '/public/stylesheets/syntax.css'. Code in
+// an unknown language is only escaped.
+func renderCodeBlock(w io.Writer, block *ast.CodeBlock) {
+  info := parseCodeBlockInfo(string(block.Info))
+
+  lexer := lexers.Get(info.Language)
+  if nil == lexer {
+    lexer = lexers.Fallback
+  }
+
+  formatter := chromahtml.New(
+    chromahtml.WithClasses(true),
+    chromahtml.WithLineNumbers(info.Numbers),
+    chromahtml.HighlightLines(info.Highlights),
+    chromahtml.TabWidth(4),
+    chromahtml.WithPreWrapper(codePreWrapper{language: info.Language}),
+  )
+
+  var code bytes.Buffer
+
+  iterator, err := chroma.Coalesce(lexer).Tokenise(nil, string(block.Literal))
+  if nil == err {
+    err = formatter.Format(&code, styles.Fallback, iterator)
+  }
+
+  if nil != err {
+    slog.Error(err.Error())
+    code.Reset()
+    fmt.Fprintf(&code, `<pre class="chroma"><code>%s</code></pre>`, html.EscapeString(string(block.Literal)))
+  }
+
+  fmt.Fprint(w, `<figure class="code-block">`)
+
+  if "" != info.FileName {
+    fmt.Fprintf(w, `<figcaption class="file-name">%s</figcaption>`, html.EscapeString(info.FileName))
+  }
+
+  code.WriteTo(w)
+
+  fmt.Fprint(w, "</figure>\n")
+}
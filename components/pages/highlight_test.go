@@ -0,0 +1,50 @@
+package pages
+
+import (
+  "github.com/stretchr/testify/assert"
+  "testing"
+)
+
+func Test_parseCodeBlockInfo(t *testing.T) {
+  assert.Equal(t, &codeBlockInfo{}, parseCodeBlockInfo(""))
+  assert.Equal(t, &codeBlockInfo{Language: "go"}, parseCodeBlockInfo("Go"))
+
+  assert.Equal(t, &codeBlockInfo{
+    Language:   "go",
+    FileName:   "main.go",
+    Highlights: [][2]int{{3, 3}, {5, 7}},
+    Numbers:    true,
+  }, parseCodeBlockInfo("go main.go hl=3,5-7 numbers"))
+
+  assert.Equal(t, &codeBlockInfo{
+    Language:   "sql",
+    FileName:   "schema.sql",
+    Highlights: [][2]int{{2, 2}},
+  }, parseCodeBlockInfo(`sql title="schema.sql" hl=2,x,4-1,-3`))
+}
+
+func TestMd2html_CodeBlocks(t *testing.T) {
+  t.Run("highlights code", func(t *testing.T) {
+    html := md2html("```go\nfunc main() {}\n```")
+    assert.Contains(t, html, `<figure class="code-block"><pre class="chroma"><code class="language-go">`)
+    assert.Contains(t, html, `<span class="kd">func</span>`)
+    assert.NotContains(t, html, "style=")
+  })
+
+  t.Run("numbers and highlights lines", func(t *testing.T) {
+    html := md2html("```{go main.go hl=2 numbers}\npackage main\n\nfunc main() {}\n```")
+    assert.Contains(t, html, `<figcaption class="file-name">main.go</figcaption>`)
+    assert.Contains(t, html, `<span class="line hl"><span class="ln">2</span>`)
+    assert.Contains(t, html, `<span class="ln">3</span>`)
+  })
+
+  t.Run("escapes unknown languages", func(t *testing.T) {
+    html := md2html("```lorem\n<script>alert(1)</script>\n```")
+    assert.Contains(t, html, "&lt;script&gt;alert(1)&lt;/script&gt;")
+  })
+
+  t.Run("escapes file names", func(t *testing.T) {
+    html := md2html("```{go <b>.go}\npackage main\n```")
+    assert.Contains(t, html, `<figcaption class="file-name">&lt;b&gt;.go</figcaption>`)
+  })
+}
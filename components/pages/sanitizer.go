@@ -0,0 +1,59 @@
+package pages
+
+import (
+  "github.com/microcosm-cc/bluemonday"
+  "regexp"
+  "strings"
+)
+
+// TrustedEmbeds are the prefixes of the sources of the iframes that can be
+// embedded in articles and projects.
+var TrustedEmbeds = []string{
+  "https://www.youtube.com/embed/",
+  "https://www.youtube-nocookie.com/embed/",
+  "https://player.vimeo.com/video/",
+  "https://codepen.io/",
+  "https://open.spotify.com/embed/",
+}
+
+// sanitizer is the allowlist of the HTML that can be rendered from
+// Markdown content: that of user-generated content, plus the classes and
+// elements of our figures, videos and embeds.
+var sanitizer = newSanitizer()
+
+func newSanitizer() *bluemonday.Policy {
+  p := bluemonday.UGCPolicy()
+
+  embeds := make([]string, len(TrustedEmbeds))
+  for i, prefix := range TrustedEmbeds {
+    embeds[i] = regexp.QuoteMeta(prefix)
+  }
+
+  p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-zA-Z0-9_\- ]+$`)).OnElements("a", "aside", "div", "span", "figure", "figcaption", "p", "img", "code", "pre")
+  // The IDs of headings are made of any letters and numbers.
+  p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}\-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+
+  p.AllowElements("figure", "figcaption", "picture", "source")
+  p.AllowAttrs("srcset", "media", "type").OnElements("source")
+  p.AllowAttrs("target").Matching(regexp.MustCompile(`^_blank$`)).OnElements("a")
+  p.AllowAttrs("loading").Matching(regexp.MustCompile(`^(lazy|eager)$`)).OnElements("img", "iframe")
+
+  p.AllowElements("video", "audio")
+  p.AllowAttrs("src", "poster").OnElements("video", "audio", "source")
+  p.AllowAttrs("width", "height").Matching(bluemonday.Integer).OnElements("video", "iframe")
+  p.AllowAttrs("controls", "loop", "muted", "playsinline").Matching(regexp.MustCompile(`^$|^[a-z]+$`)).OnElements("video", "audio")
+
+  p.AllowElements("iframe")
+  p.AllowAttrs("src").Matching(regexp.MustCompile("^(" + strings.Join(embeds, "|") + ")")).OnElements("iframe")
+  p.AllowAttrs("title").OnElements("iframe")
+  p.AllowAttrs("allowfullscreen").Matching(regexp.MustCompile(`^$|^allowfullscreen$|^true$`)).OnElements("iframe")
+  p.AllowAttrs("allow").Matching(regexp.MustCompile(`^[a-z\-; ]+$`)).OnElements("iframe")
+  p.AllowAttrs("frameborder").Matching(bluemonday.Integer).OnElements("iframe")
+  p.AllowAttrs("referrerpolicy").Matching(regexp.MustCompile(`^[a-z\-]+$`)).OnElements("iframe")
+
+  // Links are ours to vouch for, unlike those in user-generated content.
+  p.RequireNoFollowOnLinks(false)
+  p.AddTargetBlankToFullyQualifiedLinks(true)
+
+  return p
+}
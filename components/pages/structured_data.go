@@ -0,0 +1,236 @@
+package pages
+
+import (
+  "encoding/json"
+  "fmt"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/a-h/templ"
+  "strconv"
+  "time"
+)
+
+// siteAuthor is the name the articles and projects of the site are
+// credited to.
+const siteAuthor = "fontseca"
+
+// schema is a schema.org object, rendered as JSON-LD.
+type schema map[string]any
+
+// structuredData renders a schema.org object as a JSON-LD script. The
+// JSON encoder escapes '<', '>' and '&', so no value can close the script.
+func structuredData(data schema) templ.Component {
+  data["@context"] = "https://schema.org"
+
+  encoded, err := json.Marshal(data)
+  if nil != err {
+    return templ.NopComponent
+  }
+
+  return templ.Raw(`<script type="application/ld+json">` + string(encoded) + `</script>`)
+}
+
+// breadcrumb is an item of a schema.org BreadcrumbList.
+type breadcrumb struct {
+  Name string
+  URL  string
+}
+
+func breadcrumbsStructuredData(breadcrumbs ...breadcrumb) templ.Component {
+  items := make([]schema, len(breadcrumbs))
+
+  for i, b := range breadcrumbs {
+    items[i] = schema{
+      "@type":    "ListItem",
+      "position": i + 1,
+      "name":     b.Name,
+      "item":     b.URL,
+    }
+  }
+
+  return structuredData(schema{
+    "@type":           "BreadcrumbList",
+    "itemListElement": items,
+  })
+}
+
+// archiveBreadcrumbs builds the breadcrumbs of an archive listing, which
+// may be narrowed down to a topic, a publication date or a tag.
+func archiveBreadcrumbs(publication *transfer.Publication, topic *model.Topic, tag *model.Tag) templ.Component {
+  const archiveURL = "https://fontseca.dev/archive"
+
+  breadcrumbs := []breadcrumb{{Name: "fontseca.dev", URL: "https://fontseca.dev/"}, {Name: "Archive", URL: archiveURL}}
+
+  switch {
+  case nil != tag:
+    breadcrumbs = append(breadcrumbs, breadcrumb{Name: tag.Name, URL: archiveURL + "/tag/" + tag.ID})
+  case nil != topic:
+    breadcrumbs = append(breadcrumbs, breadcrumb{Name: topic.Name, URL: archiveURL + "/" + topic.ID})
+
+    if nil != publication && 0 != publication.Month && 0 != publication.Year {
+      breadcrumbs = append(breadcrumbs, breadcrumb{
+        Name: publication.Month.String() + " " + strconv.Itoa(publication.Year),
+        URL:  fmt.Sprint(archiveURL, "/", topic.ID, "/", publication.Year, "/", int(publication.Month)),
+      })
+    }
+  }
+
+  return breadcrumbsStructuredData(breadcrumbs...)
+}
+
+func articleStructuredData(article *model.Article) templ.Component {
+  data := schema{
+    "@type":            "BlogPosting",
+    "headline":         article.Title,
+    "description":      article.Summary,
+    "url":              getOGArticleURL(article),
+    "mainEntityOfPage": getOGArticleURL(article),
+    "wordCount":        article.WordCount,
+    "timeRequired":     fmt.Sprintf("PT%dM", article.ReadTime),
+    "author": schema{
+      "@type": "Person",
+      "name":  article.Author,
+      "url":   "https://fontseca.dev/",
+    },
+    "publisher": schema{
+      "@type": "Organization",
+      "name":  "fontseca.dev",
+      "url":   "https://fontseca.dev/",
+    },
+  }
+
+  if nil != article.PublishedAt {
+    data["datePublished"] = article.PublishedAt.Format(time.RFC3339)
+    data["dateModified"] = article.PublishedAt.Format(time.RFC3339)
+  }
+
+  if nil != article.ModifiedAt {
+    data["dateModified"] = article.ModifiedAt.Format(time.RFC3339)
+  }
+
+  if "" != article.CoverURL && "about:blank" != article.CoverURL {
+    data["image"] = article.CoverURL
+  } else if card := getOGCardURL(article); "" != card {
+    data["image"] = card
+  }
+
+  if nil != article.Topic {
+    data["articleSection"] = article.Topic.Name
+  }
+
+  if 0 < len(article.Tags) {
+    keywords := make([]string, len(article.Tags))
+
+    for i, tag := range article.Tags {
+      keywords[i] = tag.Name
+    }
+
+    data["keywords"] = keywords
+  }
+
+  if nil != article.CanonicalURL {
+    data["isBasedOn"] = *article.CanonicalURL
+  }
+
+  return structuredData(data)
+}
+
+// articleBreadcrumbs builds the breadcrumbs of an article, which go
+// through its topic, if any.
+func articleBreadcrumbs(article *model.Article) templ.Component {
+  breadcrumbs := []breadcrumb{{Name: "fontseca.dev", URL: "https://fontseca.dev/"}, {Name: "Archive", URL: "https://fontseca.dev/archive"}}
+
+  if nil != article.Topic {
+    breadcrumbs = append(breadcrumbs, breadcrumb{Name: article.Topic.Name, URL: "https://fontseca.dev/archive/" + article.Topic.ID})
+  }
+
+  breadcrumbs = append(breadcrumbs, breadcrumb{Name: article.Title, URL: getOGArticleURL(article)})
+
+  return breadcrumbsStructuredData(breadcrumbs...)
+}
+
+// projectStructuredData describes a project as source code if it has a
+// repository, or as a creative work otherwise.
+func projectStructuredData(project *model.Project) templ.Component {
+  data := schema{
+    "@type":        "CreativeWork",
+    "name":         project.Name,
+    "description":  project.Summary,
+    "url":          "https://fontseca.dev/work/" + project.Slug,
+    "dateCreated":  project.CreatedAt.Format(time.RFC3339),
+    "dateModified": project.UpdatedAt.Format(time.RFC3339),
+    "creator": schema{
+      "@type": "Person",
+      "name":  siteAuthor,
+      "url":   "https://fontseca.dev/",
+    },
+  }
+
+  if "" != project.GitHubURL && "about:blank" != project.GitHubURL {
+    data["@type"] = "SoftwareSourceCode"
+    data["codeRepository"] = project.GitHubURL
+  }
+
+  if nil != project.Language {
+    data["programmingLanguage"] = *project.Language
+  }
+
+  if 0 < len(project.TechnologyTags) {
+    data["keywords"] = project.TechnologyTags
+  }
+
+  if "" != project.FirstImageURL && "about:blank" != project.FirstImageURL {
+    data["image"] = project.FirstImageURL
+  }
+
+  if "" != project.Homepage && "about:blank" != project.Homepage {
+    data["sameAs"] = project.Homepage
+  }
+
+  if nil != project.Starts {
+    data["dateCreated"] = project.Starts.Format(time.DateOnly)
+  }
+
+  return structuredData(data)
+}
+
+func personStructuredData(me *model.Me) templ.Component {
+  data := schema{
+    "@type":      "Person",
+    "name":       me.FirstName + " " + me.LastName,
+    "givenName":  me.FirstName,
+    "familyName": me.LastName,
+    "jobTitle":   me.JobTitle,
+    "url":        "https://fontseca.dev/",
+  }
+
+  if "" != me.PhotoURL && "about:blank" != me.PhotoURL {
+    data["image"] = me.PhotoURL
+  }
+
+  if "" != me.Email {
+    data["email"] = "mailto:" + me.Email
+  }
+
+  if "" != me.Company {
+    data["worksFor"] = schema{"@type": "Organization", "name": me.Company}
+  }
+
+  if "" != me.Location {
+    data["homeLocation"] = schema{"@type": "Place", "name": me.Location}
+  }
+
+  sameAs := make([]string, 0, 5)
+
+  for _, link := range []string{me.GitHubURL, me.LinkedInURL, me.YouTubeURL, me.TwitterURL, me.InstagramURL} {
+    if "" != link && "about:blank" != link {
+      sameAs = append(sameAs, link)
+    }
+  }
+
+  if 0 < len(sameAs) {
+    data["sameAs"] = sameAs
+  }
+
+  return structuredData(data)
+}
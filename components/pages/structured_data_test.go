@@ -0,0 +1,280 @@
+package pages
+
+import (
+  "bytes"
+  "context"
+  "encoding/json"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/a-h/templ"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "strings"
+  "testing"
+  "time"
+)
+
+// decodeStructuredData renders a JSON-LD script and decodes the object
+// in it.
+func decodeStructuredData(t *testing.T, component templ.Component) map[string]any {
+  var buf bytes.Buffer
+  require.NoError(t, component.Render(context.Background(), &buf))
+
+  script := buf.String()
+  require.True(t, strings.HasPrefix(script, `<script type="application/ld+json">`), script)
+  require.True(t, strings.HasSuffix(script, `</script>`), script)
+  require.Equal(t, 1, strings.Count(strings.ToLower(script), "</script"), "the script is closed before its end: %s", script)
+
+  var data map[string]any
+  body := strings.TrimSuffix(strings.TrimPrefix(script, `<script type="application/ld+json">`), `</script>`)
+  require.NoError(t, json.Unmarshal([]byte(body), &data))
+
+  assert.Equal(t, "https://schema.org", data["@context"])
+
+  return data
+}
+
+func TestArticleStructuredData(t *testing.T) {
+  published := time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC)
+  modified := published.Add(48 * time.Hour)
+  canonical := "https://dev.to/fontseca/lorem"
+
+  for _, test := range []struct {
+    name     string
+    article  *model.Article
+    expected map[string]any
+    missing  []string
+  }{
+    {
+      name: "published article",
+      article: &model.Article{
+        UUID:        uuid.MustParse("5b0e9a4c-2f61-4d8e-b7a3-1c9d0e6f8a27"),
+        Title:       "Lorem ipsum",
+        Author:      "fontseca",
+        Slug:        "lorem-ipsum",
+        Summary:     "Dolor sit amet.",
+        ReadTime:    4,
+        WordCount:   812,
+        CoverURL:    "about:blank",
+        PublishedAt: &published,
+        ModifiedAt:  &modified,
+        Topic:       &model.Topic{ID: "development", Name: "Development"},
+        Tags:        []*model.Tag{{ID: "go", Name: "Go"}, {ID: "sql", Name: "SQL"}},
+      },
+      expected: map[string]any{
+        "@type":            "BlogPosting",
+        "headline":         "Lorem ipsum",
+        "description":      "Dolor sit amet.",
+        "url":              "https://fontseca.dev/archive/development/2026/10/lorem-ipsum",
+        "mainEntityOfPage": "https://fontseca.dev/archive/development/2026/10/lorem-ipsum",
+        "wordCount":        float64(812),
+        "timeRequired":     "PT4M",
+        "datePublished":    "2026-10-18T09:30:00Z",
+        "dateModified":     "2026-10-20T09:30:00Z",
+        "image":            "https://fontseca.dev/og/archive/5b0e9a4c-2f61-4d8e-b7a3-1c9d0e6f8a27",
+        "articleSection":   "Development",
+        "keywords":         []any{"Go", "SQL"},
+        "author":           map[string]any{"@type": "Person", "name": "fontseca", "url": "https://fontseca.dev/"},
+        "publisher":        map[string]any{"@type": "Organization", "name": "fontseca.dev", "url": "https://fontseca.dev/"},
+      },
+      missing: []string{"isBasedOn"},
+    },
+    {
+      name: "article first published elsewhere",
+      article: &model.Article{
+        Title:        "Lorem ipsum",
+        CoverURL:     "https://fontseca.dev/media/cover.png",
+        PublishedAt:  &published,
+        CanonicalURL: &canonical,
+      },
+      expected: map[string]any{
+        "dateModified": "2026-10-18T09:30:00Z",
+        "image":        "https://fontseca.dev/media/cover.png",
+        "isBasedOn":    canonical,
+      },
+      missing: []string{"articleSection", "keywords"},
+    },
+    {
+      name:    "escapes the title",
+      article: &model.Article{Title: `</script><script>alert("x")</script>`, IsDraft: true},
+      expected: map[string]any{
+        "headline": `</script><script>alert("x")</script>`,
+      },
+      missing: []string{"datePublished", "image"},
+    },
+  } {
+    t.Run(test.name, func(t *testing.T) {
+      data := decodeStructuredData(t, articleStructuredData(test.article))
+
+      for property, value := range test.expected {
+        assert.Equal(t, value, data[property], property)
+      }
+
+      for _, property := range test.missing {
+        assert.NotContains(t, data, property)
+      }
+    })
+  }
+}
+
+func TestBreadcrumbsStructuredData(t *testing.T) {
+  published := time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC)
+  topic := &model.Topic{ID: "development", Name: "Development"}
+
+  for _, test := range []struct {
+    name      string
+    component templ.Component
+    expected  []string // the names and URLs of the items, in order
+  }{
+    {
+      name:      "archive",
+      component: archiveBreadcrumbs(nil, nil, nil),
+      expected:  []string{"fontseca.dev", "https://fontseca.dev/", "Archive", "https://fontseca.dev/archive"},
+    },
+    {
+      name:      "archive by topic and publication",
+      component: archiveBreadcrumbs(&transfer.Publication{Month: time.October, Year: 2026}, topic, nil),
+      expected: []string{
+        "fontseca.dev", "https://fontseca.dev/",
+        "Archive", "https://fontseca.dev/archive",
+        "Development", "https://fontseca.dev/archive/development",
+        "October 2026", "https://fontseca.dev/archive/development/2026/10",
+      },
+    },
+    {
+      name:      "archive by tag",
+      component: archiveBreadcrumbs(nil, topic, &model.Tag{ID: "go", Name: "Go"}),
+      expected: []string{
+        "fontseca.dev", "https://fontseca.dev/",
+        "Archive", "https://fontseca.dev/archive",
+        "Go", "https://fontseca.dev/archive/tag/go",
+      },
+    },
+    {
+      name:      "article",
+      component: articleBreadcrumbs(&model.Article{Title: "</script>", Slug: "lorem-ipsum", Topic: topic, PublishedAt: &published}),
+      expected: []string{
+        "fontseca.dev", "https://fontseca.dev/",
+        "Archive", "https://fontseca.dev/archive",
+        "Development", "https://fontseca.dev/archive/development",
+        "</script>", "https://fontseca.dev/archive/development/2026/10/lorem-ipsum",
+      },
+    },
+  } {
+    t.Run(test.name, func(t *testing.T) {
+      data := decodeStructuredData(t, test.component)
+      assert.Equal(t, "BreadcrumbList", data["@type"])
+
+      items, ok := data["itemListElement"].([]any)
+      require.True(t, ok)
+      require.Len(t, items, len(test.expected)/2)
+
+      for i, item := range items {
+        assert.Equal(t, map[string]any{
+          "@type":    "ListItem",
+          "position": float64(i + 1),
+          "name":     test.expected[2*i],
+          "item":     test.expected[2*i+1],
+        }, item)
+      }
+    })
+  }
+}
+
+func TestProjectStructuredData(t *testing.T) {
+  created := time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC)
+  language := "Go"
+
+  for _, test := range []struct {
+    name     string
+    project  *model.Project
+    expected map[string]any
+    missing  []string
+  }{
+    {
+      name: "project with a repository",
+      project: &model.Project{
+        Name:           "fontseca.dev",
+        Slug:           "fontseca-dev",
+        Summary:        "My website.",
+        Homepage:       "https://fontseca.dev",
+        Language:       &language,
+        GitHubURL:      "https://github.com/fontseca/.dev",
+        FirstImageURL:  "about:blank",
+        TechnologyTags: []string{"Go", "PostgreSQL"},
+        CreatedAt:      created,
+        UpdatedAt:      created,
+      },
+      expected: map[string]any{
+        "@type":               "SoftwareSourceCode",
+        "name":                "fontseca.dev",
+        "description":         "My website.",
+        "url":                 "https://fontseca.dev/work/fontseca-dev",
+        "codeRepository":      "https://github.com/fontseca/.dev",
+        "programmingLanguage": "Go",
+        "keywords":            []any{"Go", "PostgreSQL"},
+        "sameAs":              "https://fontseca.dev",
+        "dateCreated":         "2026-10-18T09:30:00Z",
+        "dateModified":        "2026-10-18T09:30:00Z",
+        "creator":             map[string]any{"@type": "Person", "name": "fontseca", "url": "https://fontseca.dev/"},
+      },
+      missing: []string{"image"},
+    },
+    {
+      name: "project without a repository",
+      project: &model.Project{
+        Name:      `</script><script>alert("x")</script>`,
+        GitHubURL: "about:blank",
+        Starts:    &created,
+      },
+      expected: map[string]any{
+        "@type":       "CreativeWork",
+        "name":        `</script><script>alert("x")</script>`,
+        "dateCreated": "2026-10-18",
+        "creator":     map[string]any{"@type": "Person", "name": "fontseca", "url": "https://fontseca.dev/"},
+      },
+      missing: []string{"codeRepository", "programmingLanguage", "keywords", "sameAs"},
+    },
+  } {
+    t.Run(test.name, func(t *testing.T) {
+      data := decodeStructuredData(t, projectStructuredData(test.project))
+
+      for property, value := range test.expected {
+        assert.Equal(t, value, data[property], property)
+      }
+
+      for _, property := range test.missing {
+        assert.NotContains(t, data, property)
+      }
+    })
+  }
+}
+
+func TestPersonStructuredData(t *testing.T) {
+  me := &model.Me{
+    FirstName:   "Shelby",
+    LastName:    "Fontseca",
+    JobTitle:    "Software Engineer",
+    Email:       "fontseca.dev@outlook.com",
+    Company:     "</script>",
+    Location:    "Managua, Nicaragua",
+    PhotoURL:    "about:blank",
+    GitHubURL:   "https://github.com/fontseca",
+    LinkedInURL: "about:blank",
+  }
+
+  data := decodeStructuredData(t, personStructuredData(me))
+
+  assert.Equal(t, "Person", data["@type"])
+  assert.Equal(t, "Shelby Fontseca", data["name"])
+  assert.Equal(t, "Shelby", data["givenName"])
+  assert.Equal(t, "Fontseca", data["familyName"])
+  assert.Equal(t, "Software Engineer", data["jobTitle"])
+  assert.Equal(t, "https://fontseca.dev/", data["url"])
+  assert.Equal(t, "mailto:fontseca.dev@outlook.com", data["email"])
+  assert.Equal(t, map[string]any{"@type": "Organization", "name": "</script>"}, data["worksFor"])
+  assert.Equal(t, map[string]any{"@type": "Place", "name": "Managua, Nicaragua"}, data["homeLocation"])
+  assert.Equal(t, []any{"https://github.com/fontseca"}, data["sameAs"])
+  assert.NotContains(t, data, "image")
+}
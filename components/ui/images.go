@@ -0,0 +1,135 @@
+package ui
+
+import (
+  "fontseca.dev/transfer"
+  "github.com/a-h/templ"
+  "golang.org/x/net/html"
+  "regexp"
+  "strconv"
+  "strings"
+)
+
+// mediaURL matches the URLs of the files of the media library.
+var mediaURL = regexp.MustCompile(`^(?:https://(?:www\.)?fontseca\.dev)?/media/([0-9a-f]{64})$`)
+
+const (
+  // imageSizes tells browsers how wide images are shown: as wide as the
+  // screen on small screens, and as the content column otherwise.
+  imageSizes = "(max-width: 800px) 100vw, 800px"
+
+  // imageFallbackWidth is the width of the image browsers that do not
+  // support srcset load.
+  imageFallbackWidth = 960
+)
+
+// responsiveImage returns the src and srcset of an image. Only images of
+// the media library have derivatives; the src of other images is kept
+// and their srcset is empty.
+func responsiveImage(src string) (string, string) {
+  match := mediaURL.FindStringSubmatch(src)
+  if nil == match {
+    return src, ""
+  }
+
+  var (
+    base   = "/img/" + match[1]
+    srcset = make([]string, len(transfer.ImageWidths))
+  )
+
+  for i, w := range transfer.ImageWidths {
+    srcset[i] = base + "?w=" + strconv.Itoa(w) + " " + strconv.Itoa(w) + "w"
+  }
+
+  return base + "?w=" + strconv.Itoa(imageFallbackWidth), strings.Join(srcset, ", ")
+}
+
+// ImageAttributes are the attributes of an <img> showing the image at
+// src: its srcset and sizes, if it is in the media library, and lazy
+// loading.
+func ImageAttributes(src string) templ.Attributes {
+  src, srcset := responsiveImage(src)
+  attributes := templ.Attributes{"src": src, "loading": "lazy"}
+
+  if "" != srcset {
+    attributes["srcset"] = srcset
+    attributes["sizes"] = imageSizes
+  }
+
+  return attributes
+}
+
+// ResponsiveImages rewrites the <img> tags of rendered HTML as
+// ImageAttributes does. Images that already have a srcset keep it.
+func ResponsiveImages(rendered string) string {
+  var (
+    b strings.Builder
+    z = html.NewTokenizer(strings.NewReader(rendered))
+  )
+
+  for {
+    tt := z.Next()
+    if html.ErrorToken == tt {
+      return b.String()
+    }
+
+    raw := string(z.Raw())
+
+    if html.StartTagToken != tt && html.SelfClosingTagToken != tt {
+      b.WriteString(raw)
+      continue
+    }
+
+    token := z.Token()
+    if "img" != token.Data {
+      b.WriteString(raw)
+      continue
+    }
+
+    b.WriteString(responsiveImageTag(token).String())
+  }
+}
+
+func responsiveImageTag(token html.Token) html.Token {
+  var (
+    src       string
+    hasSrcset bool
+    hasLazy   bool
+  )
+
+  for _, attr := range token.Attr {
+    switch attr.Key {
+    case "src":
+      src = attr.Val
+    case "srcset":
+      hasSrcset = true
+    case "loading":
+      hasLazy = true
+    }
+  }
+
+  if !hasLazy {
+    token.Attr = append(token.Attr, html.Attribute{Key: "loading", Val: "lazy"})
+  }
+
+  if hasSrcset {
+    return token
+  }
+
+  src, srcset := responsiveImage(src)
+  if "" == srcset {
+    return token
+  }
+
+  for i := range token.Attr {
+    if "src" == token.Attr[i].Key {
+      token.Attr[i].Val = src
+    }
+  }
+
+  token.Attr = append(token.Attr,
+    html.Attribute{Key: "srcset", Val: srcset},
+    html.Attribute{Key: "sizes", Val: imageSizes},
+  )
+
+  return token
+}
@@ -0,0 +1,18 @@
+BEGIN;
+
+ALTER TABLE "archive"."article"
+    ADD COLUMN "canonical_url" VARCHAR(2048) DEFAULT NULL CHECK ("canonical_url" <> '');
+
+ALTER TABLE "archive"."article_patch"
+    ADD COLUMN "canonical_url" VARCHAR(2048) CHECK ("canonical_url" <> '');
+
+CREATE TABLE IF NOT EXISTS "archive"."article_syndication"
+(
+    "article_uuid" VARCHAR(36)   NOT NULL REFERENCES "archive"."article" ("uuid") ON DELETE CASCADE,
+    "url"          VARCHAR(2048) NOT NULL CHECK ("url" <> ''),
+    "name"         VARCHAR(64)            DEFAULT NULL CHECK ("name" <> ''),
+    "created_at"   TIMESTAMP     NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY ("article_uuid", "url")
+);
+
+COMMIT;
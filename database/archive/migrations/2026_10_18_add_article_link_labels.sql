@@ -0,0 +1,15 @@
+BEGIN;
+
+ALTER TABLE "archive"."article_link"
+    DROP CONSTRAINT IF EXISTS "article_link_article_uuid_key";
+
+ALTER TABLE "archive"."article_link"
+    ADD COLUMN "uuid"          VARCHAR(36) NOT NULL PRIMARY KEY DEFAULT "extensions"."uuid_generate_v4"(),
+    ADD COLUMN "label"         VARCHAR(64) NOT NULL DEFAULT 'default' CHECK ("label" <> ''),
+    ADD COLUMN "password_hash" VARCHAR(60)          DEFAULT NULL CHECK ("password_hash" <> ''),
+    ADD COLUMN "views"         INTEGER     NOT NULL DEFAULT 0,
+    ADD COLUMN "created_at"    TIMESTAMP   NOT NULL DEFAULT current_timestamp,
+    ADD CONSTRAINT "article_link_shareable_link_key" UNIQUE ("shareable_link"),
+    ADD CONSTRAINT "article_link_article_uuid_label_key" UNIQUE ("article_uuid", "label");
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE "archive"."article"
+    ADD COLUMN "outdated_at"     TIMESTAMP    DEFAULT NULL,
+    ADD COLUMN "outdated_reason" VARCHAR(512) DEFAULT NULL CHECK ("outdated_reason" <> ''),
+    ADD COLUMN "superseded_by"   VARCHAR(36)  DEFAULT NULL REFERENCES "archive"."article" ("uuid") ON DELETE SET NULL,
+    ADD COLUMN "reviewed_at"     TIMESTAMP    DEFAULT NULL;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE "archive"."article"
+    ADD COLUMN "rendered_html"     TEXT     DEFAULT NULL,
+    ADD COLUMN "table_of_contents" JSONB    DEFAULT NULL,
+    ADD COLUMN "word_count"        INTEGER  DEFAULT NULL CHECK ("word_count" >= 0),
+    ADD COLUMN "render_version"    SMALLINT DEFAULT NULL;
+
+COMMIT;
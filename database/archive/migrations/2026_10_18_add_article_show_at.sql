@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "archive"."article"
+    ADD COLUMN "show_at" TIMESTAMP DEFAULT NULL;
+
+COMMIT;
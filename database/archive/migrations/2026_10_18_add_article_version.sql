@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE "archive"."article"
+    ADD COLUMN "version" INTEGER NOT NULL DEFAULT 1 CHECK ("version" > 0);
+
+ALTER TABLE "archive"."article_patch"
+    ADD COLUMN "version" INTEGER NOT NULL DEFAULT 1 CHECK ("version" > 0);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS "archive"."follower"
+(
+    "actor"        VARCHAR(2048) NOT NULL PRIMARY KEY CHECK ("actor" <> ''),
+    "inbox"        VARCHAR(2048) NOT NULL CHECK ("inbox" <> ''),
+    "shared_inbox" VARCHAR(2048)          DEFAULT NULL CHECK ("shared_inbox" <> ''),
+    "followed_at"  TIMESTAMP     NOT NULL DEFAULT current_timestamp
+);
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+ALTER TABLE "archive"."article"
+    ADD COLUMN "workflow_state" VARCHAR(32) NOT NULL DEFAULT 'writing' CHECK ("workflow_state" <> '');
+
+ALTER TABLE "archive"."article_patch"
+    ADD COLUMN "workflow_state" VARCHAR(32) NOT NULL DEFAULT 'writing' CHECK ("workflow_state" <> '');
+
+CREATE TABLE IF NOT EXISTS "archive"."workflow_transition"
+(
+    "article_uuid"    VARCHAR(36)  NOT NULL REFERENCES "archive"."article" ("uuid") ON DELETE CASCADE,
+    "patch"           BOOLEAN      NOT NULL DEFAULT FALSE,
+    "from_state"      VARCHAR(32)  NOT NULL CHECK ("from_state" <> ''),
+    "to_state"        VARCHAR(32)  NOT NULL CHECK ("to_state" <> ''),
+    "actor"           VARCHAR(64)  NOT NULL CHECK ("actor" <> ''),
+    "comment"         VARCHAR(512)          DEFAULT NULL CHECK ("comment" <> ''),
+    "transitioned_at" TIMESTAMP    NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS "workflow_transition_article_uuid_idx"
+    ON "archive"."workflow_transition" ("article_uuid", "patch", "transitioned_at");
+
+COMMIT;
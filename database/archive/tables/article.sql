@@ -14,5 +14,22 @@ CREATE TABLE IF NOT EXISTS "archive"."article"
   "drafted_at"   TIMESTAMP           NOT NULL DEFAULT current_timestamp,
   "published_at" TIMESTAMP                    DEFAULT NULL,
   "updated_at"   TIMESTAMP           NOT NULL DEFAULT current_timestamp,
-  "modified_at"  TIMESTAMP                    DEFAULT NULL
+  "modified_at"  TIMESTAMP                    DEFAULT NULL,
+  "show_at"      TIMESTAMP                    DEFAULT NULL,
+
+  "outdated_at"     TIMESTAMP    DEFAULT NULL,
+  "outdated_reason" VARCHAR(512) DEFAULT NULL CHECK ("outdated_reason" <> ''),
+  "superseded_by"   VARCHAR(36)  DEFAULT NULL REFERENCES "archive"."article" ("uuid") ON DELETE SET NULL,
+  "reviewed_at"     TIMESTAMP    DEFAULT NULL,
+
+  "canonical_url" VARCHAR(2048) DEFAULT NULL CHECK ("canonical_url" <> ''),
+
+  "rendered_html"     TEXT     DEFAULT NULL,
+  "table_of_contents" JSONB    DEFAULT NULL,
+  "word_count"        INTEGER  DEFAULT NULL CHECK ("word_count" >= 0),
+  "render_version"    SMALLINT DEFAULT NULL,
+
+  "workflow_state" VARCHAR(32) NOT NULL DEFAULT 'writing' CHECK ("workflow_state" <> ''),
+
+  "version" INTEGER NOT NULL DEFAULT 1 CHECK ("version" > 0)
 );
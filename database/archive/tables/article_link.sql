@@ -1,6 +1,12 @@
 CREATE TABLE IF NOT EXISTS "archive"."article_link"
 (
-  "article_uuid"   VARCHAR(36) UNIQUE NOT NULL REFERENCES "archive"."article" ("uuid") ON DELETE CASCADE,
-  "shareable_link" VARCHAR(273)       NOT NULL CHECK ( "shareable_link" <> '' ),
-  "expires_at"     TIMESTAMP          NOT NULL DEFAULT current_timestamp + INTERVAL '+7 day'
+  "uuid"           VARCHAR(36)  NOT NULL PRIMARY KEY DEFAULT "extensions"."uuid_generate_v4"(),
+  "article_uuid"   VARCHAR(36)  NOT NULL REFERENCES "archive"."article" ("uuid") ON DELETE CASCADE,
+  "label"          VARCHAR(64)  NOT NULL DEFAULT 'default' CHECK ( "label" <> '' ),
+  "shareable_link" VARCHAR(273) NOT NULL UNIQUE CHECK ( "shareable_link" <> '' ),
+  "password_hash"  VARCHAR(60)           DEFAULT NULL CHECK ( "password_hash" <> '' ),
+  "views"          INTEGER      NOT NULL DEFAULT 0,
+  "created_at"     TIMESTAMP    NOT NULL DEFAULT current_timestamp,
+  "expires_at"     TIMESTAMP    NOT NULL DEFAULT current_timestamp + INTERVAL '+7 day',
+  UNIQUE ("article_uuid", "label")
 );
@@ -5,5 +5,8 @@ CREATE TABLE IF NOT EXISTS "archive"."article_patch"
   "topic"        VARCHAR(32) REFERENCES "archive"."topic" ("id") CHECK ( "topic" <> '' ),
   "slug"         VARCHAR(512) CHECK ("slug" <> ''),
   "read_time"    SMALLINT DEFAULT 0 CHECK ( "read_time" >= 0 ),
-  "content"      VARCHAR(3145728) CHECK ( "content" <> '' )
+  "content"      VARCHAR(3145728) CHECK ( "content" <> '' ),
+  "canonical_url" VARCHAR(2048) CHECK ( "canonical_url" <> '' ),
+  "workflow_state" VARCHAR(32) NOT NULL DEFAULT 'writing' CHECK ( "workflow_state" <> '' ),
+  "version"      INTEGER NOT NULL DEFAULT 1 CHECK ( "version" > 0 )
 );
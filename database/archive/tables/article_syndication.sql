@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS "archive"."article_syndication"
+(
+  "article_uuid" VARCHAR(36)   NOT NULL REFERENCES "archive"."article" ("uuid") ON DELETE CASCADE,
+  "url"          VARCHAR(2048) NOT NULL CHECK ( "url" <> '' ),
+  "name"         VARCHAR(64)            DEFAULT NULL CHECK ( "name" <> '' ),
+  "created_at"   TIMESTAMP     NOT NULL DEFAULT current_timestamp,
+  PRIMARY KEY ("article_uuid", "url")
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS "archive"."feedback"
+(
+  "uuid"         VARCHAR(36)   NOT NULL PRIMARY KEY DEFAULT "extensions"."uuid_generate_v4"(),
+  "article_uuid" VARCHAR(36)   NOT NULL REFERENCES "archive"."article" ("uuid") ON DELETE CASCADE,
+  "patch"        BOOLEAN       NOT NULL DEFAULT FALSE,
+  "anchor"       VARCHAR(128)  NOT NULL CHECK ( "anchor" <> '' ),
+  "anchor_text"  TEXT          NOT NULL,
+  "selection"    VARCHAR(1024)          DEFAULT NULL CHECK ( "selection" <> '' ),
+  "reviewer"     VARCHAR(64)   NOT NULL CHECK ( "reviewer" <> '' ),
+  "comment"      VARCHAR(2048) NOT NULL CHECK ( "comment" <> '' ),
+  "orphaned"     BOOLEAN       NOT NULL DEFAULT FALSE,
+  "resolved_at"  TIMESTAMP              DEFAULT NULL,
+  "created_at"   TIMESTAMP     NOT NULL DEFAULT current_timestamp
+);
+
+CREATE INDEX IF NOT EXISTS "feedback_article_uuid_idx"
+  ON "archive"."feedback" ("article_uuid", "patch", "created_at");
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS "archive"."template"
+(
+  "uuid"          VARCHAR(36) PRIMARY KEY      DEFAULT "extensions"."uuid_generate_v4"(),
+  "name"          VARCHAR(64) UNIQUE  NOT NULL CHECK ("name" <> ''),
+  "title_pattern" VARCHAR(256)        NOT NULL DEFAULT '{title}' CHECK ("title_pattern" <> ''),
+  "summary"       VARCHAR(512)                 DEFAULT NULL CHECK ("summary" <> ''),
+  "content"       VARCHAR(3145728)             DEFAULT NULL CHECK ("content" <> ''),
+  "topic"         VARCHAR(32)                  DEFAULT NULL REFERENCES "archive"."topic" ("id") ON UPDATE CASCADE ON DELETE SET NULL,
+  "created_at"    TIMESTAMP           NOT NULL DEFAULT current_timestamp,
+  "updated_at"    TIMESTAMP           NOT NULL DEFAULT current_timestamp
+);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS "archive"."template_tag"
+(
+  "template_uuid" VARCHAR(36) NOT NULL REFERENCES "archive"."template" ("uuid") ON DELETE CASCADE,
+  "tag_id"        VARCHAR(32) NOT NULL REFERENCES "archive"."tag" ("id") ON UPDATE CASCADE ON DELETE CASCADE,
+  PRIMARY KEY ("template_uuid", "tag_id")
+);
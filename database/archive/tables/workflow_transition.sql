@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "archive"."workflow_transition"
+(
+  "article_uuid"    VARCHAR(36)  NOT NULL REFERENCES "archive"."article" ("uuid") ON DELETE CASCADE,
+  "patch"           BOOLEAN      NOT NULL DEFAULT FALSE,
+  "from_state"      VARCHAR(32)  NOT NULL CHECK ( "from_state" <> '' ),
+  "to_state"        VARCHAR(32)  NOT NULL CHECK ( "to_state" <> '' ),
+  "actor"           VARCHAR(64)  NOT NULL CHECK ( "actor" <> '' ),
+  "comment"         VARCHAR(512)          DEFAULT NULL CHECK ( "comment" <> '' ),
+  "transitioned_at" TIMESTAMP    NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX IF NOT EXISTS "workflow_transition_article_uuid_idx"
+  ON "archive"."workflow_transition" ("article_uuid", "patch", "transitioned_at");
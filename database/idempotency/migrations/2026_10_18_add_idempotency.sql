@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE SCHEMA IF NOT EXISTS "idempotency";
+
+CREATE TABLE IF NOT EXISTS "idempotency"."response"
+(
+    "key"          VARCHAR(255) NOT NULL CHECK ("key" <> ''),
+    "method"       VARCHAR(64)  NOT NULL CHECK ("method" <> ''),
+    "fingerprint"  VARCHAR(64)  NOT NULL CHECK ("fingerprint" <> ''),
+    "status"       SMALLINT              DEFAULT NULL,
+    "content_type" VARCHAR(128)          DEFAULT NULL,
+    "body"         BYTEA                 DEFAULT NULL,
+    "created_at"   TIMESTAMP    NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY ("key", "method")
+);
+
+CREATE INDEX IF NOT EXISTS "response_created_at_idx" ON "idempotency"."response" ("created_at");
+
+COMMIT;
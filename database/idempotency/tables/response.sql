@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS "idempotency"."response"
+(
+  "key"          VARCHAR(255) NOT NULL CHECK ("key" <> ''),
+  "method"       VARCHAR(64)  NOT NULL CHECK ("method" <> ''),
+  "fingerprint"  VARCHAR(64)  NOT NULL CHECK ("fingerprint" <> ''),
+  "status"       SMALLINT              DEFAULT NULL,
+  "content_type" VARCHAR(128)          DEFAULT NULL,
+  "body"         BYTEA                 DEFAULT NULL,
+  "created_at"   TIMESTAMP    NOT NULL DEFAULT current_timestamp,
+  PRIMARY KEY ("key", "method")
+);
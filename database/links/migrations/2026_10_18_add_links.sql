@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE SCHEMA IF NOT EXISTS "links";
+
+CREATE TABLE IF NOT EXISTS "links"."link"
+(
+    "source_type"  VARCHAR(8)    NOT NULL CHECK ("source_type" IN ('article', 'project')),
+    "source_id"    VARCHAR(2048) NOT NULL CHECK ("source_id" <> ''),
+    "source_title" VARCHAR(256)  NOT NULL CHECK ("source_title" <> ''),
+    "url"          VARCHAR(2048) NOT NULL CHECK ("url" <> ''),
+    "status"       SMALLINT               DEFAULT NULL,
+    "error"        VARCHAR(512)           DEFAULT NULL CHECK ("error" <> ''),
+    "broken"       BOOLEAN       NOT NULL DEFAULT FALSE,
+    "checked_at"   TIMESTAMP     NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY ("source_type", "source_id", "url")
+);
+
+COMMIT;
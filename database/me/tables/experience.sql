@@ -11,5 +11,6 @@ CREATE TABLE IF NOT EXISTS "me"."experience"
   "active"           BOOLEAN          NOT NULL DEFAULT FALSE,
   "hidden"           BOOLEAN          NOT NULL DEFAULT FALSE,
   "created_at"       TIMESTAMP        NOT NULL DEFAULT current_timestamp,
-  "updated_at"       TIMESTAMP        NOT NULL DEFAULT current_timestamp
+  "updated_at"       TIMESTAMP        NOT NULL DEFAULT current_timestamp,
+  "version"          INTEGER          NOT NULL DEFAULT 1 CHECK ("version" > 0)
 );
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS "media"."file"
+(
+  "hash"        VARCHAR(64) PRIMARY KEY CHECK ("hash" <> ''),
+  "name"        VARCHAR(256) NOT NULL CHECK ("name" <> ''),
+  "mime_type"   VARCHAR(128) NOT NULL CHECK ("mime_type" <> ''),
+  "size"        BIGINT       NOT NULL CHECK ("size" > 0),
+  "width"       INTEGER               DEFAULT NULL CHECK ("width" > 0),
+  "height"      INTEGER               DEFAULT NULL CHECK ("height" > 0),
+  "alt_text"    VARCHAR(512)          DEFAULT NULL CHECK ("alt_text" <> ''),
+  "uploaded_at" TIMESTAMP    NOT NULL DEFAULT current_timestamp
+);
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE "projects"."project"
+    ADD COLUMN "version" INTEGER NOT NULL DEFAULT 1 CHECK ("version" > 0);
+
+COMMIT;
@@ -21,5 +21,6 @@ CREATE TABLE IF NOT EXISTS "projects"."project"
   "archived"         BOOLEAN              NOT NULL DEFAULT FALSE,
   "finished"         BOOLEAN              NOT NULL DEFAULT FALSE,
   "created_at"       TIMESTAMP            NOT NULL DEFAULT current_timestamp,
-  "updated_at"       TIMESTAMP            NOT NULL DEFAULT current_timestamp
+  "updated_at"       TIMESTAMP            NOT NULL DEFAULT current_timestamp,
+  "version"          INTEGER              NOT NULL DEFAULT 1 CHECK ("version" > 0)
 );
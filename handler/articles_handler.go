@@ -10,13 +10,19 @@ import (
 )
 
 type articlesServiceAPI interface {
-  List(ctx context.Context, filter *transfer.ArticleFilter) (articles []*transfer.Article, err error)
+  List(ctx context.Context, filter *transfer.ArticleFilter) (page *transfer.Page[*transfer.Article], err error)
   Publications(ctx context.Context) (publications []*transfer.Publication, err error)
-  ListHidden(ctx context.Context, filter *transfer.ArticleFilter) (articles []*transfer.Article, err error)
+  ListHidden(ctx context.Context, filter *transfer.ArticleFilter) (page *transfer.Page[*transfer.Article], err error)
   Get(ctx context.Context, request *transfer.ArticleRequest) (article *model.Article, err error)
   GetByID(ctx context.Context, articleUUID string) (article *model.Article, err error)
   Hide(ctx context.Context, articleID string) error
+  HideUntil(ctx context.Context, articleID, until string) error
   Show(ctx context.Context, articleID string) error
+  ShowAt(ctx context.Context, articleID, at string) error
+  MarkOutdated(ctx context.Context, articleID, reason, supersededBy string) error
+  UnmarkOutdated(ctx context.Context, articleID string) error
+  AddSyndication(ctx context.Context, articleID, copyURL, name string) error
+  RemoveSyndication(ctx context.Context, articleID, copyURL string) error
   Amend(ctx context.Context, articleID string) error
   SetSlug(ctx context.Context, articleID, slug string) error
   SetSummary(ctx context.Context, articleID, summary string) error
@@ -38,24 +44,24 @@ func NewArticlesHandler(articles articlesServiceAPI) *ArticlesHandler {
 
 func (h *ArticlesHandler) List(c *gin.Context) {
   filter := getArticleFilter(c)
-  articles, err := h.articles.List(c, filter)
+  page, err := h.articles.List(c, filter)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, articles)
+  writePage(c, page)
 }
 
 func (h *ArticlesHandler) ListHidden(c *gin.Context) {
   filter := getArticleFilter(c)
-  articles, err := h.articles.ListHidden(c, filter)
+  page, err := h.articles.ListHidden(c, filter)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, articles)
+  writePage(c, page)
 }
 
 func (h *ArticlesHandler) Get(c *gin.Context) {
@@ -77,6 +83,15 @@ func (h *ArticlesHandler) Hide(c *gin.Context) {
     return
   }
 
+  if until, embargo := c.GetPostForm("until"); embargo {
+    if err := h.articles.HideUntil(c, article, until); check(err, c.Writer) {
+      return
+    }
+
+    c.Status(http.StatusNoContent)
+    return
+  }
+
   if err := h.articles.Hide(c, article); check(err, c.Writer) {
     return
   }
@@ -92,6 +107,15 @@ func (h *ArticlesHandler) Show(c *gin.Context) {
     return
   }
 
+  if at, scheduled := c.GetPostForm("at"); scheduled {
+    if err := h.articles.ShowAt(c, article, at); check(err, c.Writer) {
+      return
+    }
+
+    c.Status(http.StatusNoContent)
+    return
+  }
+
   if err := h.articles.Show(c, article); check(err, c.Writer) {
     return
   }
@@ -188,6 +212,89 @@ func (h *ArticlesHandler) Remove(c *gin.Context) {
   c.Status(http.StatusNoContent)
 }
 
+func (h *ArticlesHandler) MarkOutdated(c *gin.Context) {
+  article, ok := c.GetPostForm("article_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("article_uuid").Emit(c.Writer)
+    return
+  }
+
+  reason, ok := c.GetPostForm("reason")
+
+  if !ok {
+    problem.NewMissingParameter("reason").Emit(c.Writer)
+    return
+  }
+
+  supersededBy := c.PostForm("superseded_by")
+
+  if err := h.articles.MarkOutdated(c, article, reason, supersededBy); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
+
+func (h *ArticlesHandler) UnmarkOutdated(c *gin.Context) {
+  article, ok := c.GetPostForm("article_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("article_uuid").Emit(c.Writer)
+    return
+  }
+
+  if err := h.articles.UnmarkOutdated(c, article); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
+
+func (h *ArticlesHandler) AddSyndication(c *gin.Context) {
+  article, ok := c.GetPostForm("article_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("article_uuid").Emit(c.Writer)
+    return
+  }
+
+  copyURL, ok := c.GetPostForm("url")
+
+  if !ok {
+    problem.NewMissingParameter("url").Emit(c.Writer)
+    return
+  }
+
+  if err := h.articles.AddSyndication(c, article, copyURL, c.PostForm("name")); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
+
+func (h *ArticlesHandler) RemoveSyndication(c *gin.Context) {
+  article, ok := c.GetPostForm("article_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("article_uuid").Emit(c.Writer)
+    return
+  }
+
+  copyURL, ok := c.GetPostForm("url")
+
+  if !ok {
+    problem.NewMissingParameter("url").Emit(c.Writer)
+    return
+  }
+
+  if err := h.articles.RemoveSyndication(c, article, copyURL); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
+
 func (h *ArticlesHandler) Pin(c *gin.Context) {
   article, ok := c.GetPostForm("article_uuid")
 
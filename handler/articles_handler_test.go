@@ -23,12 +23,12 @@ type articlesServiceMockAPI struct {
   errors    error
 }
 
-func (mock *articlesServiceMockAPI) List(_ context.Context, filter *transfer.ArticleFilter) (articles []*transfer.Article, err error) {
+func (mock *articlesServiceMockAPI) List(_ context.Context, filter *transfer.ArticleFilter) (page *transfer.Page[*transfer.Article], err error) {
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], filter)
   }
 
-  return mock.returns[0].([]*transfer.Article), mock.errors
+  return mock.returns[0].(*transfer.Page[*transfer.Article]), mock.errors
 }
 
 func TestArticlesHandler_Get(t *testing.T) {
@@ -38,12 +38,12 @@ func TestArticlesHandler_Get(t *testing.T) {
   )
 
   request := httptest.NewRequest(method, target, nil)
-  articles := []*transfer.Article{{}, {}, {}}
+  articles := &transfer.Page[*transfer.Article]{Items: []*transfer.Article{{}, {}, {}}}
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
     expectedBody := string(marshal(t, articles))
-    filter := &transfer.ArticleFilter{Page: 1, RPP: 20}
+    filter := &transfer.ArticleFilter{RPP: 20}
 
     s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), filter}, returns: []any{articles}}
 
@@ -67,7 +67,7 @@ func TestArticlesHandler_Get(t *testing.T) {
     expected.Status(expectedStatusCode)
     expected.Detail(expectBodyContains)
 
-    s := &articlesServiceMockAPI{returns: []any{[]*transfer.Article(nil)}, errors: expected}
+    s := &articlesServiceMockAPI{returns: []any{(*transfer.Page[*transfer.Article])(nil)}, errors: expected}
 
     engine := gin.Default()
     engine.GET(target, NewArticlesHandler(s).List)
@@ -87,7 +87,7 @@ func TestArticlesHandler_Get(t *testing.T) {
     expectedStatusCode := http.StatusInternalServerError
     expectBodyContains := "An unexpected error occurred while processing your request"
 
-    s := &articlesServiceMockAPI{returns: []any{[]*transfer.Article(nil)}, errors: unexpected}
+    s := &articlesServiceMockAPI{returns: []any{(*transfer.Page[*transfer.Article])(nil)}, errors: unexpected}
 
     engine := gin.Default()
     engine.GET(target, NewArticlesHandler(s).List)
@@ -103,12 +103,12 @@ func TestArticlesHandler_Get(t *testing.T) {
   })
 }
 
-func (mock *articlesServiceMockAPI) ListHidden(_ context.Context, filter *transfer.ArticleFilter) (articles []*transfer.Article, err error) {
+func (mock *articlesServiceMockAPI) ListHidden(_ context.Context, filter *transfer.ArticleFilter) (page *transfer.Page[*transfer.Article], err error) {
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], filter)
   }
 
-  return mock.returns[0].([]*transfer.Article), mock.errors
+  return mock.returns[0].(*transfer.Page[*transfer.Article]), mock.errors
 }
 
 func TestArticlesHandler_GetHidden(t *testing.T) {
@@ -118,12 +118,12 @@ func TestArticlesHandler_GetHidden(t *testing.T) {
   )
 
   request := httptest.NewRequest(method, target, nil)
-  articles := []*transfer.Article{{}, {}, {}}
+  articles := &transfer.Page[*transfer.Article]{Items: []*transfer.Article{{}, {}, {}}}
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
     expectedBody := string(marshal(t, articles))
-    filter := &transfer.ArticleFilter{Page: 1, RPP: 20}
+    filter := &transfer.ArticleFilter{RPP: 20}
 
     s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), filter}, returns: []any{articles}}
 
@@ -147,7 +147,7 @@ func TestArticlesHandler_GetHidden(t *testing.T) {
     expected.Status(expectedStatusCode)
     expected.Detail(expectBodyContains)
 
-    s := &articlesServiceMockAPI{returns: []any{[]*transfer.Article(nil)}, errors: expected}
+    s := &articlesServiceMockAPI{returns: []any{(*transfer.Page[*transfer.Article])(nil)}, errors: expected}
 
     engine := gin.Default()
     engine.GET(target, NewArticlesHandler(s).ListHidden)
@@ -167,7 +167,7 @@ func TestArticlesHandler_GetHidden(t *testing.T) {
     expectedStatusCode := http.StatusInternalServerError
     expectBodyContains := "An unexpected error occurred while processing your request"
 
-    s := &articlesServiceMockAPI{returns: []any{[]*transfer.Article(nil)}, errors: unexpected}
+    s := &articlesServiceMockAPI{returns: []any{(*transfer.Page[*transfer.Article])(nil)}, errors: unexpected}
 
     engine := gin.Default()
     engine.GET(target, NewArticlesHandler(s).ListHidden)
@@ -350,6 +350,71 @@ func TestArticlesHandler_Hide(t *testing.T) {
   })
 }
 
+func (mock *articlesServiceMockAPI) HideUntil(_ context.Context, articleID, until string) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], articleID)
+    require.Equal(mock.t, mock.arguments[2], until)
+  }
+
+  return mock.errors
+}
+
+func TestArticlesHandler_HideUntil(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.articles.hide"
+  )
+
+  request := httptest.NewRequest(method, target, nil)
+  id := uuid.NewString()
+  until := "2030-01-01"
+
+  _ = request.ParseForm()
+
+  request.PostForm.Add("article_uuid", id)
+  request.PostForm.Add("until", until)
+
+  t.Run("success", func(t *testing.T) {
+    expectedStatusCode := http.StatusNoContent
+
+    s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), id, until}}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).Hide)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Empty(t, recorder.Body)
+    assert.Empty(t, recorder.Result().Cookies())
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    expectedStatusCode := http.StatusBadRequest
+    expectBodyContains := "Expected problem detail."
+
+    expected := &problem.Problem{}
+    expected.Status(expectedStatusCode)
+    expected.Detail(expectBodyContains)
+
+    s := &articlesServiceMockAPI{errors: expected}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).Hide)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), expectBodyContains)
+    assert.Empty(t, recorder.Result().Cookies())
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+}
+
 func (mock *articlesServiceMockAPI) Show(_ context.Context, articleID string) error {
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], articleID)
@@ -432,6 +497,167 @@ func TestArticlesHandler_Show(t *testing.T) {
   })
 }
 
+func (mock *articlesServiceMockAPI) ShowAt(_ context.Context, articleID, at string) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], articleID)
+    require.Equal(mock.t, mock.arguments[2], at)
+  }
+
+  return mock.errors
+}
+
+func TestArticlesHandler_ShowAt(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.articles.show"
+  )
+
+  request := httptest.NewRequest(method, target, nil)
+  id := uuid.NewString()
+  at := "2030-01-01"
+
+  _ = request.ParseForm()
+
+  request.PostForm.Add("article_uuid", id)
+  request.PostForm.Add("at", at)
+
+  t.Run("success", func(t *testing.T) {
+    expectedStatusCode := http.StatusNoContent
+
+    s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), id, at}}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).Show)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Empty(t, recorder.Body)
+    assert.Empty(t, recorder.Result().Cookies())
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    expectedStatusCode := http.StatusBadRequest
+    expectBodyContains := "Expected problem detail."
+
+    expected := &problem.Problem{}
+    expected.Status(expectedStatusCode)
+    expected.Detail(expectBodyContains)
+
+    s := &articlesServiceMockAPI{errors: expected}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).Show)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, expectedStatusCode, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), expectBodyContains)
+    assert.Empty(t, recorder.Result().Cookies())
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+}
+
+func (mock *articlesServiceMockAPI) MarkOutdated(_ context.Context, articleID, reason, supersededBy string) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], articleID)
+    require.Equal(mock.t, mock.arguments[2], reason)
+    require.Equal(mock.t, mock.arguments[3], supersededBy)
+  }
+
+  return mock.errors
+}
+
+func TestArticlesHandler_MarkOutdated(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.articles.mark_outdated"
+  )
+
+  id := uuid.NewString()
+  replacement := uuid.NewString()
+  reason := "Superseded by a newer version."
+
+  t.Run("success", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("article_uuid", id)
+    request.PostForm.Add("reason", reason)
+    request.PostForm.Add("superseded_by", replacement)
+
+    s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), id, reason, replacement}}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).MarkOutdated)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Empty(t, recorder.Body)
+  })
+
+  t.Run("missing reason", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("article_uuid", id)
+
+    s := &articlesServiceMockAPI{}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).MarkOutdated)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "reason")
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+}
+
+func (mock *articlesServiceMockAPI) UnmarkOutdated(_ context.Context, articleID string) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], articleID)
+  }
+
+  return mock.errors
+}
+
+func TestArticlesHandler_UnmarkOutdated(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.articles.unmark_outdated"
+  )
+
+  request := httptest.NewRequest(method, target, nil)
+  id := uuid.NewString()
+
+  _ = request.ParseForm()
+
+  request.PostForm.Add("article_uuid", id)
+
+  t.Run("success", func(t *testing.T) {
+    s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), id}}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).UnmarkOutdated)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Empty(t, recorder.Body)
+  })
+}
+
 func (mock *articlesServiceMockAPI) Amend(_ context.Context, articleID string) error {
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], articleID)
@@ -929,3 +1155,100 @@ func TestArticlesHandler_RemoveTag(t *testing.T) {
     assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
   })
 }
+
+func (mock *articlesServiceMockAPI) AddSyndication(_ context.Context, articleID, copyURL, name string) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], articleID)
+    require.Equal(mock.t, mock.arguments[2], copyURL)
+    require.Equal(mock.t, mock.arguments[3], name)
+  }
+
+  return mock.errors
+}
+
+func TestArticlesHandler_AddSyndication(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.articles.syndication.add"
+  )
+
+  id := uuid.NewString()
+  copyURL := "https://dev.to/fontseca/article"
+
+  t.Run("success", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("article_uuid", id)
+    request.PostForm.Add("url", copyURL)
+    request.PostForm.Add("name", "DEV Community")
+
+    s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), id, copyURL, "DEV Community"}}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).AddSyndication)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Empty(t, recorder.Body)
+  })
+
+  t.Run("missing url", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("article_uuid", id)
+
+    s := &articlesServiceMockAPI{}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).AddSyndication)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "url")
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+}
+
+func (mock *articlesServiceMockAPI) RemoveSyndication(_ context.Context, articleID, copyURL string) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], articleID)
+    require.Equal(mock.t, mock.arguments[2], copyURL)
+  }
+
+  return mock.errors
+}
+
+func TestArticlesHandler_RemoveSyndication(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.articles.syndication.remove"
+  )
+
+  id := uuid.NewString()
+  copyURL := "https://dev.to/fontseca/article"
+
+  t.Run("success", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("article_uuid", id)
+    request.PostForm.Add("url", copyURL)
+
+    s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), id, copyURL}}
+
+    engine := gin.Default()
+    engine.POST(target, NewArticlesHandler(s).RemoveSyndication)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Empty(t, recorder.Body)
+  })
+}
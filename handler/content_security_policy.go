@@ -0,0 +1,68 @@
+package handler
+
+import (
+  "crypto/rand"
+  "encoding/base64"
+  "fontseca.dev/components/pages"
+  "github.com/a-h/templ"
+  "github.com/gin-gonic/gin"
+  "log/slog"
+  "net/http"
+  "net/url"
+  "slices"
+  "strings"
+)
+
+// frameSources are the origins of the trusted embeds, allowed as frames.
+var frameSources = func() string {
+  origins := make([]string, 0, len(pages.TrustedEmbeds))
+
+  for _, prefix := range pages.TrustedEmbeds {
+    u, err := url.Parse(prefix)
+    if nil != err {
+      continue
+    }
+
+    origin := u.Scheme + "://" + u.Host
+    if !slices.Contains(origins, origin) {
+      origins = append(origins, origin)
+    }
+  }
+
+  return strings.Join(origins, " ")
+}()
+
+// ContentSecurityPolicy is a middleware that sets the Content-Security-Policy
+// of rendered pages. Inline scripts run only if they carry the nonce of the
+// request, which templates get with templ.GetNonce from the context of the
+// request; inline event handlers are not allowed at all.
+func ContentSecurityPolicy(c *gin.Context) {
+  var nonce = make([]byte, 16)
+
+  if _, err := rand.Read(nonce); nil != err {
+    slog.Error(err.Error())
+    c.AbortWithStatus(http.StatusInternalServerError)
+    return
+  }
+
+  encoded := base64.StdEncoding.EncodeToString(nonce)
+
+  c.Request = c.Request.WithContext(templ.WithNonce(c.Request.Context(), encoded))
+
+  c.Header("Content-Security-Policy", strings.Join([]string{
+    "default-src 'self'",
+    "script-src 'self' 'nonce-" + encoded + "' https://unpkg.com",
+    "style-src 'self' 'unsafe-inline' https://fonts.googleapis.com https://cdnjs.cloudflare.com https://unpkg.com",
+    "font-src 'self' https://fonts.gstatic.com https://cdnjs.cloudflare.com https://unpkg.com",
+    "img-src 'self' https: data:",
+    "media-src 'self' https:",
+    "frame-src " + frameSources,
+    "connect-src 'self'",
+    "object-src 'none'",
+    "base-uri 'self'",
+    "form-action 'self'",
+    "frame-ancestors 'self'",
+  }, "; "))
+
+  c.Next()
+}
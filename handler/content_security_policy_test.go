@@ -0,0 +1,36 @@
+package handler
+
+import (
+  "github.com/a-h/templ"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+func TestContentSecurityPolicy(t *testing.T) {
+  var nonces []string
+
+  engine := gin.Default()
+  engine.GET("/", ContentSecurityPolicy, func(c *gin.Context) {
+    nonces = append(nonces, templ.GetNonce(c.Request.Context()))
+    c.Status(http.StatusOK)
+  })
+
+  for range 2 {
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+    policy := recorder.Header().Get("Content-Security-Policy")
+    nonce := nonces[len(nonces)-1]
+
+    assert.NotEmpty(t, nonce)
+    assert.Contains(t, policy, "script-src 'self' 'nonce-"+nonce+"'")
+    assert.Contains(t, policy, "frame-src https://www.youtube.com https://www.youtube-nocookie.com")
+    assert.Contains(t, policy, "object-src 'none'")
+  }
+
+  assert.NotEqual(t, nonces[0], nonces[1])
+}
@@ -8,19 +8,27 @@ import (
   "github.com/gin-gonic/gin"
   "github.com/google/uuid"
   "net/http"
+  "strconv"
 )
 
 type draftsServiceAPI interface {
   Draft(ctx context.Context, creation *transfer.ArticleCreation) (insertedUUID uuid.UUID, err error)
-  Publish(ctx context.Context, draftUUID string) error
-  List(ctx context.Context, filter *transfer.ArticleFilter) (drafts []*transfer.Article, err error)
-  GetByLink(ctx context.Context, link string) (article *model.Article, err error)
+  Publish(ctx context.Context, draftUUID string, force bool) error
+  Lint(ctx context.Context, draftUUID string) (violations []*transfer.LintViolation, err error)
+  List(ctx context.Context, filter *transfer.ArticleFilter) (page *transfer.Page[*transfer.Article], err error)
+  GetByLink(ctx context.Context, link, token string) (article *model.Article, err error)
+  Unlock(ctx context.Context, link, password string) (token string, err error)
   Get(ctx context.Context, draftUUID string) (draft *model.Article, err error)
   AddTag(ctx context.Context, draftUUID, tagID string) error
   RemoveTag(ctx context.Context, draftUUID, tagID string) error
-  Share(ctx context.Context, draftUUID string) (link string, err error)
+  Share(ctx context.Context, draftUUID string, creation *transfer.ShareableLinkCreation) (link *model.ShareableLink, err error)
+  Links(ctx context.Context, draftUUID string) (links []*model.ShareableLink, err error)
+  RevokeLink(ctx context.Context, linkUUID string) error
+  ExtendLink(ctx context.Context, linkUUID string, days int) error
   Discard(ctx context.Context, draftUUID string) error
   Revise(ctx context.Context, draftUUID string, revision *transfer.ArticleRevision) error
+  Transition(ctx context.Context, draftUUID string, transition *transfer.WorkflowTransition) error
+  History(ctx context.Context, draftUUID string) (history *model.WorkflowHistory, err error)
 }
 
 type DraftsHandler struct {
@@ -59,22 +67,49 @@ func (h *DraftsHandler) Publish(c *gin.Context) {
     return
   }
 
-  if err := h.drafts.Publish(c, draft); check(err, c.Writer) {
+  var force bool
+
+  if value, ok := c.GetPostForm("force"); ok {
+    var err error
+    if force, err = strconv.ParseBool(value); nil != err {
+      problem.NewUnparsableValue("bool", "force", value).Emit(c.Writer)
+      return
+    }
+  }
+
+  if err := h.drafts.Publish(c, draft, force); check(err, c.Writer) {
     return
   }
 
   c.Status(http.StatusNoContent)
 }
 
+func (h *DraftsHandler) Lint(c *gin.Context) {
+  draft, ok := c.GetQuery("draft_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("draft_uuid").Emit(c.Writer)
+    return
+  }
+
+  violations, err := h.drafts.Lint(c, draft)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusOK, violations)
+}
+
 func (h *DraftsHandler) List(c *gin.Context) {
   filter := getArticleFilter(c)
-  drafts, err := h.drafts.List(c, filter)
+  page, err := h.drafts.List(c, filter)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, drafts)
+  writePage(c, page)
 }
 
 func (h *DraftsHandler) Get(c *gin.Context) {
@@ -85,6 +120,7 @@ func (h *DraftsHandler) Get(c *gin.Context) {
     return
   }
 
+  setETag(c, draft.Version)
   c.JSON(http.StatusOK, draft)
 }
 
@@ -140,13 +176,84 @@ func (h *DraftsHandler) Share(c *gin.Context) {
     return
   }
 
-  link, err := h.drafts.Share(c, draft)
+  var creation transfer.ShareableLinkCreation
+
+  if err := bindPostForm(c, &creation); check(err, c.Writer) {
+    return
+  }
+
+  if err := validateStruct(&creation); check(err, c.Writer) {
+    return
+  }
+
+  link, err := h.drafts.Share(c, draft, &creation)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusOK, link)
+}
+
+func (h *DraftsHandler) Links(c *gin.Context) {
+  draft, ok := c.GetQuery("draft_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("draft_uuid").Emit(c.Writer)
+    return
+  }
+
+  links, err := h.drafts.Links(c, draft)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, gin.H{"shareable_link": link})
+  c.JSON(http.StatusOK, links)
+}
+
+func (h *DraftsHandler) RevokeLink(c *gin.Context) {
+  link, ok := c.GetPostForm("link_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("link_uuid").Emit(c.Writer)
+    return
+  }
+
+  if err := h.drafts.RevokeLink(c, link); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
+
+func (h *DraftsHandler) ExtendLink(c *gin.Context) {
+  link, ok := c.GetPostForm("link_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("link_uuid").Emit(c.Writer)
+    return
+  }
+
+  value, ok := c.GetPostForm("days")
+
+  if !ok {
+    problem.NewMissingParameter("days").Emit(c.Writer)
+    return
+  }
+
+  days, err := strconv.Atoi(value)
+
+  if nil != err {
+    problem.NewUnparsableValue("int", "days", value).Emit(c.Writer)
+    return
+  }
+
+  if err = h.drafts.ExtendLink(c, link, days); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
 }
 
 func (h *DraftsHandler) Discard(c *gin.Context) {
@@ -178,6 +285,10 @@ func (h *DraftsHandler) Revise(c *gin.Context) {
     return
   }
 
+  if err := ifMatch(c, &revision.Version); check(err, c.Writer) {
+    return
+  }
+
   if err := validateStruct(&revision); check(err, c.Writer) {
     return
   }
@@ -188,3 +299,45 @@ func (h *DraftsHandler) Revise(c *gin.Context) {
 
   c.Status(http.StatusNoContent)
 }
+
+func (h *DraftsHandler) Transition(c *gin.Context) {
+  draft, ok := c.GetPostForm("draft_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("draft_uuid").Emit(c.Writer)
+    return
+  }
+
+  var transition transfer.WorkflowTransition
+
+  if err := bindPostForm(c, &transition); check(err, c.Writer) {
+    return
+  }
+
+  if err := validateStruct(&transition); check(err, c.Writer) {
+    return
+  }
+
+  if err := h.drafts.Transition(c, draft, &transition); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
+
+func (h *DraftsHandler) History(c *gin.Context) {
+  draft, ok := c.GetQuery("draft_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("draft_uuid").Emit(c.Writer)
+    return
+  }
+
+  history, err := h.drafts.History(c, draft)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusOK, history)
+}
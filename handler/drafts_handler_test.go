@@ -12,6 +12,8 @@ import (
   "github.com/stretchr/testify/require"
   "net/http"
   "net/http/httptest"
+  "net/url"
+  "strings"
   "testing"
 )
 
@@ -112,9 +114,10 @@ func TestDraftsHandler_Start(t *testing.T) {
   })
 }
 
-func (mock *draftsServiceMockAPI) Publish(_ context.Context, draftUUID string) error {
+func (mock *draftsServiceMockAPI) Publish(_ context.Context, draftUUID string, force bool) error {
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], draftUUID)
+    require.Equal(mock.t, mock.arguments[2], force)
   }
 
   return mock.errors
@@ -136,7 +139,7 @@ func TestDraftsHandler_Publish(t *testing.T) {
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusNoContent
 
-    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id}}
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id, false}}
 
     engine := gin.Default()
     engine.POST(target, NewDraftsHandler(s).Publish)
@@ -150,6 +153,36 @@ func TestDraftsHandler_Publish(t *testing.T) {
     assert.Empty(t, recorder.Result().Cookies())
   })
 
+  t.Run("forced", func(t *testing.T) {
+    forced := httptest.NewRequest(method, target, strings.NewReader(url.Values{"draft_uuid": {id}, "force": {"true"}}.Encode()))
+    forced.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id, true}}
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(s).Publish)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, forced)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+  })
+
+  t.Run("unparsable force", func(t *testing.T) {
+    forced := httptest.NewRequest(method, target, strings.NewReader(url.Values{"draft_uuid": {id}, "force": {"lorem"}}.Encode()))
+    forced.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(&draftsServiceMockAPI{}).Publish)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, forced)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+  })
+
   t.Run("expected problem detail", func(t *testing.T) {
     expectedStatusCode := http.StatusBadRequest
     expectBodyContains := "Expected problem detail."
@@ -194,12 +227,55 @@ func TestDraftsHandler_Publish(t *testing.T) {
   })
 }
 
-func (mock *draftsServiceMockAPI) List(_ context.Context, filter *transfer.ArticleFilter) (drafts []*transfer.Article, err error) {
+func (mock *draftsServiceMockAPI) Lint(_ context.Context, draftUUID string) ([]*transfer.LintViolation, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], draftUUID)
+  }
+
+  return mock.returns[0].([]*transfer.LintViolation), mock.errors
+}
+
+func TestDraftsHandler_Lint(t *testing.T) {
+  const (
+    method = http.MethodGet
+    target = "/archive.drafts.lint"
+  )
+
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    violations := []*transfer.LintViolation{{Rule: "cover", Severity: "error", Message: "The article has no cover."}}
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id}, returns: []any{violations}}
+
+    engine := gin.Default()
+    engine.GET(target, NewDraftsHandler(s).Lint)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?draft_uuid="+id, nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, string(marshal(t, violations)), recorder.Body.String())
+  })
+
+  t.Run("missing draft_uuid", func(t *testing.T) {
+    engine := gin.Default()
+    engine.GET(target, NewDraftsHandler(&draftsServiceMockAPI{}).Lint)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
+
+func (mock *draftsServiceMockAPI) List(_ context.Context, filter *transfer.ArticleFilter) (page *transfer.Page[*transfer.Article], err error) {
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], filter)
   }
 
-  return mock.returns[0].([]*transfer.Article), mock.errors
+  return mock.returns[0].(*transfer.Page[*transfer.Article]), mock.errors
 }
 
 func TestDraftsHandler_Get(t *testing.T) {
@@ -209,12 +285,12 @@ func TestDraftsHandler_Get(t *testing.T) {
   )
 
   request := httptest.NewRequest(method, target, nil)
-  drafts := []*transfer.Article{{}, {}, {}}
+  drafts := &transfer.Page[*transfer.Article]{Items: []*transfer.Article{{}, {}, {}}}
 
   t.Run("success without search", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
     expectedBody := string(marshal(t, drafts))
-    filter := &transfer.ArticleFilter{Page: 1, RPP: 20}
+    filter := &transfer.ArticleFilter{RPP: 20}
 
     s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), filter}, returns: []any{drafts}}
 
@@ -238,7 +314,7 @@ func TestDraftsHandler_Get(t *testing.T) {
     expected.Status(expectedStatusCode)
     expected.Detail(expectBodyContains)
 
-    s := &draftsServiceMockAPI{returns: []any{[]*transfer.Article(nil)}, errors: expected}
+    s := &draftsServiceMockAPI{returns: []any{(*transfer.Page[*transfer.Article])(nil)}, errors: expected}
 
     engine := gin.Default()
     engine.GET(target, NewDraftsHandler(s).List)
@@ -258,7 +334,7 @@ func TestDraftsHandler_Get(t *testing.T) {
     expectedStatusCode := http.StatusInternalServerError
     expectBodyContains := "An unexpected error occurred while processing your request"
 
-    s := &draftsServiceMockAPI{returns: []any{[]*transfer.Article(nil)}, errors: unexpected}
+    s := &draftsServiceMockAPI{returns: []any{(*transfer.Page[*transfer.Article])(nil)}, errors: unexpected}
 
     engine := gin.Default()
     engine.GET(target, NewDraftsHandler(s).List)
@@ -292,7 +368,8 @@ func TestDraftsHandler_GetByID(t *testing.T) {
   id := uuid.NewString()
 
   draft := &model.Article{
-    UUID: uuid.MustParse(id),
+    UUID:    uuid.MustParse(id),
+    Version: 3,
   }
 
   request.URL.RawQuery = request.URL.RawQuery + "&draft_uuid=" + id
@@ -312,6 +389,7 @@ func TestDraftsHandler_GetByID(t *testing.T) {
 
     assert.Equal(t, expectedStatusCode, recorder.Code)
     assert.Equal(t, expectedBody, recorder.Body.String())
+    assert.Equal(t, `"3"`, recorder.Header().Get("ETag"))
     assert.Empty(t, recorder.Result().Cookies())
   })
 
@@ -529,19 +607,19 @@ func TestDraftsHandler_RemoveTag(t *testing.T) {
   })
 }
 
-func (mock *draftsServiceMockAPI) Share(_ context.Context, draftUUID string) (link string, err error) {
+func (mock *draftsServiceMockAPI) Share(_ context.Context, draftUUID string, creation *transfer.ShareableLinkCreation) (*model.ShareableLink, error) {
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], draftUUID)
+    require.Equal(mock.t, mock.arguments[2], creation)
   }
 
-  return mock.returns[0].(string), mock.errors
+  return mock.returns[0].(*model.ShareableLink), mock.errors
 }
 
 func TestDraftsHandler_Share(t *testing.T) {
   const (
     method = http.MethodPost
     target = "/archive.drafts.share"
-    link   = "/link/to/draft"
   )
 
   request := httptest.NewRequest(method, target, nil)
@@ -550,12 +628,17 @@ func TestDraftsHandler_Share(t *testing.T) {
   _ = request.ParseForm()
 
   request.PostForm.Add("draft_uuid", id)
+  request.PostForm.Add("label", "Jane")
+  request.PostForm.Add("expires_in", "30")
+  request.PostForm.Add("password", "correct horse")
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
-    expectedBody := string(marshal(t, gin.H{"shareable_link": link}))
+    link := &model.ShareableLink{UUID: uuid.NewString(), Label: "Jane", Link: "https://fontseca.dev/archive/sharing/6d7f", Protected: true}
+    expectedBody := string(marshal(t, link))
+    creation := &transfer.ShareableLinkCreation{Label: "Jane", ExpiresIn: 30, Password: "correct horse"}
 
-    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id}, returns: []any{link}}
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id, creation}, returns: []any{link}}
 
     engine := gin.Default()
     engine.POST(target, NewDraftsHandler(s).Share)
@@ -577,7 +660,7 @@ func TestDraftsHandler_Share(t *testing.T) {
     expected.Status(expectedStatusCode)
     expected.Detail(expectBodyContains)
 
-    s := &draftsServiceMockAPI{returns: []any{"about:blank"}, errors: expected}
+    s := &draftsServiceMockAPI{returns: []any{(*model.ShareableLink)(nil)}, errors: expected}
 
     engine := gin.Default()
     engine.POST(target, NewDraftsHandler(s).Share)
@@ -597,7 +680,7 @@ func TestDraftsHandler_Share(t *testing.T) {
     expectedStatusCode := http.StatusInternalServerError
     expectBodyContains := "An unexpected error occurred while processing your request"
 
-    s := &draftsServiceMockAPI{returns: []any{"about:blank"}, errors: unexpected}
+    s := &draftsServiceMockAPI{returns: []any{(*model.ShareableLink)(nil)}, errors: unexpected}
 
     engine := gin.Default()
     engine.POST(target, NewDraftsHandler(s).Share)
@@ -741,6 +824,54 @@ func TestDraftsHandler_Revise(t *testing.T) {
     assert.Empty(t, recorder.Result().Cookies())
   })
 
+  t.Run("success with an entity tag", func(t *testing.T) {
+    guarded := *revision
+    guarded.Version = 3
+
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id, &guarded}}
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(s).Revise)
+
+    request := request.Clone(request.Context())
+    request.Header.Set("If-Match", `W/"3"`)
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+  })
+
+  t.Run("unparseable entity tag", func(t *testing.T) {
+    s := &draftsServiceMockAPI{}
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(s).Revise)
+
+    request := request.Clone(request.Context())
+    request.Header.Set("If-Match", `"three"`)
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "If-Match")
+  })
+
+  t.Run("stale version", func(t *testing.T) {
+    s := &draftsServiceMockAPI{errors: problem.NewConflict(id, "draft", 4)}
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(s).Revise)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), `"current_version":4`)
+  })
+
   t.Run("expected problem detail", func(t *testing.T) {
     expectedStatusCode := http.StatusBadRequest
     expectBodyContains := "Expected problem detail."
@@ -784,3 +915,313 @@ func TestDraftsHandler_Revise(t *testing.T) {
     assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
   })
 }
+
+func (mock *draftsServiceMockAPI) Transition(_ context.Context, draftUUID string, transition *transfer.WorkflowTransition) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], draftUUID)
+    require.Equal(mock.t, mock.arguments[2], transition)
+  }
+
+  return mock.errors
+}
+
+func TestDraftsHandler_Transition(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.drafts.transition"
+  )
+
+  transition := &transfer.WorkflowTransition{State: "in_review", Actor: "jane", Comment: "Ready."}
+  id := uuid.NewString()
+
+  request := httptest.NewRequest(method, target, nil)
+  _ = request.ParseForm()
+
+  request.PostForm.Add("draft_uuid", id)
+  request.PostForm.Add("state", transition.State)
+  request.PostForm.Add("actor", transition.Actor)
+  request.PostForm.Add("comment", transition.Comment)
+
+  t.Run("success", func(t *testing.T) {
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id, transition}}
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(s).Transition)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Empty(t, recorder.Body)
+  })
+
+  t.Run("missing draft_uuid", func(t *testing.T) {
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(&draftsServiceMockAPI{}).Transition)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+
+  t.Run("missing actor", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+
+    request.PostForm.Add("draft_uuid", id)
+    request.PostForm.Add("state", transition.State)
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(&draftsServiceMockAPI{}).Transition)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "Actor")
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    expected := &problem.Problem{}
+    expected.Status(http.StatusConflict)
+    expected.Detail("Expected problem detail.")
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(&draftsServiceMockAPI{errors: expected}).Transition)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "Expected problem detail.")
+  })
+}
+
+func (mock *draftsServiceMockAPI) History(_ context.Context, draftUUID string) (*model.WorkflowHistory, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], draftUUID)
+  }
+
+  return mock.returns[0].(*model.WorkflowHistory), mock.errors
+}
+
+func TestDraftsHandler_History(t *testing.T) {
+  const (
+    method = http.MethodGet
+    target = "/archive.drafts.history"
+  )
+
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    history := &model.WorkflowHistory{
+      State:       "in_review",
+      Transitions: []*model.WorkflowTransition{{From: "writing", To: "in_review", Actor: "jane"}},
+    }
+
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id}, returns: []any{history}}
+
+    engine := gin.Default()
+    engine.GET(target, NewDraftsHandler(s).History)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?draft_uuid="+id, nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, string(marshal(t, history)), recorder.Body.String())
+  })
+
+  t.Run("missing draft_uuid", func(t *testing.T) {
+    engine := gin.Default()
+    engine.GET(target, NewDraftsHandler(&draftsServiceMockAPI{}).History)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
+
+func (mock *draftsServiceMockAPI) Links(_ context.Context, draftUUID string) ([]*model.ShareableLink, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], draftUUID)
+  }
+
+  return mock.returns[0].([]*model.ShareableLink), mock.errors
+}
+
+func TestDraftsHandler_Links(t *testing.T) {
+  const (
+    method = http.MethodGet
+    target = "/archive.drafts.links.list"
+  )
+
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    links := []*model.ShareableLink{{UUID: uuid.NewString(), Label: "Jane"}, {UUID: uuid.NewString(), Label: "John", Protected: true}}
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id}, returns: []any{links}}
+
+    engine := gin.Default()
+    engine.GET(target, NewDraftsHandler(s).Links)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?draft_uuid="+id, nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, string(marshal(t, links)), recorder.Body.String())
+  })
+
+  t.Run("missing draft_uuid", func(t *testing.T) {
+    engine := gin.Default()
+    engine.GET(target, NewDraftsHandler(&draftsServiceMockAPI{}).Links)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
+
+func (mock *draftsServiceMockAPI) RevokeLink(_ context.Context, linkUUID string) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], linkUUID)
+  }
+
+  return mock.errors
+}
+
+func TestDraftsHandler_RevokeLink(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.drafts.links.revoke"
+  )
+
+  id := uuid.NewString()
+
+  request := httptest.NewRequest(method, target, nil)
+  _ = request.ParseForm()
+
+  request.PostForm.Add("link_uuid", id)
+
+  t.Run("success", func(t *testing.T) {
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id}}
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(s).RevokeLink)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Empty(t, recorder.Body)
+  })
+
+  t.Run("missing link_uuid", func(t *testing.T) {
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(&draftsServiceMockAPI{}).RevokeLink)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    expected := problem.NewNotFound(id, "shareable link")
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(&draftsServiceMockAPI{errors: expected}).RevokeLink)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNotFound, recorder.Code)
+    assert.Contains(t, recorder.Result().Header.Get("Content-Type"), "application/problem+json")
+  })
+}
+
+func (mock *draftsServiceMockAPI) ExtendLink(_ context.Context, linkUUID string, days int) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], linkUUID)
+    require.Equal(mock.t, mock.arguments[2], days)
+  }
+
+  return mock.errors
+}
+
+func TestDraftsHandler_ExtendLink(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.drafts.links.extend"
+  )
+
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+
+    request.PostForm.Add("link_uuid", id)
+    request.PostForm.Add("days", "14")
+
+    s := &draftsServiceMockAPI{t: t, arguments: []any{context.Background(), id, 14}}
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(s).ExtendLink)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    assert.Empty(t, recorder.Body)
+  })
+
+  t.Run("missing days", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+
+    request.PostForm.Add("link_uuid", id)
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(&draftsServiceMockAPI{}).ExtendLink)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "days")
+  })
+
+  t.Run("unparseable days", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+
+    request.PostForm.Add("link_uuid", id)
+    request.PostForm.Add("days", "a week")
+
+    engine := gin.Default()
+    engine.POST(target, NewDraftsHandler(&draftsServiceMockAPI{}).ExtendLink)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "days")
+  })
+}
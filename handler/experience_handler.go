@@ -40,7 +40,11 @@ func (h *ExperienceHandler) List(c *gin.Context) {
     }
     return
   }
-  c.JSON(http.StatusOK, e)
+  page, err := paginate(c, e, func(e *model.Experience) string { return e.UUID.String() })
+  if check(err, c.Writer) {
+    return
+  }
+  writePage(c, page)
 }
 
 func (h *ExperienceHandler) ListHidden(c *gin.Context) {
@@ -54,7 +58,11 @@ func (h *ExperienceHandler) ListHidden(c *gin.Context) {
     }
     return
   }
-  c.JSON(http.StatusOK, e)
+  page, err := paginate(c, e, func(e *model.Experience) string { return e.UUID.String() })
+  if check(err, c.Writer) {
+    return
+  }
+  writePage(c, page)
 }
 
 func (h *ExperienceHandler) Get(c *gin.Context) {
@@ -69,6 +77,7 @@ func (h *ExperienceHandler) Get(c *gin.Context) {
     }
     return
   }
+  setETag(c, e.Version)
   c.JSON(http.StatusOK, e)
 }
 
@@ -103,6 +112,10 @@ func (h *ExperienceHandler) Set(c *gin.Context) {
     return
   }
 
+  if err := ifMatch(c, &update.Version); check(err, c.Writer) {
+    return
+  }
+
   if err := validateStruct(&update); check(err, c.Writer) {
     return
   }
@@ -42,7 +42,7 @@ func TestExperienceHandler_List(t *testing.T) {
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, e)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, &transfer.Page[*model.Experience]{Items: e})), recorder.Body.String())
   })
 }
 
@@ -60,7 +60,7 @@ func TestExperienceHandler_ListHidden(t *testing.T) {
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, e)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, &transfer.Page[*model.Experience]{Items: e})), recorder.Body.String())
   })
 }
 
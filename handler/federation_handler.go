@@ -0,0 +1,109 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "io"
+  "net/http"
+)
+
+// maxInboxBodySize is the maximum size of an activity posted to the inbox.
+const maxInboxBodySize = 1 << 20
+
+type federationServiceAPI interface {
+  WebFinger(resource string) (*transfer.WebFinger, error)
+  Actor() (*transfer.Actor, error)
+  Outbox(ctx context.Context, page bool, cursor string) (*transfer.OrderedCollection, error)
+  Followers(ctx context.Context) (*transfer.OrderedCollection, error)
+  Article(ctx context.Context, articleID string) (*transfer.ArticleObject, error)
+  Receive(ctx context.Context, r *http.Request, body []byte) error
+}
+
+type FederationHandler struct {
+  federation federationServiceAPI
+}
+
+func NewFederationHandler(federation federationServiceAPI) *FederationHandler {
+  return &FederationHandler{federation: federation}
+}
+
+// writeActivity writes an ActivityPub document with the media type
+// expected by fediverse servers.
+func writeActivity(c *gin.Context, document any) {
+  c.Header("Content-Type", "application/activity+json; charset=utf-8")
+  c.JSON(http.StatusOK, document)
+}
+
+func (h *FederationHandler) WebFinger(c *gin.Context) {
+  resource, ok := c.GetQuery("resource")
+
+  if !ok {
+    problem.NewMissingParameter("resource").Emit(c.Writer)
+    return
+  }
+
+  webfinger, err := h.federation.WebFinger(resource)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.Header("Content-Type", "application/jrd+json; charset=utf-8")
+  c.JSON(http.StatusOK, webfinger)
+}
+
+func (h *FederationHandler) Actor(c *gin.Context) {
+  actor, err := h.federation.Actor()
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writeActivity(c, actor)
+}
+
+func (h *FederationHandler) Outbox(c *gin.Context) {
+  outbox, err := h.federation.Outbox(c, "true" == c.Query("page"), c.Query("cursor"))
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writeActivity(c, outbox)
+}
+
+func (h *FederationHandler) Followers(c *gin.Context) {
+  followers, err := h.federation.Followers(c)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writeActivity(c, followers)
+}
+
+func (h *FederationHandler) Article(c *gin.Context) {
+  article, err := h.federation.Article(c, c.Param("article_uuid"))
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writeActivity(c, article)
+}
+
+func (h *FederationHandler) Inbox(c *gin.Context) {
+  body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxInboxBodySize))
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  if err = h.federation.Receive(c, c.Request, body); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusAccepted)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+)
+
+type federationServiceMockAPI struct {
+  federationServiceAPI
+  t         *testing.T
+  returns   []any
+  arguments []any
+  errors    error
+}
+
+func (mock *federationServiceMockAPI) WebFinger(resource string) (*transfer.WebFinger, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[0], resource)
+  }
+
+  return mock.returns[0].(*transfer.WebFinger), mock.errors
+}
+
+func TestFederationHandler_WebFinger(t *testing.T) {
+  const (
+    method = http.MethodGet
+    target = "/.well-known/webfinger"
+  )
+
+  t.Run("success", func(t *testing.T) {
+    resource := "acct:archive@fontseca.dev"
+    webfinger := &transfer.WebFinger{Subject: resource}
+    s := &federationServiceMockAPI{t: t, arguments: []any{resource}, returns: []any{webfinger}}
+
+    engine := gin.Default()
+    engine.GET(target, NewFederationHandler(s).WebFinger)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?resource="+resource, nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Contains(t, recorder.Header().Get("Content-Type"), "application/jrd+json")
+    assert.Contains(t, recorder.Body.String(), resource)
+  })
+
+  t.Run("missing resource", func(t *testing.T) {
+    engine := gin.Default()
+    engine.GET(target, NewFederationHandler(&federationServiceMockAPI{}).WebFinger)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "resource")
+  })
+}
+
+func (mock *federationServiceMockAPI) Receive(_ context.Context, _ *http.Request, body []byte) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[0], string(body))
+  }
+
+  return mock.errors
+}
+
+func TestFederationHandler_Inbox(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/activitypub/inbox"
+  )
+
+  activity := `{"type":"Follow"}`
+  s := &federationServiceMockAPI{t: t, arguments: []any{activity}}
+
+  engine := gin.Default()
+  engine.POST(target, NewFederationHandler(s).Inbox)
+
+  recorder := httptest.NewRecorder()
+  request := httptest.NewRequest(method, target, strings.NewReader(activity))
+  request.Header.Set("Content-Type", "application/activity+json")
+
+  engine.ServeHTTP(recorder, request)
+
+  assert.Equal(t, http.StatusAccepted, recorder.Code)
+}
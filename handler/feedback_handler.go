@@ -0,0 +1,80 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+)
+
+type feedbackServiceAPI interface {
+  Leave(ctx context.Context, link, token string, creation *transfer.FeedbackCreation) (feedbackUUID string, err error)
+  List(ctx context.Context, draftUUID string) (feedback []*model.Feedback, err error)
+  Resolve(ctx context.Context, feedbackUUID string) error
+}
+
+type FeedbackHandler struct {
+  feedback feedbackServiceAPI
+}
+
+func NewFeedbackHandler(feedback feedbackServiceAPI) *FeedbackHandler {
+  return &FeedbackHandler{feedback: feedback}
+}
+
+// Leave stores the feedback of a reviewer on the draft or the patch
+// shared by the link in the 'hash' parameter of the path, unlocked by
+// the token in the cookie of the link, if it is protected.
+func (h *FeedbackHandler) Leave(c *gin.Context) {
+  var creation transfer.FeedbackCreation
+
+  if err := bindPostForm(c, &creation); check(err, c.Writer) {
+    return
+  }
+
+  if err := validateStruct(&creation); check(err, c.Writer) {
+    return
+  }
+
+  token, _ := c.Cookie(shareableLinkCookie)
+  insertedUUID, err := h.feedback.Leave(c, "/archive/sharing/"+c.Param("hash"), token, &creation)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusCreated, gin.H{"feedback_uuid": insertedUUID})
+}
+
+func (h *FeedbackHandler) List(c *gin.Context) {
+  draft, ok := c.GetQuery("draft_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("draft_uuid").Emit(c.Writer)
+    return
+  }
+
+  feedback, err := h.feedback.List(c, draft)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusOK, feedback)
+}
+
+func (h *FeedbackHandler) Resolve(c *gin.Context) {
+  feedback, ok := c.GetPostForm("feedback_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("feedback_uuid").Emit(c.Writer)
+    return
+  }
+
+  if err := h.feedback.Resolve(c, feedback); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+type feedbackServiceMockAPI struct {
+  feedbackServiceAPI
+  t         *testing.T
+  returns   []any
+  arguments []any
+  errors    error
+}
+
+func (mock *feedbackServiceMockAPI) Leave(_ context.Context, link, token string, creation *transfer.FeedbackCreation) (string, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], link)
+    require.Equal(mock.t, mock.arguments[2], token)
+    require.Equal(mock.t, mock.arguments[3], creation)
+  }
+
+  return mock.returns[0].(string), mock.errors
+}
+
+func TestFeedbackHandler_Leave(t *testing.T) {
+  const (
+    method = http.MethodPost
+    route  = "/archive/sharing/:hash/feedback"
+    target = "/archive/sharing/6d7f/feedback"
+  )
+
+  creation := &transfer.FeedbackCreation{Anchor: "p-2", Selection: "toolchain", Reviewer: "Jane", Comment: "Which version?"}
+  id := uuid.NewString()
+
+  request := httptest.NewRequest(method, target, nil)
+  _ = request.ParseForm()
+
+  request.PostForm.Add("anchor", creation.Anchor)
+  request.PostForm.Add("selection", creation.Selection)
+  request.PostForm.Add("reviewer", creation.Reviewer)
+  request.PostForm.Add("comment", creation.Comment)
+  request.AddCookie(&http.Cookie{Name: shareableLinkCookie, Value: "token"})
+
+  t.Run("success", func(t *testing.T) {
+    s := &feedbackServiceMockAPI{t: t, arguments: []any{context.Background(), "/archive/sharing/6d7f", "token", creation}, returns: []any{id}}
+
+    engine := gin.Default()
+    engine.POST(route, NewFeedbackHandler(s).Leave)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusCreated, recorder.Code)
+    assert.Equal(t, string(marshal(t, gin.H{"feedback_uuid": id})), recorder.Body.String())
+  })
+
+  t.Run("missing comment", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+
+    request.PostForm.Add("anchor", creation.Anchor)
+    request.PostForm.Add("reviewer", creation.Reviewer)
+
+    engine := gin.Default()
+    engine.POST(route, NewFeedbackHandler(&feedbackServiceMockAPI{}).Leave)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    expected := &problem.Problem{}
+    expected.Status(http.StatusGone)
+    expected.Detail("Expected problem detail.")
+
+    engine := gin.Default()
+    engine.POST(route, NewFeedbackHandler(&feedbackServiceMockAPI{returns: []any{""}, errors: expected}).Leave)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusGone, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "Expected problem detail.")
+  })
+}
+
+func (mock *feedbackServiceMockAPI) List(_ context.Context, draftUUID string) ([]*model.Feedback, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], draftUUID)
+  }
+
+  return mock.returns[0].([]*model.Feedback), mock.errors
+}
+
+func TestFeedbackHandler_List(t *testing.T) {
+  const (
+    method = http.MethodGet
+    target = "/archive.drafts.feedback.list"
+  )
+
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    feedback := []*model.Feedback{{UUID: uuid.NewString(), Anchor: "p-2", Reviewer: "Jane", Comment: "Which version?"}}
+    s := &feedbackServiceMockAPI{t: t, arguments: []any{context.Background(), id}, returns: []any{feedback}}
+
+    engine := gin.Default()
+    engine.GET(target, NewFeedbackHandler(s).List)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?draft_uuid="+id, nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, string(marshal(t, feedback)), recorder.Body.String())
+  })
+
+  t.Run("missing draft_uuid", func(t *testing.T) {
+    engine := gin.Default()
+    engine.GET(target, NewFeedbackHandler(&feedbackServiceMockAPI{}).List)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
+
+func (mock *feedbackServiceMockAPI) Resolve(_ context.Context, feedbackUUID string) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], feedbackUUID)
+  }
+
+  return mock.errors
+}
+
+func TestFeedbackHandler_Resolve(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.drafts.feedback.resolve"
+  )
+
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    request := httptest.NewRequest(method, target, nil)
+    _ = request.ParseForm()
+    request.PostForm.Add("feedback_uuid", id)
+
+    engine := gin.Default()
+    engine.POST(target, NewFeedbackHandler(&feedbackServiceMockAPI{t: t, arguments: []any{context.Background(), id}}).Resolve)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+  })
+
+  t.Run("missing feedback_uuid", func(t *testing.T) {
+    engine := gin.Default()
+    engine.POST(target, NewFeedbackHandler(&feedbackServiceMockAPI{}).Resolve)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
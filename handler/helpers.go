@@ -15,6 +15,7 @@ import (
   "net/http"
   "reflect"
   "regexp"
+  "slices"
   "strconv"
   "strings"
   "time"
@@ -252,6 +253,9 @@ var (
 
 // getArticleFilter creates a transfer.ArticleFilter object with the values extracted from
 // c.Request.URL. If no values are provided, then it injects default values.
+//
+// The 'page' query parameter is only kept for compatibility with offset pagination;
+// if it is absent, filter.Page is zero and articles are paginated with 'cursor'.
 func getArticleFilter(c *gin.Context) *transfer.ArticleFilter {
   var (
     filter transfer.ArticleFilter
@@ -280,6 +284,63 @@ func getArticleFilter(c *gin.Context) *transfer.ArticleFilter {
 
   filter.Topic = topic
 
+  var excludedTopic = strings.TrimSpace(c.Query("exclude_topic"))
+
+  if "" != excludedTopic {
+    words := wordsOnly.FindAllString(excludedTopic, -1)
+    excludedTopic = strings.Join(words, "-")
+  }
+
+  filter.ExcludeTopic = excludedTopic
+
+  // Tags can be provided either as a repeated 'tag' parameter or as a
+  // comma-separated list, e.g.: '?tag=go&tag=sql' or '?tag=go,sql'.
+  for _, tags := range c.QueryArray("tag") {
+    for _, tag := range strings.Split(tags, ",") {
+      words := wordsOnly.FindAllString(tag, -1)
+      tag = strings.Join(words, "-")
+
+      if "" != tag && !slices.Contains(filter.Tags, tag) {
+        filter.Tags = append(filter.Tags, tag)
+      }
+    }
+  }
+
+  filter.MatchAllTags = "all" == strings.ToLower(strings.TrimSpace(c.Query("tags_match")))
+
+  filter.Sort = transfer.ArticleSort(strings.ToLower(strings.TrimSpace(c.Query("sort"))))
+
+  for parameter, date := range map[string]**time.Time{"after": &filter.After, "before": &filter.Before} {
+    var value = strings.TrimSpace(c.Query(parameter))
+
+    if "" == value {
+      continue
+    }
+
+    parsed, err := time.Parse(time.DateOnly, value)
+    if nil != err {
+      slog.Error(err.Error())
+      continue
+    }
+
+    *date = &parsed
+  }
+
+  for parameter, minutes := range map[string]*int{"min_read_time": &filter.MinReadTime, "max_read_time": &filter.MaxReadTime} {
+    var value = strings.TrimSpace(c.Query(parameter))
+
+    if "" == value {
+      continue
+    }
+
+    *minutes, err = strconv.Atoi(value)
+    if nil != err {
+      slog.Error(err.Error())
+    }
+  }
+
+  filter.Cursor = strings.TrimSpace(c.Query("cursor"))
+
   var page = c.Query("page")
 
   if "" != page {
@@ -287,10 +348,10 @@ func getArticleFilter(c *gin.Context) *transfer.ArticleFilter {
     if nil != err {
       slog.Error(err.Error())
     }
-  }
 
-  if 0 >= filter.Page {
-    filter.Page = 1
+    if 0 >= filter.Page {
+      filter.Page = 1
+    }
   }
 
   var rpp = c.Query("rpp")
@@ -303,7 +364,7 @@ func getArticleFilter(c *gin.Context) *transfer.ArticleFilter {
   }
 
   if 0 >= filter.RPP {
-    filter.RPP = 20
+    filter.RPP = defaultRPP
   }
 
   var from = c.Query("from")
@@ -340,3 +401,104 @@ func getArticleFilter(c *gin.Context) *transfer.ArticleFilter {
 finish:
   return &filter
 }
+
+// defaultRPP is the number of records per page of a list method
+// when the 'rpp' query parameter is not provided.
+const defaultRPP = 20
+
+// getRPP extracts the 'rpp' (records per page) query parameter from
+// c.Request.URL. If it is absent or invalid, it returns defaultRPP.
+func getRPP(c *gin.Context) int {
+  rpp, err := strconv.Atoi(c.DefaultQuery("rpp", strconv.Itoa(defaultRPP)))
+  if nil != err || 0 >= rpp {
+    return defaultRPP
+  }
+
+  return rpp
+}
+
+// writePage writes a page of records as a JSON response. If the
+// client asked for a page number, that is, the legacy offset
+// pagination, then only the records are written as a JSON array.
+func writePage[T any](c *gin.Context, page *transfer.Page[T]) {
+  if _, legacy := c.GetQuery("page"); legacy {
+    c.JSON(http.StatusOK, page.Items)
+    return
+  }
+
+  c.JSON(http.StatusOK, page)
+}
+
+// paginate slices items, which must be already sorted, into the page
+// requested by the 'cursor' and 'rpp' query parameters. It is meant for
+// small collections that are fully loaded into memory. The cursor holds
+// the identity, as given by id, of the last record of the previous page.
+//
+// If the 'page' query parameter is present, items are sliced by offset.
+func paginate[T any](c *gin.Context, items []T, id func(T) string) (*transfer.Page[T], error) {
+  var (
+    rpp    = getRPP(c)
+    start  = 0
+    cursor = strings.TrimSpace(c.Query("cursor"))
+  )
+
+  if p, legacy := c.GetQuery("page"); legacy {
+    n, err := strconv.Atoi(p)
+    if nil != err || 0 >= n {
+      n = 1
+    }
+
+    start = min(len(items), (n-1)*rpp)
+  } else if "" != cursor {
+    var last string
+
+    if err := transfer.DecodeCursor(cursor, &last); nil != err {
+      return nil, problem.NewUnparsableValue("cursor", "cursor", cursor)
+    }
+
+    start = slices.IndexFunc(items, func(item T) bool { return id(item) == last })
+
+    if -1 == start {
+      return nil, problem.NewStaleCursor(cursor)
+    }
+
+    start++
+  }
+
+  var (
+    end  = min(len(items), start+rpp)
+    page = &transfer.Page[T]{Items: items[start:end]}
+  )
+
+  if end < len(items) {
+    page.NextCursor = transfer.EncodeCursor(id(items[end-1]))
+  }
+
+  return page, nil
+}
+
+// setETag sets the 'ETag' response header to the version of the
+// record being written, as in: '"3"'.
+func setETag(c *gin.Context, version int) {
+  c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatch overrides version with the one in the 'If-Match' request
+// header, if any, so that a write is only made upon the version of the
+// record the client last read. Both strong and weak entity tags are
+// accepted, as in: '"3"' and 'W/"3"'; a '*' matches any version.
+func ifMatch(c *gin.Context, version *int) error {
+  header := strings.TrimSpace(c.GetHeader("If-Match"))
+
+  if "" == header || "*" == header {
+    return nil
+  }
+
+  parsed, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(header, "W/"), `"`))
+  if nil != err || 0 >= parsed {
+    return problem.NewUnparsableValue("entity tag", "If-Match", header)
+  }
+
+  *version = parsed
+  return nil
+}
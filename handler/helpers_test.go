@@ -6,9 +6,13 @@ import (
   "github.com/gin-gonic/gin"
   "github.com/stretchr/testify/assert"
   "github.com/stretchr/testify/require"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
   "math"
   "net/http"
+  "net/http/httptest"
   "testing"
+  "time"
 )
 
 // marshal marshals a value to JSON and logs a fatal error if marshaling fails.
@@ -181,19 +185,91 @@ func Test_bindPostForm(t *testing.T) {
 }
 
 func Test_getArticleFilter(t *testing.T) {
-  // t.Run("success with search", func(t *testing.T) {
-  //   expectedArticles := make([]*model.Article, 3)
-  //   expectedNeedle := "20 www xxx yyy zzz zzz"
-  //
-  //   needle := ">> = 20 www? xxx! yyy... zzz_zzz \" ' ° <<"
-  //
-  //   r := mocks.NewArchiveRepository()
-  //   r.On(routine, ctx, expectedNeedle, true, false).Return(expectedArticles, nil)
-  //
-  //   articles, err := NewArticlesService(r).GetHidden(ctx, needle)
-  //
-  //   assert.Equal(t, expectedArticles, articles)
-  //   assert.NoError(t, err)
-  // })
+  var context = func(query string) *gin.Context {
+    var c, _ = gin.CreateTestContext(httptest.NewRecorder())
+    c.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+    return c
+  }
+
+  t.Run("defaults", func(t *testing.T) {
+    assert.Equal(t, &transfer.ArticleFilter{RPP: defaultRPP}, getArticleFilter(context("")))
+  })
+
+  t.Run("sorting and advanced filtering", func(t *testing.T) {
+    var (
+      after, _  = time.Parse(time.DateOnly, "2024-01-01")
+      before, _ = time.Parse(time.DateOnly, "2025-01-01")
+      expected  = &transfer.ArticleFilter{
+        Topic:        "go",
+        ExcludeTopic: "life-style",
+        Tags:         []string{"sql", "postgres", "go-lang"},
+        MatchAllTags: true,
+        After:        &after,
+        Before:       &before,
+        MinReadTime:  3,
+        MaxReadTime:  15,
+        Sort:         transfer.SortMostViewed,
+        RPP:          defaultRPP,
+      }
+    )
+
+    filter := getArticleFilter(context("topic=go&exclude_topic=life%20style&tag=sql,postgres&tag=go%20lang&tag=sql" +
+      "&tags_match=ALL&after=2024-01-01&before=2025-01-01&min_read_time=3&max_read_time=15&sort=Views"))
+
+    assert.Equal(t, expected, filter)
+  })
+
+  t.Run("ignores malformed values", func(t *testing.T) {
+    filter := getArticleFilter(context("after=yesterday&min_read_time=a&tag=,,"))
+    assert.Nil(t, filter.After)
+    assert.Zero(t, filter.MinReadTime)
+    assert.Empty(t, filter.Tags)
+  })
+}
+
+func Test_paginate(t *testing.T) {
+  var items = []string{"a", "b", "c", "d", "e"}
+  var id = func(s string) string { return s }
+  var context = func(query string) *gin.Context {
+    var c, _ = gin.CreateTestContext(httptest.NewRecorder())
+    c.Request = httptest.NewRequest(http.MethodGet, "/?"+query, nil)
+    return c
+  }
+
+  t.Run("first page", func(t *testing.T) {
+    page, err := paginate(context("rpp=2"), items, id)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"a", "b"}, page.Items)
+    assert.Equal(t, transfer.EncodeCursor("b"), page.NextCursor)
+  })
+
+  t.Run("follows cursor until the last page", func(t *testing.T) {
+    page, err := paginate(context("rpp=2&cursor="+transfer.EncodeCursor("b")), items, id)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"c", "d"}, page.Items)
+
+    page, err = paginate(context("rpp=2&cursor="+page.NextCursor), items, id)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"e"}, page.Items)
+    assert.Empty(t, page.NextCursor)
+  })
 
+  t.Run("legacy page number", func(t *testing.T) {
+    page, err := paginate(context("rpp=2&page=2"), items, id)
+    require.NoError(t, err)
+    assert.Equal(t, []string{"c", "d"}, page.Items)
+  })
+
+  t.Run("unparseable cursor", func(t *testing.T) {
+    page, err := paginate(context("cursor=%25%25"), items, id)
+    assert.Nil(t, page)
+    assert.ErrorContains(t, err, "cursor")
+  })
+
+  t.Run("stale cursor", func(t *testing.T) {
+    cursor := transfer.EncodeCursor("z")
+    page, err := paginate(context("cursor="+cursor), items, id)
+    assert.Nil(t, page)
+    assert.Equal(t, problem.NewStaleCursor(cursor), err)
+  })
 }
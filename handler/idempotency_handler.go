@@ -0,0 +1,137 @@
+package handler
+
+import (
+  "bytes"
+  "context"
+  "crypto/sha256"
+  "encoding/hex"
+  "errors"
+  "fontseca.dev/model"
+  "github.com/gin-gonic/gin"
+  "log/slog"
+  "net/http"
+  "strings"
+)
+
+type idempotencyServiceAPI interface {
+  Begin(ctx context.Context, key, method, fingerprint string) (stored *model.IdempotentResponse, err error)
+  Complete(ctx context.Context, response *model.IdempotentResponse) error
+  Release(ctx context.Context, key, method string) error
+}
+
+const (
+  idempotencyKeyHeader     = "Idempotency-Key"
+  idempotentReplayedHeader = "Idempotent-Replayed"
+  idempotencyMaxFormMemory = 32 << 20
+)
+
+// idempotentWriter records the response written by the next handlers,
+// so that it can be stored and replayed.
+type idempotentWriter struct {
+  gin.ResponseWriter
+  body bytes.Buffer
+}
+
+func (w *idempotentWriter) Write(b []byte) (int, error) {
+  w.body.Write(b)
+  return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotentWriter) WriteString(s string) (int, error) {
+  w.body.WriteString(s)
+  return w.ResponseWriter.WriteString(s)
+}
+
+type IdempotencyHandler struct {
+  idempotency idempotencyServiceAPI
+}
+
+func NewIdempotencyHandler(idempotency idempotencyServiceAPI) *IdempotencyHandler {
+  return &IdempotencyHandler{idempotency: idempotency}
+}
+
+// Guard is a middleware that makes the repeats of a request with the
+// same 'Idempotency-Key' header replay the response to its first
+// execution. Requests without the header are executed as usual.
+//
+// The response to an execution that fails with a server error, or that
+// panics, is not stored, so that the request can be retried with the
+// same key.
+func (h *IdempotencyHandler) Guard(c *gin.Context) {
+  key, ok := c.Request.Header[idempotencyKeyHeader]
+  if !ok {
+    c.Next()
+    return
+  }
+
+  if err := c.Request.ParseMultipartForm(idempotencyMaxFormMemory); nil != err && !errors.Is(err, http.ErrNotMultipart) {
+    slog.Error(err.Error())
+  }
+
+  var (
+    method      = strings.TrimPrefix(c.FullPath(), "/")
+    fingerprint = sha256.Sum256([]byte(method + "\n" + c.Request.PostForm.Encode()))
+    response    = &model.IdempotentResponse{
+      Key:         strings.TrimSpace(strings.Join(key, ",")),
+      Method:      method,
+      Fingerprint: hex.EncodeToString(fingerprint[:]),
+    }
+  )
+
+  stored, err := h.idempotency.Begin(c, response.Key, response.Method, response.Fingerprint)
+  if check(err, c.Writer) {
+    c.Abort()
+    return
+  }
+
+  if nil != stored {
+    c.Header(idempotentReplayedHeader, "true")
+    c.Data(stored.Status, stored.ContentType, stored.Body)
+    c.Abort()
+    return
+  }
+
+  writer := &idempotentWriter{ResponseWriter: c.Writer}
+  c.Writer = writer
+
+  defer func() {
+    if recovered := recover(); nil != recovered {
+      h.release(c, response)
+      panic(recovered)
+    }
+  }()
+
+  c.Next()
+
+  response.Status = writer.Status()
+
+  if http.StatusInternalServerError <= response.Status {
+    h.release(c, response)
+    return
+  }
+
+  response.ContentType = writer.Header().Get("Content-Type")
+  response.Body = writer.body.Bytes()
+
+  // The response has been sent by then, so a failure is only logged and
+  // the key is freed once its lease is over.
+  if err = h.idempotency.Complete(context.WithoutCancel(c), response); nil != err {
+    slog.Error("could not store idempotent response",
+      slog.String("idempotency_key", response.Key),
+      slog.String("method", response.Method),
+      slog.String("error", err.Error()),
+    )
+  }
+}
+
+// release frees the key of a request whose execution failed. A failure
+// is only logged, since the key is freed anyway once its lease is over.
+func (h *IdempotencyHandler) release(c *gin.Context, response *model.IdempotentResponse) {
+  if err := h.idempotency.Release(context.WithoutCancel(c), response.Key, response.Method); nil != err {
+    slog.Error("could not release idempotency key",
+      slog.String("idempotency_key", response.Key),
+      slog.String("method", response.Method),
+      slog.String("error", err.Error()),
+    )
+  }
+}
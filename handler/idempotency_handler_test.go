@@ -0,0 +1,167 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+)
+
+type idempotencyServiceMockAPI struct {
+  idempotencyServiceAPI
+  returns   []any
+  errors    error
+  began     []string
+  completed *model.IdempotentResponse
+  released  bool
+}
+
+func (mock *idempotencyServiceMockAPI) Begin(_ context.Context, key, method, fingerprint string) (*model.IdempotentResponse, error) {
+  mock.began = []string{key, method, fingerprint}
+  return mock.returns[0].(*model.IdempotentResponse), mock.errors
+}
+
+func (mock *idempotencyServiceMockAPI) Complete(_ context.Context, response *model.IdempotentResponse) error {
+  mock.completed = response
+  return nil
+}
+
+func (mock *idempotencyServiceMockAPI) Release(context.Context, string, string) error {
+  mock.released = true
+  return nil
+}
+
+func TestIdempotencyHandler_Guard(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/archive.drafts.start"
+    key    = "0d7c6b1e-5d3f-4c1b-9a52-6f1f0f6a2b10"
+  )
+
+  request := func(key string) *http.Request {
+    r := httptest.NewRequest(method, target, strings.NewReader("title=Title&content=Content"))
+    r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+    if "" != key {
+      r.Header.Set("Idempotency-Key", key)
+    }
+
+    return r
+  }
+
+  var executions int
+
+  start := func(c *gin.Context) {
+    executions++
+    c.JSON(http.StatusOK, gin.H{"inserted_id": "7d7d4da0-093a-443b-b041-2da650381220"})
+  }
+
+  t.Run("without a key", func(t *testing.T) {
+    executions = 0
+    s := &idempotencyServiceMockAPI{}
+
+    engine := gin.Default()
+    engine.POST(target, NewIdempotencyHandler(s).Guard, start)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(""))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, 1, executions)
+    assert.Nil(t, s.began)
+    assert.Nil(t, s.completed)
+  })
+
+  t.Run("first execution", func(t *testing.T) {
+    executions = 0
+    s := &idempotencyServiceMockAPI{returns: []any{(*model.IdempotentResponse)(nil)}}
+
+    engine := gin.Default()
+    engine.POST(target, NewIdempotencyHandler(s).Guard, start)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(key))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, 1, executions)
+    require.Len(t, s.began, 3)
+    assert.Equal(t, key, s.began[0])
+    assert.Equal(t, "archive.drafts.start", s.began[1])
+    require.NotNil(t, s.completed)
+    assert.Equal(t, http.StatusOK, s.completed.Status)
+    assert.Contains(t, s.completed.ContentType, "application/json")
+    assert.Equal(t, recorder.Body.String(), string(s.completed.Body))
+  })
+
+  t.Run("same fingerprint for the same request", func(t *testing.T) {
+    s := &idempotencyServiceMockAPI{returns: []any{(*model.IdempotentResponse)(nil)}}
+
+    engine := gin.Default()
+    engine.POST(target, NewIdempotencyHandler(s).Guard, start)
+
+    engine.ServeHTTP(httptest.NewRecorder(), request(key))
+    first := s.began[2]
+    engine.ServeHTTP(httptest.NewRecorder(), request(key))
+
+    assert.Equal(t, first, s.began[2])
+  })
+
+  t.Run("repeat", func(t *testing.T) {
+    executions = 0
+    stored := &model.IdempotentResponse{Status: http.StatusOK, ContentType: "application/json; charset=utf-8", Body: []byte(`{"inserted_id":"7d7d4da0-093a-443b-b041-2da650381220"}`)}
+    s := &idempotencyServiceMockAPI{returns: []any{stored}}
+
+    engine := gin.Default()
+    engine.POST(target, NewIdempotencyHandler(s).Guard, start)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(key))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, 0, executions)
+    assert.Equal(t, string(stored.Body), recorder.Body.String())
+    assert.Equal(t, "true", recorder.Header().Get("Idempotent-Replayed"))
+    assert.Nil(t, s.completed)
+  })
+
+  t.Run("server error", func(t *testing.T) {
+    s := &idempotencyServiceMockAPI{returns: []any{(*model.IdempotentResponse)(nil)}}
+
+    engine := gin.Default()
+    engine.POST(target, NewIdempotencyHandler(s).Guard, func(c *gin.Context) {
+      problem.NewInternal().Emit(c.Writer)
+    })
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(key))
+
+    assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+    assert.True(t, s.released)
+    assert.Nil(t, s.completed)
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    executions = 0
+    expected := &problem.Problem{}
+    expected.Status(http.StatusConflict)
+    expected.Detail("Expected problem detail.")
+
+    s := &idempotencyServiceMockAPI{returns: []any{(*model.IdempotentResponse)(nil)}, errors: expected}
+
+    engine := gin.Default()
+    engine.POST(target, NewIdempotencyHandler(s).Guard, start)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(key))
+
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "Expected problem detail.")
+    assert.Equal(t, 0, executions)
+  })
+}
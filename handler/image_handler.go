@@ -0,0 +1,47 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/problem"
+  "github.com/gin-gonic/gin"
+  "strconv"
+  "strings"
+)
+
+type imageServiceAPI interface {
+  Derive(ctx context.Context, hash string, width int, format string) (path, mimeType string, err error)
+}
+
+type ImageHandler struct {
+  images imageServiceAPI
+}
+
+func NewImageHandler(images imageServiceAPI) *ImageHandler {
+  return &ImageHandler{images: images}
+}
+
+// Serve serves an image of the media library, resized to the width in
+// the 'w' query parameter and encoded in the format in 'fmt', if any.
+// Derivatives never change for the same URL, so clients can cache them
+// forever.
+func (h *ImageHandler) Serve(c *gin.Context) {
+  var width int
+
+  if w := strings.TrimSpace(c.Query("w")); "" != w {
+    var err error
+    if width, err = strconv.Atoi(w); nil != err {
+      problem.NewUnparsableValue("int", "w", w).Emit(c.Writer)
+      return
+    }
+  }
+
+  path, mimeType, err := h.images.Derive(c, c.Param("hash"), width, c.Query("fmt"))
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.Header("Content-Type", mimeType)
+  c.Header("Cache-Control", "public, max-age=31536000, immutable")
+  c.Header("X-Content-Type-Options", "nosniff")
+  c.File(path)
+}
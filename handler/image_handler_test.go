@@ -0,0 +1,64 @@
+package handler
+
+import (
+  "context"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "os"
+  "path/filepath"
+  "testing"
+)
+
+type imageServiceMockAPI struct {
+  imageServiceAPI
+  t         *testing.T
+  returns   []any
+  arguments []any
+  errors    error
+}
+
+func (mock *imageServiceMockAPI) Derive(_ context.Context, hash string, width int, format string) (string, string, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments, []any{hash, width, format})
+  }
+
+  return mock.returns[0].(string), mock.returns[1].(string), mock.errors
+}
+
+func TestImageHandler_Serve(t *testing.T) {
+  const method = http.MethodGet
+
+  t.Run("success", func(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "lorem-640.jpeg")
+    require.NoError(t, os.WriteFile(path, []byte("lorem ipsum"), 0o644))
+    s := &imageServiceMockAPI{t: t, arguments: []any{"lorem", 640, "jpeg"}, returns: []any{path, "image/jpeg"}}
+
+    engine := gin.Default()
+    engine.GET("/img/:hash", NewImageHandler(s).Serve)
+
+    request := httptest.NewRequest(method, "/img/lorem?w=640&fmt=jpeg", nil)
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Equal(t, "image/jpeg", recorder.Header().Get("Content-Type"))
+    assert.Contains(t, recorder.Header().Get("Cache-Control"), "immutable")
+    assert.Equal(t, "lorem ipsum", recorder.Body.String())
+  })
+
+  t.Run("unparsable width", func(t *testing.T) {
+    engine := gin.Default()
+    engine.GET("/img/:hash", NewImageHandler(&imageServiceMockAPI{}).Serve)
+
+    request := httptest.NewRequest(method, "/img/lorem?w=wide", nil)
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+  })
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+)
+
+type linksServiceAPI interface {
+  Report(ctx context.Context) (reports []*transfer.LinkReport, err error)
+}
+
+type LinksHandler struct {
+  links linksServiceAPI
+}
+
+func NewLinksHandler(links linksServiceAPI) *LinksHandler {
+  return &LinksHandler{links: links}
+}
+
+func (h *LinksHandler) Report(c *gin.Context) {
+  reports, err := h.links.Report(c)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusOK, reports)
+}
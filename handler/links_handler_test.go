@@ -0,0 +1,58 @@
+package handler
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+type linksServiceMockAPI struct {
+  linksServiceAPI
+  returns []any
+  errors  error
+}
+
+func (mock *linksServiceMockAPI) Report(context.Context) ([]*transfer.LinkReport, error) {
+  return mock.returns[0].([]*transfer.LinkReport), mock.errors
+}
+
+func TestLinksHandler_Report(t *testing.T) {
+  const (
+    method = http.MethodGet
+    target = "/links.report"
+  )
+
+  t.Run("success", func(t *testing.T) {
+    status := http.StatusNotFound
+    reports := []*transfer.LinkReport{{Type: "project", ID: "lorem-ipsum", Title: "Lorem ipsum", Links: []*transfer.BrokenLink{{URL: "https://example.com/lorem", Status: &status}}}}
+    s := &linksServiceMockAPI{returns: []any{reports}}
+
+    engine := gin.Default()
+    engine.GET(target, NewLinksHandler(s).Report)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), `"url":"https://example.com/lorem","status":404`)
+  })
+
+  t.Run("gets a service failure", func(t *testing.T) {
+    s := &linksServiceMockAPI{returns: []any{[]*transfer.LinkReport(nil)}, errors: errors.New("unexpected error")}
+
+    engine := gin.Default()
+    engine.GET(target, NewLinksHandler(s).Report)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+  })
+}
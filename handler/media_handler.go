@@ -0,0 +1,131 @@
+package handler
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "io"
+  "net/http"
+)
+
+type mediaServiceAPI interface {
+  Upload(ctx context.Context, upload *transfer.MediaUpload) (media *model.Media, err error)
+  List(ctx context.Context) (files []*model.Media, err error)
+  Get(ctx context.Context, hash string) (media *model.Media, err error)
+  Open(ctx context.Context, hash string) (media *model.Media, path string, err error)
+  Remove(ctx context.Context, hash string) error
+}
+
+// maxMediaUploadSize is the maximum size of the body of an upload to the
+// media library: the file, of up to 32MiB, and the rest of the form.
+const maxMediaUploadSize = 33 << 20
+
+type MediaHandler struct {
+  media mediaServiceAPI
+}
+
+func NewMediaHandler(media mediaServiceAPI) *MediaHandler {
+  return &MediaHandler{media: media}
+}
+
+func (h *MediaHandler) Upload(c *gin.Context) {
+  c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxMediaUploadSize)
+
+  file, header, err := c.Request.FormFile("file")
+  if nil != err {
+    var tooLarge *http.MaxBytesError
+    if errors.As(err, &tooLarge) {
+      check(problem.NewValidation([3]string{"file", "max", "32MiB"}), c.Writer)
+      return
+    }
+
+    problem.NewMissingParameter("file").Emit(c.Writer)
+    return
+  }
+
+  defer file.Close()
+
+  content, err := io.ReadAll(file)
+  if check(err, c.Writer) {
+    return
+  }
+
+  upload := transfer.MediaUpload{
+    Name:    header.Filename,
+    AltText: c.PostForm("alt_text"),
+    Content: content,
+  }
+
+  if err = validateStruct(&upload); check(err, c.Writer) {
+    return
+  }
+
+  media, err := h.media.Upload(c, &upload)
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusCreated, media)
+}
+
+func (h *MediaHandler) List(c *gin.Context) {
+  files, err := h.media.List(c)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  page, err := paginate(c, files, func(m *model.Media) string { return m.Hash })
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writePage(c, page)
+}
+
+func (h *MediaHandler) Get(c *gin.Context) {
+  hash, ok := c.GetQuery("hash")
+  if !ok {
+    problem.NewMissingParameter("hash").Emit(c.Writer)
+    return
+  }
+
+  media, err := h.media.Get(c, hash)
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusOK, media)
+}
+
+// Serve serves the content of a file of the media library. Files are
+// named after their content, so clients can cache them forever.
+func (h *MediaHandler) Serve(c *gin.Context) {
+  media, path, err := h.media.Open(c, c.Param("hash"))
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.Header("Content-Type", media.MIMEType)
+  c.Header("Cache-Control", "public, max-age=31536000, immutable")
+  c.Header("X-Content-Type-Options", "nosniff")
+  c.File(path)
+}
+
+func (h *MediaHandler) Remove(c *gin.Context) {
+  hash, ok := c.GetPostForm("hash")
+  if !ok {
+    problem.NewMissingParameter("hash").Emit(c.Writer)
+    return
+  }
+
+  if err := h.media.Remove(c, hash); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
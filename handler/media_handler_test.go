@@ -0,0 +1,143 @@
+package handler
+
+import (
+  "bytes"
+  "context"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "mime/multipart"
+  "net/http"
+  "net/http/httptest"
+  "net/url"
+  "strings"
+  "testing"
+)
+
+type mediaServiceMockAPI struct {
+  mediaServiceAPI
+  t         *testing.T
+  returns   []any
+  arguments []any
+  errors    error
+}
+
+func (mock *mediaServiceMockAPI) Upload(_ context.Context, upload *transfer.MediaUpload) (*model.Media, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[0], upload)
+  }
+
+  return mock.returns[0].(*model.Media), mock.errors
+}
+
+func (mock *mediaServiceMockAPI) Remove(_ context.Context, hash string) error {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[0], hash)
+  }
+
+  return mock.errors
+}
+
+func TestMediaHandler_Upload(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/media.upload"
+  )
+
+  t.Run("success", func(t *testing.T) {
+    var (
+      body   bytes.Buffer
+      writer = multipart.NewWriter(&body)
+    )
+
+    part, err := writer.CreateFormFile("file", "lorem.png")
+    require.NoError(t, err)
+    _, _ = part.Write([]byte("lorem ipsum"))
+    require.NoError(t, writer.WriteField("alt_text", "Lorem ipsum"))
+    require.NoError(t, writer.Close())
+
+    upload := &transfer.MediaUpload{Name: "lorem.png", AltText: "Lorem ipsum", Content: []byte("lorem ipsum")}
+    media := &model.Media{Hash: strings.Repeat("ab", 32), Name: "lorem.png"}
+    s := &mediaServiceMockAPI{t: t, arguments: []any{upload}, returns: []any{media}}
+
+    engine := gin.Default()
+    engine.POST(target, NewMediaHandler(s).Upload)
+
+    request := httptest.NewRequest(method, target, &body)
+    request.Header.Set("Content-Type", writer.FormDataContentType())
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusCreated, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), media.Hash)
+  })
+
+  t.Run("missing file", func(t *testing.T) {
+    engine := gin.Default()
+    engine.POST(target, NewMediaHandler(&mediaServiceMockAPI{}).Upload)
+
+    request := httptest.NewRequest(method, target, strings.NewReader(url.Values{"alt_text": {"Lorem"}}.Encode()))
+    request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+}
+
+func TestMediaHandler_Remove(t *testing.T) {
+  const (
+    method = http.MethodPost
+    target = "/media.remove"
+  )
+
+  hash := strings.Repeat("ab", 32)
+
+  t.Run("success", func(t *testing.T) {
+    s := &mediaServiceMockAPI{t: t, arguments: []any{hash}}
+
+    engine := gin.Default()
+    engine.POST(target, NewMediaHandler(s).Remove)
+
+    request := httptest.NewRequest(method, target, strings.NewReader(url.Values{"hash": {hash}}.Encode()))
+    request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+  })
+
+  t.Run("missing hash", func(t *testing.T) {
+    engine := gin.Default()
+    engine.POST(target, NewMediaHandler(&mediaServiceMockAPI{}).Remove)
+
+    request := httptest.NewRequest(method, target, nil)
+    request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+  })
+
+  t.Run("gets a service failure", func(t *testing.T) {
+    s := &mediaServiceMockAPI{errors: problem.NewNotFound(hash, "media")}
+
+    engine := gin.Default()
+    engine.POST(target, NewMediaHandler(s).Remove)
+
+    request := httptest.NewRequest(method, target, strings.NewReader(url.Values{"hash": {hash}}.Encode()))
+    request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusNotFound, recorder.Code)
+  })
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+  "context"
+  "encoding/xml"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+  "strconv"
+  "strings"
+)
+
+type oEmbedServiceAPI interface {
+  Resolve(ctx context.Context, request *transfer.OEmbedRequest) (*transfer.OEmbed, error)
+}
+
+type OEmbedHandler struct {
+  oembed oEmbedServiceAPI
+}
+
+func NewOEmbedHandler(oembed oEmbedServiceAPI) *OEmbedHandler {
+  return &OEmbedHandler{oembed: oembed}
+}
+
+func (h *OEmbedHandler) Resolve(c *gin.Context) {
+  u, ok := c.GetQuery("url")
+
+  if !ok {
+    problem.NewMissingParameter("url").Emit(c.Writer)
+    return
+  }
+
+  format := strings.TrimSpace(c.DefaultQuery("format", "json"))
+
+  if "json" != format && "xml" != format {
+    var p problem.Problem
+    p.Status(http.StatusNotImplemented)
+    p.Title("Unsupported format.")
+    p.Detail("The oEmbed format must be either 'json' or 'xml'.")
+    p.With("format", format)
+    p.Emit(c.Writer)
+    return
+  }
+
+  request := &transfer.OEmbedRequest{URL: u}
+
+  for parameter, dimension := range map[string]*int{"maxwidth": &request.MaxWidth, "maxheight": &request.MaxHeight} {
+    value := strings.TrimSpace(c.Query(parameter))
+
+    if "" == value {
+      continue
+    }
+
+    var err error
+    if *dimension, err = strconv.Atoi(value); nil != err {
+      problem.NewUnparsableValue("int", parameter, value).Emit(c.Writer)
+      return
+    }
+  }
+
+  embed, err := h.oembed.Resolve(c, request)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  if "xml" == format {
+    document, err := xml.Marshal(embed)
+
+    if check(err, c.Writer) {
+      return
+    }
+
+    c.Data(http.StatusOK, "text/xml; charset=utf-8", append([]byte(xml.Header), document...))
+    return
+  }
+
+  c.JSON(http.StatusOK, embed)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "net/url"
+  "testing"
+)
+
+type oEmbedServiceMockAPI struct {
+  oEmbedServiceAPI
+  t         *testing.T
+  returns   []any
+  arguments []any
+  errors    error
+}
+
+func (mock *oEmbedServiceMockAPI) Resolve(_ context.Context, request *transfer.OEmbedRequest) (*transfer.OEmbed, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[0], request)
+  }
+
+  return mock.returns[0].(*transfer.OEmbed), mock.errors
+}
+
+func TestOEmbedHandler_Resolve(t *testing.T) {
+  const (
+    method = http.MethodGet
+    target = "/oembed"
+  )
+
+  u := "https://fontseca.dev/work/lorem-ipsum"
+  embed := &transfer.OEmbed{Version: "1.0", Type: "rich", Title: "Lorem ipsum", Width: 400, Height: 370}
+
+  t.Run("success", func(t *testing.T) {
+    request := &transfer.OEmbedRequest{URL: u, MaxWidth: 400}
+    s := &oEmbedServiceMockAPI{t: t, arguments: []any{request}, returns: []any{embed}}
+
+    engine := gin.Default()
+    engine.GET(target, NewOEmbedHandler(s).Resolve)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?maxwidth=400&url="+url.QueryEscape(u), nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+    assert.Contains(t, recorder.Body.String(), `"title":"Lorem ipsum"`)
+  })
+
+  t.Run("xml format", func(t *testing.T) {
+    s := &oEmbedServiceMockAPI{returns: []any{embed}}
+
+    engine := gin.Default()
+    engine.GET(target, NewOEmbedHandler(s).Resolve)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?format=xml&url="+url.QueryEscape(u), nil))
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+    assert.Contains(t, recorder.Header().Get("Content-Type"), "text/xml")
+    assert.Contains(t, recorder.Body.String(), "<oembed>")
+    assert.Contains(t, recorder.Body.String(), "<title>Lorem ipsum</title>")
+  })
+
+  t.Run("unsupported format", func(t *testing.T) {
+    engine := gin.Default()
+    engine.GET(target, NewOEmbedHandler(&oEmbedServiceMockAPI{}).Resolve)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?format=yaml&url="+url.QueryEscape(u), nil))
+
+    assert.Equal(t, http.StatusNotImplemented, recorder.Code)
+  })
+
+  t.Run("missing url", func(t *testing.T) {
+    engine := gin.Default()
+    engine.GET(target, NewOEmbedHandler(&oEmbedServiceMockAPI{}).Resolve)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "url")
+  })
+
+  t.Run("unparsable maxwidth", func(t *testing.T) {
+    engine := gin.Default()
+    engine.GET(target, NewOEmbedHandler(&oEmbedServiceMockAPI{}).Resolve)
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, httptest.NewRequest(method, target+"?maxwidth=wide&url="+url.QueryEscape(u), nil))
+
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), "maxwidth")
+  })
+}
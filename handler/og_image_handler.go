@@ -0,0 +1,47 @@
+package handler
+
+import (
+  "context"
+  "github.com/gin-gonic/gin"
+)
+
+type ogImageServiceAPI interface {
+  Article(ctx context.Context, articleID string) (path string, err error)
+  Project(ctx context.Context, slug string) (path string, err error)
+}
+
+type OGImageHandler struct {
+  images ogImageServiceAPI
+}
+
+func NewOGImageHandler(images ogImageServiceAPI) *OGImageHandler {
+  return &OGImageHandler{images: images}
+}
+
+// writeImage serves a generated image. Images are named after their
+// content, so clients can cache them for a while.
+func writeImage(c *gin.Context, path string) {
+  c.Header("Content-Type", "image/png")
+  c.Header("Cache-Control", "public, max-age=86400")
+  c.File(path)
+}
+
+func (h *OGImageHandler) Article(c *gin.Context) {
+  path, err := h.images.Article(c, c.Param("article_uuid"))
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writeImage(c, path)
+}
+
+func (h *OGImageHandler) Project(c *gin.Context) {
+  path, err := h.images.Project(c, c.Param("project_slug"))
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writeImage(c, path)
+}
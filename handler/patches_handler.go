@@ -12,7 +12,7 @@ import (
 type patchesServiceAPI interface {
   List(ctx context.Context) (patches []*model.ArticlePatch, err error)
   Revise(ctx context.Context, patchID string, revision *transfer.ArticleRevision) error
-  Share(ctx context.Context, patchID string) (link string, err error)
+  Share(ctx context.Context, patchID string, creation *transfer.ShareableLinkCreation) (link *model.ShareableLink, err error)
   Discard(ctx context.Context, patchID string) error
   Release(ctx context.Context, patchID string) error
 }
@@ -32,7 +32,13 @@ func (h *PatchesHandler) List(c *gin.Context) {
     return
   }
 
-  c.JSON(http.StatusOK, patches)
+  page, err := paginate(c, patches, func(p *model.ArticlePatch) string { return p.ArticleUUID.String() })
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writePage(c, page)
 }
 
 func (h *PatchesHandler) Revise(c *gin.Context) {
@@ -49,6 +55,10 @@ func (h *PatchesHandler) Revise(c *gin.Context) {
     return
   }
 
+  if err := ifMatch(c, &revision.Version); check(err, c.Writer) {
+    return
+  }
+
   if err := h.patches.Revise(c, patch, &revision); check(err, c.Writer) {
     return
   }
@@ -64,13 +74,23 @@ func (h *PatchesHandler) Share(c *gin.Context) {
     return
   }
 
-  link, err := h.patches.Share(c, draft)
+  var creation transfer.ShareableLinkCreation
+
+  if err := bindPostForm(c, &creation); check(err, c.Writer) {
+    return
+  }
+
+  if err := validateStruct(&creation); check(err, c.Writer) {
+    return
+  }
+
+  link, err := h.patches.Share(c, draft, &creation)
 
   if check(err, c.Writer) {
     return
   }
 
-  c.JSON(http.StatusOK, gin.H{"shareable_link": link})
+  c.JSON(http.StatusOK, link)
 }
 
 func (h *PatchesHandler) Discard(c *gin.Context) {
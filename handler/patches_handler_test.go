@@ -38,7 +38,7 @@ func TestPatchesHandler_Get(t *testing.T) {
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
-    expectedBody := string(marshal(t, patches))
+    expectedBody := string(marshal(t, &transfer.Page[*model.ArticlePatch]{Items: patches}))
 
     s := &patchesServiceMockAPI{returns: []any{patches}}
 
@@ -188,19 +188,19 @@ func TestPatchesHandler_Revise(t *testing.T) {
   })
 }
 
-func (mock *patchesServiceMockAPI) Share(_ context.Context, patchID string) (link string, err error) {
+func (mock *patchesServiceMockAPI) Share(_ context.Context, patchID string, creation *transfer.ShareableLinkCreation) (*model.ShareableLink, error) {
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], patchID)
+    require.Equal(mock.t, mock.arguments[2], creation)
   }
 
-  return mock.returns[0].(string), mock.errors
+  return mock.returns[0].(*model.ShareableLink), mock.errors
 }
 
 func TestPatchesHandler_Share(t *testing.T) {
   const (
     method = http.MethodPost
     target = "/archive.articles.patches.share"
-    link   = "/link/to/draft"
   )
 
   request := httptest.NewRequest(method, target, nil)
@@ -209,12 +209,17 @@ func TestPatchesHandler_Share(t *testing.T) {
   _ = request.ParseForm()
 
   request.PostForm.Add("patch_uuid", id)
+  request.PostForm.Add("label", "Jane")
+  request.PostForm.Add("expires_in", "30")
+  request.PostForm.Add("password", "correct horse")
 
   t.Run("success", func(t *testing.T) {
     expectedStatusCode := http.StatusOK
-    expectedBody := string(marshal(t, gin.H{"shareable_link": link}))
+    link := &model.ShareableLink{UUID: uuid.NewString(), Label: "Jane", Link: "https://fontseca.dev/archive/sharing/6d7f", Protected: true}
+    expectedBody := string(marshal(t, link))
+    creation := &transfer.ShareableLinkCreation{Label: "Jane", ExpiresIn: 30, Password: "correct horse"}
 
-    s := &patchesServiceMockAPI{t: t, arguments: []any{context.Background(), id}, returns: []any{link}}
+    s := &patchesServiceMockAPI{t: t, arguments: []any{context.Background(), id, creation}, returns: []any{link}}
 
     engine := gin.Default()
     engine.POST(target, NewPatchesHandler(s).Share)
@@ -236,7 +241,7 @@ func TestPatchesHandler_Share(t *testing.T) {
     expected.Status(expectedStatusCode)
     expected.Detail(expectBodyContains)
 
-    s := &patchesServiceMockAPI{returns: []any{"about:blank"}, errors: expected}
+    s := &patchesServiceMockAPI{returns: []any{(*model.ShareableLink)(nil)}, errors: expected}
 
     engine := gin.Default()
     engine.POST(target, NewPatchesHandler(s).Share)
@@ -256,7 +261,7 @@ func TestPatchesHandler_Share(t *testing.T) {
     expectedStatusCode := http.StatusInternalServerError
     expectBodyContains := "An unexpected error occurred while processing your request"
 
-    s := &patchesServiceMockAPI{returns: []any{"about:blank"}, errors: unexpected}
+    s := &patchesServiceMockAPI{returns: []any{(*model.ShareableLink)(nil)}, errors: unexpected}
 
     engine := gin.Default()
     engine.POST(target, NewPatchesHandler(s).Share)
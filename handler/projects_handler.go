@@ -36,7 +36,11 @@ func (h *ProjectsHandler) List(c *gin.Context) {
   if check(err, c.Writer) {
     return
   }
-  c.JSON(http.StatusOK, projects)
+  page, err := paginate(c, projects, func(p *model.Project) string { return p.UUID.String() })
+  if check(err, c.Writer) {
+    return
+  }
+  writePage(c, page)
 }
 
 func (h *ProjectsHandler) ListArchived(c *gin.Context) {
@@ -44,7 +48,11 @@ func (h *ProjectsHandler) ListArchived(c *gin.Context) {
   if check(err, c.Writer) {
     return
   }
-  c.JSON(http.StatusOK, projects)
+  page, err := paginate(c, projects, func(p *model.Project) string { return p.UUID.String() })
+  if check(err, c.Writer) {
+    return
+  }
+  writePage(c, page)
 }
 
 func (h *ProjectsHandler) Get(c *gin.Context) {
@@ -53,6 +61,7 @@ func (h *ProjectsHandler) Get(c *gin.Context) {
   if check(err, c.Writer) {
     return
   }
+  setETag(c, project.Version)
   c.JSON(http.StatusOK, project)
 }
 
@@ -81,6 +90,9 @@ func (h *ProjectsHandler) Set(c *gin.Context) {
   if err := bindPostForm(c, &update); check(err, c.Writer) {
     return
   }
+  if err := ifMatch(c, &update.Version); check(err, c.Writer) {
+    return
+  }
   if err := validateStruct(&update); check(err, c.Writer) {
     return
   }
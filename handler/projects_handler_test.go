@@ -48,7 +48,7 @@ func TestProjectsHandler_List(t *testing.T) {
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, projects)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, &transfer.Page[*model.Project]{Items: projects})), recorder.Body.String())
   })
 }
 
@@ -69,7 +69,7 @@ func TestProjectsHandler_ListArchived(t *testing.T) {
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, projects)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, &transfer.Page[*model.Project]{Items: projects})), recorder.Body.String())
   })
 }
 
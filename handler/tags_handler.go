@@ -49,7 +49,13 @@ func (h *TagsHandler) List(c *gin.Context) {
     return
   }
 
-  c.JSON(http.StatusOK, tags)
+  page, err := paginate(c, tags, func(t *model.Tag) string { return t.ID })
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writePage(c, page)
 }
 
 func (h *TagsHandler) Set(c *gin.Context) {
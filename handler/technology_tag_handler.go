@@ -30,7 +30,11 @@ func (h *TechnologyTagHandler) List(c *gin.Context) {
   if check(err, c.Writer) {
     return
   }
-  c.JSON(http.StatusOK, tags)
+  page, err := paginate(c, tags, func(t *model.TechnologyTag) string { return t.UUID.String() })
+  if check(err, c.Writer) {
+    return
+  }
+  writePage(c, page)
 }
 
 func (h *TechnologyTagHandler) Create(c *gin.Context) {
@@ -47,7 +47,7 @@ func TestTechnologyTagHandler_Get(t *testing.T) {
     var recorder = httptest.NewRecorder()
     engine.ServeHTTP(recorder, request)
     assert.Equal(t, http.StatusOK, recorder.Code)
-    assert.Equal(t, string(marshal(t, technologies)), recorder.Body.String())
+    assert.Equal(t, string(marshal(t, &transfer.Page[*model.TechnologyTag]{Items: technologies})), recorder.Body.String())
   })
 }
 
@@ -0,0 +1,128 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "net/http"
+  "strings"
+)
+
+type templatesServiceAPI interface {
+  Create(ctx context.Context, creation *transfer.TemplateCreation) (id string, err error)
+  List(ctx context.Context) (templates []*model.Template, err error)
+  Update(ctx context.Context, id string, update *transfer.TemplateUpdate) error
+  Remove(ctx context.Context, id string) error
+}
+
+type TemplatesHandler struct {
+  templates templatesServiceAPI
+}
+
+func NewTemplatesHandler(templates templatesServiceAPI) *TemplatesHandler {
+  return &TemplatesHandler{templates: templates}
+}
+
+// templateTags gets the default tags of a template, which can be given
+// either as repeated 'tags' parameters or as a comma-separated list. It
+// returns nil if there is no 'tags' parameter at all.
+func templateTags(c *gin.Context) []string {
+  values, ok := c.GetPostFormArray("tags")
+  if !ok {
+    return nil
+  }
+
+  tags := make([]string, 0, len(values))
+
+  for _, value := range values {
+    for _, tag := range strings.Split(value, ",") {
+      if tag = strings.TrimSpace(tag); "" != tag {
+        tags = append(tags, tag)
+      }
+    }
+  }
+
+  return tags
+}
+
+func (h *TemplatesHandler) Create(c *gin.Context) {
+  var creation transfer.TemplateCreation
+
+  if err := bindPostForm(c, &creation); check(err, c.Writer) {
+    return
+  }
+
+  if err := validateStruct(&creation); check(err, c.Writer) {
+    return
+  }
+
+  creation.Tags = templateTags(c)
+
+  id, err := h.templates.Create(c, &creation)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  c.JSON(http.StatusCreated, gin.H{"template_uuid": id})
+}
+
+func (h *TemplatesHandler) List(c *gin.Context) {
+  templates, err := h.templates.List(c)
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  page, err := paginate(c, templates, func(t *model.Template) string { return t.UUID.String() })
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writePage(c, page)
+}
+
+func (h *TemplatesHandler) Set(c *gin.Context) {
+  var update transfer.TemplateUpdate
+
+  template, ok := c.GetPostForm("template_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("template_uuid").Emit(c.Writer)
+    return
+  }
+
+  if err := bindPostForm(c, &update); check(err, c.Writer) {
+    return
+  }
+
+  if err := validateStruct(&update); check(err, c.Writer) {
+    return
+  }
+
+  update.Tags = templateTags(c)
+
+  if err := h.templates.Update(c, template, &update); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
+
+func (h *TemplatesHandler) Remove(c *gin.Context) {
+  template, ok := c.GetPostForm("template_uuid")
+
+  if !ok {
+    problem.NewMissingParameter("template_uuid").Emit(c.Writer)
+    return
+  }
+
+  if err := h.templates.Remove(c, template); check(err, c.Writer) {
+    return
+  }
+
+  c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "net/url"
+  "strings"
+  "testing"
+)
+
+type templatesServiceMockAPI struct {
+  templatesServiceAPI
+  returns  []any
+  errors   error
+  creation *transfer.TemplateCreation
+  update   *transfer.TemplateUpdate
+}
+
+func (mock *templatesServiceMockAPI) Create(_ context.Context, creation *transfer.TemplateCreation) (string, error) {
+  mock.creation = creation
+  return mock.returns[0].(string), mock.errors
+}
+
+func (mock *templatesServiceMockAPI) Update(_ context.Context, _ string, update *transfer.TemplateUpdate) error {
+  mock.update = update
+  return mock.errors
+}
+
+func TestTemplatesHandler_Create(t *testing.T) {
+  const method = http.MethodPost
+  const target = "/archive.templates.create"
+
+  request := func(form url.Values) *http.Request {
+    r := httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+    r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    return r
+  }
+
+  t.Run("success", func(t *testing.T) {
+    id := uuid.NewString()
+    s := &templatesServiceMockAPI{returns: []any{id}}
+    engine := gin.Default()
+    engine.POST(target, NewTemplatesHandler(s).Create)
+
+    form := url.Values{
+      "name":          {"Post-mortem"},
+      "title_pattern": {"Post-mortem: {title}"},
+      "topic_id":      {"reliability"},
+      "tags":          {"incident, postgres", "outage"},
+    }
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(form))
+
+    assert.Equal(t, http.StatusCreated, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), id)
+    require.NotNil(t, s.creation)
+    assert.Equal(t, "Post-mortem: {title}", s.creation.TitlePattern)
+    assert.Equal(t, "reliability", s.creation.Topic)
+    assert.Equal(t, []string{"incident", "postgres", "outage"}, s.creation.Tags)
+  })
+
+  t.Run("missing name", func(t *testing.T) {
+    s := &templatesServiceMockAPI{}
+    engine := gin.Default()
+    engine.POST(target, NewTemplatesHandler(s).Create)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(url.Values{"title_pattern": {"{title}"}}))
+
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+    assert.Nil(t, s.creation)
+  })
+}
+
+func TestTemplatesHandler_Set(t *testing.T) {
+  const method = http.MethodPost
+  const target = "/archive.templates.set"
+
+  request := func(form url.Values) *http.Request {
+    r := httptest.NewRequest(method, target, strings.NewReader(form.Encode()))
+    r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+    return r
+  }
+
+  t.Run("keeps the tags", func(t *testing.T) {
+    s := &templatesServiceMockAPI{}
+    engine := gin.Default()
+    engine.POST(target, NewTemplatesHandler(s).Set)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(url.Values{"template_uuid": {uuid.NewString()}, "name": {"Book notes"}}))
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    require.NotNil(t, s.update)
+    assert.Nil(t, s.update.Tags)
+  })
+
+  t.Run("clears the tags", func(t *testing.T) {
+    s := &templatesServiceMockAPI{}
+    engine := gin.Default()
+    engine.POST(target, NewTemplatesHandler(s).Set)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(url.Values{"template_uuid": {uuid.NewString()}, "tags": {""}}))
+
+    assert.Equal(t, http.StatusNoContent, recorder.Code)
+    require.NotNil(t, s.update)
+    assert.Equal(t, []string{}, s.update.Tags)
+  })
+
+  t.Run("missing template uuid", func(t *testing.T) {
+    s := &templatesServiceMockAPI{}
+    engine := gin.Default()
+    engine.POST(target, NewTemplatesHandler(s).Set)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(url.Values{"name": {"Book notes"}}))
+
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+    assert.Nil(t, s.update)
+  })
+
+  t.Run("expected problem detail", func(t *testing.T) {
+    expected := problem.NewNotFound(uuid.NewString(), "template")
+    s := &templatesServiceMockAPI{errors: expected}
+    engine := gin.Default()
+    engine.POST(target, NewTemplatesHandler(s).Set)
+
+    recorder := httptest.NewRecorder()
+    engine.ServeHTTP(recorder, request(url.Values{"template_uuid": {uuid.NewString()}}))
+
+    assert.Equal(t, http.StatusNotFound, recorder.Code)
+  })
+}
@@ -49,7 +49,13 @@ func (h *TopicsHandler) List(c *gin.Context) {
     return
   }
 
-  c.JSON(http.StatusOK, topics)
+  page, err := paginate(c, topics, func(t *model.Topic) string { return t.ID })
+
+  if check(err, c.Writer) {
+    return
+  }
+
+  writePage(c, page)
 }
 
 func (h *TopicsHandler) Set(c *gin.Context) {
@@ -18,6 +18,10 @@ import (
   "time"
 )
 
+// shareableLinkCookie is the cookie that keeps the token of a shareable
+// link protected by a password once it is unlocked.
+const shareableLinkCookie = "shareable_link_token"
+
 type WebHandler struct {
   me         meServiceAPI
   experience experienceServiceAPI
@@ -58,7 +62,7 @@ func (h *WebHandler) RenderMe(c *gin.Context) {
   if nil != err {
     return
   }
-  pages.Me(me).Render(c, c.Writer)
+  pages.Me(me).Render(c.Request.Context(), c.Writer)
 }
 
 func (h *WebHandler) RenderExperience(c *gin.Context) {
@@ -66,7 +70,7 @@ func (h *WebHandler) RenderExperience(c *gin.Context) {
   if nil != err {
     return
   }
-  pages.Experience(exp).Render(c, c.Writer)
+  pages.Experience(exp).Render(c.Request.Context(), c.Writer)
 }
 
 func (h *WebHandler) RenderProjects(c *gin.Context) {
@@ -74,7 +78,7 @@ func (h *WebHandler) RenderProjects(c *gin.Context) {
   if nil != err {
     return
   }
-  pages.Projects(projects).Render(c, c.Writer)
+  pages.Projects(projects).Render(c.Request.Context(), c.Writer)
 }
 
 func (h *WebHandler) RenderProjectDetails(c *gin.Context) {
@@ -82,38 +86,111 @@ func (h *WebHandler) RenderProjectDetails(c *gin.Context) {
   var project, err = h.projects.GetBySlug(c, slug)
   if nil != err {
     c.Status(http.StatusNotFound)
-    pages.ProjectDetails(nil).Render(c, c.Writer)
+    pages.ProjectDetails(nil).Render(c.Request.Context(), c.Writer)
     return
   }
-  pages.ProjectDetails(project).Render(c, c.Writer)
+  pages.ProjectDetails(project).Render(c.Request.Context(), c.Writer)
+}
+
+// archiveRPP is the number of articles per page of the archive.
+const archiveRPP = 10
+
+// archiveURL returns the URL of the current archive page, keeping its
+// query string, but pointing to the given page number and cursor.
+func archiveURL(c *gin.Context, page int, cursor string) string {
+  query := c.Request.URL.Query()
+  query.Del("page")
+  query.Del("cursor")
+
+  if 1 < page {
+    query.Set("page", strconv.Itoa(page))
+  }
+
+  if "" != cursor {
+    query.Set("cursor", cursor)
+  }
+
+  if 0 == len(query) {
+    return c.Request.URL.Path
+  }
+
+  return c.Request.URL.Path + "?" + query.Encode()
+}
+
+// archivePageURL returns the URL of the current archive page, keeping
+// its query string, but pointing to the given page number.
+func archivePageURL(c *gin.Context, page int) string {
+  return archiveURL(c, page, "")
+}
+
+// archiveLinks returns the links around the archive page number page.
+// The prev and next links paginate by offset, for crawlers to follow;
+// the more link is where HTMX loads the next page from, and paginates
+// by cursor so that articles pinned or published while scrolling are
+// neither repeated nor skipped. The page number is carried along the
+// cursor only to build the links of the next page. A link is empty if
+// there is no such page.
+func archiveLinks(c *gin.Context, page int, nextCursor string) (prev, next, more string) {
+  if 1 < page {
+    prev = archivePageURL(c, page-1)
+  }
+
+  if "" != nextCursor {
+    next = archivePageURL(c, page+1)
+    more = archiveURL(c, page+1, nextCursor)
+  }
+
+  return prev, next, more
 }
 
 func (h *WebHandler) RenderArchive(c *gin.Context) {
   var (
     anyTopicSentinel      = &model.Topic{ID: "any", Name: "Any topic"}
     search, includeSearch = c.GetQuery("search")
+    _, sorting            = c.GetQuery("sort")
     year, _               = strconv.Atoi(c.Param("year"))
     month, _              = strconv.Atoi(c.Param("month"))
     topic, includeTopic   = c.Params.Get("topic")
     tag, filteringByTag   = c.Params.Get("tag")
-    filter                = &transfer.ArticleFilter{
-      Search:      strings.TrimSpace(search),
-      Topic:       strings.TrimSpace(topic),
-      Tag:         strings.TrimSpace(tag),
-      Publication: &transfer.Publication{Month: time.Month(month), Year: year},
-      Page:        1,
-      RPP:         10000,
-    }
+    filter                = getArticleFilter(c)
   )
 
+  filter.Search = strings.TrimSpace(search)
+  filter.Topic = strings.TrimSpace(topic)
+  filter.Publication = &transfer.Publication{Month: time.Month(month), Year: year}
+  filter.RPP = archiveRPP
+
+  page := max(filter.Page, 1)
+
+  if "" != filter.Cursor {
+    filter.Page = 0
+  } else {
+    filter.Page = page
+  }
+
+  if tag = strings.TrimSpace(tag); "" != tag && !slices.Contains(filter.Tags, tag) {
+    filter.Tags = append(filter.Tags, tag)
+  }
+
+  if !slices.Contains(transfer.ArticleSorts, filter.Sort) {
+    filter.Sort = transfer.SortNewest
+  }
+
   if anyTopicSentinel.ID == topic {
     filter.Topic = ""
   }
 
+  hxRequest, _ := strconv.ParseBool(c.GetHeader("HX-Request"))
+
+  // A request made by HTMX to load more articles or to search them is
+  // answered with the articles alone.
+  fragment := hxRequest && ("" != filter.Cursor || 1 < page || includeSearch || sorting || includeTopic || filteringByTag)
+
   group := errgroup.Group{}
 
   var (
     articles     []*transfer.Article
+    nextCursor   string
     publications []*transfer.Publication
     topics       []*model.Topic
     tags         []*model.Tag
@@ -127,52 +204,55 @@ func (h *WebHandler) RenderArchive(c *gin.Context) {
       return err
     }
 
-    articles = a
+    articles = a.Items
+    nextCursor = a.NextCursor
     return nil
   })
 
-  group.Go(func() error {
-    p, err := h.articles.Publications(c)
+  if !fragment {
+    group.Go(func() error {
+      p, err := h.articles.Publications(c)
 
-    if nil != err {
-      return err
-    }
+      if nil != err {
+        return err
+      }
 
-    publications = p
-    return nil
-  })
+      publications = p
+      return nil
+    })
 
-  group.Go(func() error {
-    t, err := h.topics.List(c)
+    group.Go(func() error {
+      t, err := h.topics.List(c)
 
-    if nil != err {
-      return err
-    }
+      if nil != err {
+        return err
+      }
 
-    topics = t
-    return nil
-  })
+      topics = t
+      return nil
+    })
 
-  group.Go(func() error {
-    t, err := h.tags.List(c)
+    group.Go(func() error {
+      t, err := h.tags.List(c)
 
-    if nil != err {
-      return err
-    }
+      if nil != err {
+        return err
+      }
 
-    tags = t
-    return nil
-  })
+      tags = t
+      return nil
+    })
+  }
 
   if err = group.Wait(); nil != err {
     h.internal(c)
     return
   }
 
-  hxRequest, _ := strconv.ParseBool(c.GetHeader("HX-Request"))
+  prev, next, more := archiveLinks(c, page, nextCursor)
 
-  if hxRequest && (includeSearch || includeTopic || filteringByTag) {
-    ui.SearchResults(articles).Render(c, c.Writer)
+  if fragment {
+    ui.PaginatedSearchResults(articles, next, more).Render(c.Request.Context(), c.Writer)
     return
   }
 
@@ -202,10 +282,14 @@ func (h *WebHandler) RenderArchive(c *gin.Context) {
     topics,
     tags,
     filter.Search,
+    filter.Sort,
     filter.Publication,
     selectedTopic,
     selectedTag,
-  ).Render(c, c.Writer)
+    prev,
+    next,
+    more,
+  ).Render(c.Request.Context(), c.Writer)
 }
 
 func (h *WebHandler) RenderArticle(c *gin.Context) {
@@ -219,13 +303,18 @@ func (h *WebHandler) RenderArticle(c *gin.Context) {
       shareableLink = "/" + shareableLink
     }
 
-    draft, err := h.drafts.GetByLink(c.Request.Context(), shareableLink)
+    token, _ := c.Cookie(shareableLinkCookie)
+    draft, err := h.drafts.GetByLink(c.Request.Context(), shareableLink, token)
 
     if nil != err {
       switch {
       default:
         http.Error(c.Writer, "500 Internal Server Error", http.StatusInternalServerError)
         return
+      case strings.Contains(err.Error(), "protected by a password"):
+        c.Status(http.StatusUnauthorized)
+        pages.ArticleLocked(false).Render(c.Request.Context(), c.Writer)
+        return
       case strings.Contains(err.Error(), "has expired") ||
         strings.Contains(err.Error(), "might have been either removed or blocked."):
         http.Error(c.Writer, "404 Not Found", http.StatusNotFound)
@@ -233,7 +322,7 @@ func (h *WebHandler) RenderArticle(c *gin.Context) {
       }
     }
 
-    pages.Article(draft).Render(c, c.Writer)
+    pages.Article(draft).Render(c.Request.Context(), c.Writer)
     return
   }
 
@@ -263,5 +352,36 @@ func (h *WebHandler) RenderArticle(c *gin.Context) {
     return
   }
 
-  pages.Article(article).Render(c, c.Writer)
+  pages.Article(article).Render(c.Request.Context(), c.Writer)
+}
+
+// UnlockArticle checks the password of a shareable link protected by
+// one and, if it is right, keeps its token in a cookie, so that the
+// draft can be seen, and reviewed, for the rest of the session.
+func (h *WebHandler) UnlockArticle(c *gin.Context) {
+  shareableLink := c.Request.URL.Path
+
+  if '/' != shareableLink[0] {
+    shareableLink = "/" + shareableLink
+  }
+
+  token, err := h.drafts.Unlock(c.Request.Context(), shareableLink, c.PostForm("password"))
+
+  if nil != err {
+    if strings.Contains(err.Error(), "is wrong") {
+      c.Status(http.StatusUnauthorized)
+      pages.ArticleLocked(true).Render(c.Request.Context(), c.Writer)
+      return
+    }
+
+    h.internal(c)
+    return
+  }
+
+  if "" != token {
+    c.SetSameSite(http.SameSiteStrictMode)
+    c.SetCookie(shareableLinkCookie, token, 0, shareableLink, "", true, true)
+  }
+
+  c.Redirect(http.StatusSeeOther, shareableLink)
 }
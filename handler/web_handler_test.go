@@ -0,0 +1,106 @@
+package handler
+
+import (
+  "context"
+  "fontseca.dev/transfer"
+  "github.com/gin-gonic/gin"
+  "github.com/stretchr/testify/assert"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+)
+
+func Test_archivePageURL(t *testing.T) {
+  context := func(target string) *gin.Context {
+    c, _ := gin.CreateTestContext(httptest.NewRecorder())
+    c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+    return c
+  }
+
+  assert.Equal(t, "/archive", archivePageURL(context("/archive"), 1))
+  assert.Equal(t, "/archive?page=2", archivePageURL(context("/archive"), 2))
+  assert.Equal(t, "/archive", archivePageURL(context("/archive?page=2"), 1))
+  assert.Equal(t, "/archive/go?page=3&sort=oldest", archivePageURL(context("/archive/go?sort=oldest&page=2"), 3))
+  assert.Equal(t, "/archive?page=4", archivePageURL(context("/archive?cursor=WyJiIl0&page=3"), 4))
+}
+
+func Test_archiveLinks(t *testing.T) {
+  context := func(target string) *gin.Context {
+    c, _ := gin.CreateTestContext(httptest.NewRecorder())
+    c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+    return c
+  }
+
+  t.Run("first page", func(t *testing.T) {
+    prev, next, more := archiveLinks(context("/archive?sort=oldest"), 1, "WyJiIl0")
+    assert.Empty(t, prev)
+    assert.Equal(t, "/archive?page=2&sort=oldest", next)
+    assert.Equal(t, "/archive?cursor=WyJiIl0&page=2&sort=oldest", more)
+  })
+
+  t.Run("middle page", func(t *testing.T) {
+    prev, next, more := archiveLinks(context("/archive?cursor=WyJhIl0&page=2"), 2, "WyJiIl0")
+    assert.Equal(t, "/archive", prev)
+    assert.Equal(t, "/archive?page=3", next)
+    assert.Equal(t, "/archive?cursor=WyJiIl0&page=3", more)
+  })
+
+  t.Run("last page", func(t *testing.T) {
+    prev, next, more := archiveLinks(context("/archive?page=3"), 3, "")
+    assert.Equal(t, "/archive?page=2", prev)
+    assert.Empty(t, next)
+    assert.Empty(t, more)
+  })
+}
+
+func TestWebHandler_RenderArchive(t *testing.T) {
+  const target = "/archive"
+
+  articles := &transfer.Page[*transfer.Article]{Items: []*transfer.Article{{}, {}}, NextCursor: "WyJjIl0"}
+
+  t.Run("loads more by cursor", func(t *testing.T) {
+    filter := &transfer.ArticleFilter{
+      Publication: &transfer.Publication{},
+      Sort:        transfer.SortNewest,
+      Cursor:      "WyJiIl0",
+      RPP:         archiveRPP,
+    }
+
+    s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), filter}, returns: []any{articles}}
+
+    engine := gin.Default()
+    engine.GET(target, NewWebHandler(nil, nil, nil, nil, s, nil, nil).RenderArchive)
+
+    request := httptest.NewRequest(http.MethodGet, target+"?cursor=WyJiIl0&page=2", nil)
+    request.Header.Set("HX-Request", "true")
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+  })
+
+  t.Run("crawls by page", func(t *testing.T) {
+    filter := &transfer.ArticleFilter{
+      Publication: &transfer.Publication{},
+      Sort:        transfer.SortNewest,
+      Page:        2,
+      RPP:         archiveRPP,
+    }
+
+    s := &articlesServiceMockAPI{t: t, arguments: []any{context.Background(), filter}, returns: []any{articles}}
+
+    engine := gin.Default()
+    engine.GET(target, NewWebHandler(nil, nil, nil, nil, s, nil, nil).RenderArchive)
+
+    request := httptest.NewRequest(http.MethodGet, target+"?page=2", nil)
+    request.Header.Set("HX-Request", "true")
+
+    recorder := httptest.NewRecorder()
+
+    engine.ServeHTTP(recorder, request)
+
+    assert.Equal(t, http.StatusOK, recorder.Code)
+  })
+}
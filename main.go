@@ -5,10 +5,12 @@ import (
   "database/sql"
   "errors"
   "fmt"
+  "fontseca.dev/components/pages"
   "fontseca.dev/handler"
   "fontseca.dev/playground"
   "fontseca.dev/repository"
   "fontseca.dev/service"
+  "fontseca.dev/shortcode"
   "github.com/gin-contrib/gzip"
   "github.com/gin-gonic/gin"
   "github.com/gin-gonic/gin/binding"
@@ -19,6 +21,7 @@ import (
   "log/slog"
   "net"
   "net/http"
+  "net/url"
   "os"
   "os/signal"
   "reflect"
@@ -128,6 +131,7 @@ func main() {
 
   engine.Use(func(c *gin.Context) {
     if http.MethodPost == c.Request.Method &&
+      "/activitypub/inbox" != c.Request.URL.Path &&
       !(strings.Contains(c.ContentType(), "application/x-www-form-urlencoded") ||
         strings.Contains(c.ContentType(), "multipart/form-data")) {
       c.Header("Accept-Post", "application/x-www-form-urlencoded; charset=UTF-8")
@@ -155,6 +159,12 @@ func main() {
     })
   }
 
+  var (
+    idempotencyRepository = repository.NewIdempotencyRepository(db)
+    idempotencyService    = service.NewIdempotencyService(idempotencyRepository)
+    idempotency           = handler.NewIdempotencyHandler(idempotencyService)
+  )
+
   var (
     meRepository = repository.NewMeRepository(db)
     meService    = service.NewMeService(meRepository)
@@ -178,7 +188,7 @@ func main() {
   engine.GET("/me.experience.list", experience.List)
   engine.GET("/me.experience.hidden.list", experience.ListHidden)
   engine.GET("/me.experience.get", experience.Get)
-  engine.POST("/me.experience.create", experience.Create)
+  engine.POST("/me.experience.create", idempotency.Guard, experience.Create)
   engine.POST("/me.experience.set", experience.Set)
   engine.POST("/me.experience.hide", experience.Hide)
   engine.POST("/me.experience.show", experience.Show)
@@ -192,7 +202,7 @@ func main() {
   )
 
   engine.GET("/technologies.list", technologies.List)
-  engine.POST("/technologies.create", technologies.Create)
+  engine.POST("/technologies.create", idempotency.Guard, technologies.Create)
   engine.POST("/technologies.set", technologies.Set)
   engine.POST("/technologies.remove", technologies.Remove)
 
@@ -202,10 +212,12 @@ func main() {
     projects           = handler.NewProjectsHandler(projectsService)
   )
 
+  shortcode.Register("project", shortcode.Project(projectsService.GetBySlug))
+
   engine.GET("/me.projects.list", projects.List)
   engine.GET("/me.projects.get", projects.Get)
   engine.GET("/me.projects.archived.list", projects.ListArchived)
-  engine.POST("/me.projects.create", projects.Create)
+  engine.POST("/me.projects.create", idempotency.Guard, projects.Create)
   engine.POST("/me.projects.set", projects.Set)
   engine.POST("/me.projects.archive", projects.Archive)
   engine.POST("/me.projects.unarchive", projects.Unarchive)
@@ -220,8 +232,23 @@ func main() {
   engine.POST("/me.projects.technologies.add", projects.AddTag)
   engine.POST("/me.projects.technologies.remove", projects.RemoveTag)
 
+  // baseURL is the public URL of the site, which shareable links and the
+  // ActivityPub actor are relative to. If empty, the default one is kept.
+  var baseURL = strings.TrimSpace(os.Getenv("BASE_URL"))
+
+  if "" != baseURL {
+    if u, err := url.Parse(baseURL); nil != err || ("http" != u.Scheme && "https" != u.Scheme) || "" == u.Host {
+      slog.Error("could not parse the base URL of the site, using the default one", slog.String("base_url", baseURL))
+      baseURL = ""
+    }
+  }
+
   var archive = repository.NewArchiveRepository(db)
 
+  if "" != baseURL {
+    archive.SetBaseURL(baseURL)
+  }
+
   var (
     tagsRepository = repository.NewTagsRepository(db)
     tagsService    = service.NewTagsService(tagsRepository)
@@ -244,16 +271,59 @@ func main() {
   engine.POST("/archive.topics.set", topics.Set)
   engine.POST("/archive.topics.remove", topics.Remove)
 
+  var (
+    templatesRepository = repository.NewTemplatesRepository(db)
+    templatesService    = service.NewTemplatesService(templatesRepository)
+    templates           = handler.NewTemplatesHandler(templatesService)
+  )
+
+  engine.POST("/archive.templates.create", templates.Create)
+  engine.GET("/archive.templates.list", templates.List)
+  engine.POST("/archive.templates.set", templates.Set)
+  engine.POST("/archive.templates.remove", templates.Remove)
+
+  var renderingService = service.NewRenderingService(archive, pages.Markdown{})
+
+  var workflow = service.DefaultWorkflow()
+
+  if spec := strings.TrimSpace(os.Getenv("WORKFLOW_TRANSITIONS")); "" != spec {
+    if parsed, err := service.ParseWorkflow(spec); nil != err {
+      slog.Error("could not parse workflow transitions, using the default ones", slog.String("error", err.Error()))
+    } else {
+      workflow = parsed
+    }
+  }
+
   var (
     draftsService = service.NewDraftsService(archive)
     drafts        = handler.NewDraftsHandler(draftsService)
   )
 
-  engine.POST("/archive.drafts.start", drafts.Start)
+  var (
+    feedbackRepository = repository.NewFeedbackRepository(db)
+    feedbackService    = service.NewFeedbackService(feedbackRepository, pages.Markdown{})
+    feedback           = handler.NewFeedbackHandler(feedbackService)
+  )
+
+  draftsService.SetRenderer(renderingService)
+  draftsService.SetWorkflow(workflow)
+  draftsService.SetFeedback(feedbackService)
+  draftsService.SetTemplates(templatesService)
+
+  engine.POST("/archive.drafts.start", idempotency.Guard, drafts.Start)
   engine.POST("/archive.drafts.publish", drafts.Publish)
   engine.GET("/archive.drafts.list", drafts.List)
   engine.GET("/archive.drafts.get", drafts.Get)
+  engine.GET("/archive.drafts.lint", drafts.Lint)
+  engine.POST("/archive.drafts.transition", drafts.Transition)
+  engine.GET("/archive.drafts.history", drafts.History)
+  engine.GET("/archive.drafts.feedback.list", feedback.List)
+  engine.POST("/archive.drafts.feedback.resolve", feedback.Resolve)
+  engine.POST("/archive/sharing/:hash/feedback", feedback.Leave)
   engine.POST("/archive.drafts.share", drafts.Share)
+  engine.GET("/archive.drafts.links.list", drafts.Links)
+  engine.POST("/archive.drafts.links.revoke", drafts.RevokeLink)
+  engine.POST("/archive.drafts.links.extend", drafts.ExtendLink)
   engine.POST("/archive.drafts.revise", drafts.Revise)
   engine.POST("/archive.drafts.discard", drafts.Discard)
   engine.POST("/archive.drafts.tags.add", drafts.AddTag)
@@ -264,6 +334,34 @@ func main() {
     articles        = handler.NewArticlesHandler(articlesService)
   )
 
+  if years, err := strconv.Atoi(strings.TrimSpace(os.Getenv("OUTDATED_AFTER_YEARS"))); nil == err {
+    var topics []string
+
+    for _, topic := range strings.Split(os.Getenv("OUTDATED_TOPICS"), ",") {
+      if topic = strings.TrimSpace(topic); "" != topic {
+        topics = append(topics, topic)
+      }
+    }
+
+    articlesService.SetOutdatedRule(&service.OutdatedRule{Years: years, Topics: topics})
+  }
+
+  articlesService.SetRenderer(renderingService)
+
+  schedulerCtx, schedulerCtxCanceler := context.WithCancel(context.Background())
+  go articlesService.RunScheduler(schedulerCtx, time.Minute)
+
+  go func() {
+    rendered, err := renderingService.Backfill(schedulerCtx)
+    if nil != err {
+      slog.Error("could not render stale articles", slog.String("error", err.Error()))
+    }
+
+    if 0 < rendered {
+      slog.Info("rendered stale articles", slog.Int("count", rendered))
+    }
+  }()
+
   engine.GET("/archive.articles.list", articles.List)
   engine.GET("/archive.articles.hidden.list", articles.ListHidden)
   engine.GET("/archive.articles.get", articles.Get)
@@ -274,22 +372,129 @@ func main() {
   engine.POST("/archive.articles.hide", articles.Hide)
   engine.POST("/archive.articles.show", articles.Show)
   engine.POST("/archive.articles.remove", articles.Remove)
+  engine.POST("/archive.articles.mark_outdated", articles.MarkOutdated)
+  engine.POST("/archive.articles.unmark_outdated", articles.UnmarkOutdated)
   engine.POST("/archive.articles.pin", articles.Pin)
   engine.POST("/archive.articles.unpin", articles.Unpin)
   engine.POST("/archive.articles.tags.add", articles.AddTag)
   engine.POST("/archive.articles.tags.remove", articles.RemoveTag)
+  engine.POST("/archive.articles.syndication.add", articles.AddSyndication)
+  engine.POST("/archive.articles.syndication.remove", articles.RemoveSyndication)
 
   var (
     patchesServices = service.NewPatchesService(archive)
     patches         = handler.NewPatchesHandler(patchesServices)
   )
 
+  patchesServices.SetRenderer(renderingService)
+  patchesServices.SetWorkflow(workflow)
+  patchesServices.SetFeedback(feedbackService)
+
   engine.GET("/archive.articles.patches.list", patches.List)
   engine.POST("/archive.articles.patches.revise", patches.Revise)
   engine.POST("/archive.articles.patches.share", patches.Share)
   engine.POST("/archive.articles.patches.discard", patches.Discard)
   engine.POST("/archive.articles.patches.release", patches.Release)
 
+  deliveryCtx, deliveryCtxCanceler := context.WithCancel(context.Background())
+
+  if keyFile := strings.TrimSpace(os.Getenv("ACTIVITYPUB_PRIVATE_KEY_FILE")); "" == keyFile {
+    fmt.Println("warn: environment `ACTIVITYPUB_PRIVATE_KEY_FILE` variable not found, the archive will not federate")
+  } else {
+    data, err := os.ReadFile(keyFile)
+    if nil != err {
+      log.Fatal(err)
+    }
+
+    key, err := service.ParsePrivateKey(data)
+    if nil != err {
+      log.Fatalf("could not parse ActivityPub private key: %v", err)
+    }
+
+    var (
+      followersRepository = repository.NewFollowersRepository(db)
+      federationService   = service.NewFederationService(archive, followersRepository, key, pages.Markdown{})
+      federation          = handler.NewFederationHandler(federationService)
+    )
+
+    if "" != baseURL {
+      federationService.SetBaseURL(baseURL)
+    }
+
+    draftsService.SetFederator(federationService)
+    patchesServices.SetFederator(federationService)
+
+    go federationService.RunDelivery(deliveryCtx, 4)
+
+    engine.GET("/.well-known/webfinger", federation.WebFinger)
+    engine.GET("/activitypub/actor", federation.Actor)
+    engine.GET("/activitypub/outbox", federation.Outbox)
+    engine.GET("/activitypub/followers", federation.Followers)
+    engine.GET("/activitypub/articles/:article_uuid", federation.Article)
+    engine.POST("/activitypub/inbox", federation.Inbox)
+  }
+
+  var ogImagesDir = strings.TrimSpace(os.Getenv("OG_IMAGES_DIR"))
+  if "" == ogImagesDir {
+    ogImagesDir = "og-images"
+  }
+
+  var (
+    ogImagesService = service.NewOGImageService(articlesService, projectsService, ogImagesDir)
+    ogImages        = handler.NewOGImageHandler(ogImagesService)
+  )
+
+  engine.GET("/og/archive/:article_uuid", ogImages.Article)
+  engine.GET("/og/work/:project_slug", ogImages.Project)
+
+  var mediaDir = strings.TrimSpace(os.Getenv("MEDIA_DIR"))
+  if "" == mediaDir {
+    mediaDir = "media"
+  }
+
+  var (
+    mediaRepository = repository.NewMediaRepository(db)
+    mediaService    = service.NewMediaService(mediaRepository, mediaDir)
+    media           = handler.NewMediaHandler(mediaService)
+  )
+
+  engine.POST("/media.upload", media.Upload)
+  engine.GET("/media.list", media.List)
+  engine.GET("/media.get", media.Get)
+  engine.POST("/media.remove", media.Remove)
+  engine.GET("/media/:hash", media.Serve)
+
+  var imagesDir = strings.TrimSpace(os.Getenv("IMAGES_DIR"))
+  if "" == imagesDir {
+    imagesDir = "images"
+  }
+
+  var (
+    imagesService = service.NewImageService(mediaService, imagesDir)
+    images        = handler.NewImageHandler(imagesService)
+  )
+
+  mediaService.SetDerivatives(imagesService)
+
+  engine.GET("/img/:hash", images.Serve)
+
+  var (
+    linksRepository = repository.NewLinksRepository(db)
+    linksService    = service.NewLinksService(linksRepository, pages.Markdown{})
+    links           = handler.NewLinksHandler(linksService)
+  )
+
+  go linksService.RunChecker(schedulerCtx, 24*time.Hour)
+
+  engine.GET("/links.report", links.Report)
+
+  var (
+    oEmbedService = service.NewOEmbedService(archive, projectsService)
+    oEmbed        = handler.NewOEmbedHandler(oEmbedService)
+  )
+
+  engine.GET("/oembed", oEmbed.Resolve)
+
   var web = handler.NewWebHandler(
     meService,
     experienceService,
@@ -300,16 +505,19 @@ func main() {
     tagsService,
   )
 
-  engine.GET("/", web.RenderMe)
-  engine.GET("/experience", web.RenderExperience)
-  engine.GET("/work", web.RenderProjects)
-  engine.GET("/work/:project_slug", web.RenderProjectDetails)
-  engine.GET("/archive", web.RenderArchive)
-  engine.GET("/archive/:topic", web.RenderArchive)
-  engine.GET("/archive/:topic/:year/:month", web.RenderArchive)
-  engine.GET("/archive/tag/:tag", web.RenderArchive)
-  engine.GET("/archive/:topic/:year/:month/:slug", web.RenderArticle)
-  engine.GET("/archive/sharing/:hash", web.RenderArticle)
+  var rendered = engine.Group("", handler.ContentSecurityPolicy)
+
+  rendered.GET("/", web.RenderMe)
+  rendered.GET("/experience", web.RenderExperience)
+  rendered.GET("/work", web.RenderProjects)
+  rendered.GET("/work/:project_slug", web.RenderProjectDetails)
+  rendered.GET("/archive", web.RenderArchive)
+  rendered.GET("/archive/:topic", web.RenderArchive)
+  rendered.GET("/archive/:topic/:year/:month", web.RenderArchive)
+  rendered.GET("/archive/tag/:tag", web.RenderArchive)
+  rendered.GET("/archive/:topic/:year/:month/:slug", web.RenderArticle)
+  rendered.GET("/archive/sharing/:hash", web.RenderArticle)
+  rendered.POST("/archive/sharing/:hash", web.UnlockArticle)
 
   playgroundCtx, playgroundCtxCanceler := context.WithCancel(context.Background())
   engine.POST("/playground.request", func(c *gin.Context) {
@@ -411,6 +619,8 @@ func main() {
     ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
     defer cancel()
 
+    schedulerCtxCanceler()
+    deliveryCtxCanceler()
     archive.Close(ctx)
     playgroundCtxCanceler()
 
@@ -21,6 +21,7 @@ type Article struct {
   Author      string     `json:"author"`
   Views       int64      `json:"views"`
   ReadTime    int        `json:"read_time"`
+  WordCount   int        `json:"word_count"`
   IsDraft     bool       `json:"is_draft"`
   IsPinned    bool       `json:"is_pinned"`
   PublishedAt *time.Time `json:"published_at"`
@@ -34,7 +35,81 @@ type Article struct {
   CoverCap    *string    `json:"cover_caption"`
   Content     string     `json:"content"`
 
+  // TableOfContents is the tree of the headings in Content.
+  TableOfContents []*Heading `json:"table_of_contents"`
+
   DownloadFiles []DownloadFile `json:"download_files"`
+
+  Outdated *OutdatedNotice `json:"outdated"`
+
+  // CanonicalURL is the URL where the article was first published, if
+  // it was not here. Syndication holds the copies published elsewhere.
+  CanonicalURL *string       `json:"canonical_url"`
+  Syndication  []Syndication `json:"syndication"`
+
+  // Version is incremented by every revision, so that a revision made
+  // upon an older one can be told apart and refused.
+  Version int `json:"version"`
+
+  // Rendering is Content rendered into HTML when the article was last
+  // published, revised or released, if it is up to date.
+  Rendering *Rendering `json:"-"`
+}
+
+// Rendering is the content of an article rendered into HTML, along with
+// what is computed from it. Version is the version of the renderer, so
+// that renderings made by an older one can be told apart.
+type Rendering struct {
+  HTML            string
+  TableOfContents []*Heading
+  WordCount       int
+  Version         int
+}
+
+// Heading is an entry of the table of contents of an article. ID is the
+// anchor of the heading in the rendered content.
+type Heading struct {
+  ID       string     `json:"id"`
+  Title    string     `json:"title"`
+  Level    int        `json:"level"`
+  Children []*Heading `json:"children"`
+}
+
+// Syndication is a copy of an article published on another platform.
+type Syndication struct {
+  URL       string    `json:"url"`
+  Name      *string   `json:"name"` // of the platform, e.g.: 'DEV Community'
+  CreatedAt time.Time `json:"created_at"`
+}
+
+// OutdatedNotice warns readers that an article is no longer accurate and,
+// if there is one, points them to the article that supersedes it.
+type OutdatedNotice struct {
+  Reason      string    `json:"reason"`
+  MarkedAt    time.Time `json:"marked_at"`
+  Replacement *struct {
+    UUID  uuid.UUID `json:"uuid"`
+    Title string    `json:"title"`
+    URL   string    `json:"url"` // in the form: '/archive/:topic/:year/:month/:slug'
+  } `json:"replacement"`
+}
+
+// WorkflowTransition is a move of a draft or a patch from one state of
+// the editorial workflow to another.
+type WorkflowTransition struct {
+  From           string    `json:"from"`
+  To             string    `json:"to"`
+  Actor          string    `json:"actor"` // who made the transition
+  Comment        *string   `json:"comment"`
+  TransitionedAt time.Time `json:"transitioned_at"`
+}
+
+// WorkflowHistory is the state of a draft or a patch in the editorial
+// workflow, along with the transitions that led to it, from the oldest
+// to the newest.
+type WorkflowHistory struct {
+  State       string                `json:"state"`
+  Transitions []*WorkflowTransition `json:"transitions"`
 }
 
 // ArticlePatch is a patch for a published article.
@@ -45,4 +120,8 @@ type ArticlePatch struct {
   ReadTime    *int      `json:"-"`
   TopicID     *string   `json:"topic_id"`
   Content     *string   `json:"content"`
+
+  CanonicalURL *string `json:"canonical_url"`
+
+  Version int `json:"version"`
 }
@@ -19,4 +19,5 @@ type Experience struct {
   Hidden          bool       `json:"hidden"`
   CreatedAt       time.Time  `json:"created_at"`
   UpdatedAt       time.Time  `json:"updated_at"`
+  Version         int        `json:"version"`
 }
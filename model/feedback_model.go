@@ -0,0 +1,20 @@
+package model
+
+import (
+  "time"
+)
+
+// Feedback is a comment a reviewer left on a shared draft or patch,
+// anchored to one of the paragraphs or headings of its content and,
+// optionally, to a selection of its text.
+type Feedback struct {
+  UUID       string     `json:"uuid"`
+  Anchor     string     `json:"anchor"`      // the ID of the paragraph or heading, like 'p-3' or 'getting-started'
+  AnchorText string     `json:"anchor_text"` // the text of the paragraph or heading when it was last anchored
+  Selection  *string    `json:"selection"`
+  Reviewer   string     `json:"reviewer"` // the display name of the reviewer
+  Comment    string     `json:"comment"`
+  Orphaned   bool       `json:"orphaned"` // whether its anchor was revised away and no other one matches
+  ResolvedAt *time.Time `json:"resolved_at"`
+  CreatedAt  time.Time  `json:"created_at"`
+}
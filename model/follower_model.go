@@ -0,0 +1,14 @@
+package model
+
+import (
+  "time"
+)
+
+// Follower is a fediverse actor that follows the archive and
+// receives its new articles through ActivityPub.
+type Follower struct {
+  Actor       string    `json:"actor"` // the ID of the actor, e.g.: 'https://mastodon.social/users/fontseca'
+  Inbox       string    `json:"inbox"`
+  SharedInbox *string   `json:"shared_inbox"`
+  FollowedAt  time.Time `json:"followed_at"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+  "time"
+)
+
+// IdempotentResponse is the response to the first execution of a
+// request made with an idempotency key, which is replayed on repeats of
+// that request. Status is zero while the first execution is in progress.
+type IdempotentResponse struct {
+  Key         string
+  Method      string // the RPC method, e.g.: 'archive.drafts.start'
+  Fingerprint string // a hash of the request, to tell a repeat from a reuse of the key
+  Status      int
+  ContentType string
+  Body        []byte
+  CreatedAt   time.Time
+}
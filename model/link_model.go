@@ -0,0 +1,29 @@
+package model
+
+import (
+  "time"
+)
+
+// LinkSource is an article or a project whose links are checked: the
+// links of its content, once rendered, and the URLs of its fields.
+type LinkSource struct {
+  Type    string   // either 'article' or 'project'
+  ID      string   // the UUID of an article or the slug of a project
+  Title   string   // the title of an article or the name of a project
+  Content string   // in Markdown
+  HTML    *string  // the stored rendering of the content, if any
+  URLs    []string // like the homepage of a project or its GitHub URL
+}
+
+// Link is an outbound link of an article or a project, along with the
+// result of its last check.
+type Link struct {
+  SourceType  string
+  SourceID    string
+  SourceTitle string
+  URL         string
+  Status      *int    // the HTTP status code of the response, if any
+  Error       *string // why the link could not be reached, if it could not
+  Broken      bool
+  CheckedAt   time.Time
+}
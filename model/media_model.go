@@ -0,0 +1,20 @@
+package model
+
+import (
+  "time"
+)
+
+// Media is a file of the media library, like an image or a document.
+// Files are named after the SHA-256 hash of their content, so the same
+// file is stored only once.
+type Media struct {
+  Hash       string    `json:"hash"`
+  URL        string    `json:"url"` // in the form: '/media/:hash'
+  Name       string    `json:"name"`
+  MIMEType   string    `json:"mime_type"`
+  Size       int64     `json:"size"`   // in bytes
+  Width      *int      `json:"width"`  // of images only
+  Height     *int      `json:"height"` // of images only
+  AltText    *string   `json:"alt_text"`
+  UploadedAt time.Time `json:"uploaded_at"`
+}
@@ -30,4 +30,5 @@ type Project struct {
   TechnologyTags  []string   `json:"technology_tags"`
   CreatedAt       time.Time  `json:"created_at"`
   UpdatedAt       time.Time  `json:"updated_at"`
+  Version         int        `json:"version"`
 }
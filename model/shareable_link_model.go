@@ -0,0 +1,17 @@
+package model
+
+import (
+  "time"
+)
+
+// ShareableLink is a temporary link through which a draft or a patch
+// can be seen, and reviewed, by those who have it.
+type ShareableLink struct {
+  UUID      string    `json:"uuid"`
+  Label     string    `json:"label"` // who or what the link is for, like the name of a reviewer
+  Link      string    `json:"shareable_link"`
+  Protected bool      `json:"protected"` // whether a password is needed to see the draft or the patch
+  Views     int       `json:"views"`
+  CreatedAt time.Time `json:"created_at"`
+  ExpiresAt time.Time `json:"expires_at"`
+}
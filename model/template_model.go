@@ -0,0 +1,23 @@
+package model
+
+import (
+  "github.com/google/uuid"
+  "time"
+)
+
+// Template is the skeleton of a recurring article format, like
+// post-mortems or book notes, that article drafts can be started with.
+//
+// In TitlePattern, '{title}' stands for the title the draft is started
+// with and '{date}' for the date it is started on.
+type Template struct {
+  UUID         uuid.UUID `json:"uuid"`
+  Name         string    `json:"name"`
+  TitlePattern string    `json:"title_pattern"`
+  Summary      *string   `json:"summary"`
+  Content      *string   `json:"content"`
+  TopicID      *string   `json:"topic_id"`
+  Tags         []string  `json:"tags"` // the IDs of the tags attached to the drafts
+  CreatedAt    time.Time `json:"created_at"`
+  UpdatedAt    time.Time `json:"updated_at"`
+}
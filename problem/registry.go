@@ -17,6 +17,8 @@ const (
   TypeDuplicateKey                = "duplicate_key"
   TypeActionAlreadyCompleted      = "action_already_completed"
   TypeActionRefused               = "action_refused"
+  TypeConflict                    = "conflict"
+  TypeStaleCursor                 = "stale_cursor"
 )
 
 func NewInternal() *Problem {
@@ -40,6 +42,28 @@ func NewNotFound(id, recordType string) *Problem {
   return &p
 }
 
+func NewConflict(id, recordType string, currentVersion int) *Problem {
+  var p Problem
+  p.Type(TypeConflict)
+  p.Status(http.StatusConflict)
+  p.Title("Stale version.")
+  p.Detail(fmt.Sprintf("The %s record with UUID '%s' was modified since it was read. Please fetch its current version and try again.", recordType, id))
+  p.With("record_uuid", id)
+  p.With("record_type", recordType)
+  p.With("current_version", currentVersion)
+  return &p
+}
+
+func NewStaleCursor(cursor string) *Problem {
+  var p Problem
+  p.Type(TypeStaleCursor)
+  p.Status(http.StatusGone)
+  p.Title("Stale cursor.")
+  p.Detail("The record this cursor points to no longer exists. Please start over from the first page.")
+  p.With("cursor", cursor)
+  return &p
+}
+
 func NewSlugNotFound(slug, recordType string) *Problem {
   var p Problem
   p.Type(TypeNotFound)
@@ -4,8 +4,36 @@ document.addEventListener("DOMContentLoaded", function () {
 
   linkCopiers.forEach(copyArticleLink);
   images.forEach(openImageInViewer);
+  document.querySelectorAll("article.content[data-feedback]").forEach(enableFeedback);
 });
 
+/* The Content-Security-Policy does not allow inline event handlers, so
+   elements name their handler in a `data-on<event>` attribute instead.
+   Handlers are called in the capture phase so that, like inline ones,
+   they run before the listeners htmx adds to the element.  */
+const eventHandlers = {
+  toggleNavigationSidebar,
+  searchArticles,
+  sortArticles,
+  setArchiveTopic,
+  setArchivePublicationDate,
+  setArchiveTag,
+};
+
+for (const type of ["click", "input", "change"]) {
+  document.addEventListener(type, (e) => {
+    const element = e.target.closest(`[data-on${type}]`);
+    if (element == null) {
+      return;
+    }
+
+    const handler = eventHandlers[element.dataset[`on${type}`]];
+    if (handler != null) {
+      handler(element);
+    }
+  }, true);
+}
+
 function openImageInViewer(img) {
   img.addEventListener("click", handleImageClicked.bind(this, img));
 }
@@ -135,19 +163,34 @@ function toggleNavigationSidebar() {
   backdrop.classList.toggle("show")
 }
 
-function searchArticles(e) {
-  const query = e.value.trim();
-  let newURL = window.location.pathname;
+function archiveURL() {
+  const params = new URLSearchParams();
+  const query = document.getElementById("searchbar").value.trim();
+  const sort = document.getElementById("sort").value;
 
   if ("" !== query) {
-    newURL += "?search=" + query;
+    params.set("search", query);
   }
 
+  if ("" !== sort && "newest" !== sort) {
+    params.set("sort", sort);
+  }
+
+  const search = params.toString();
+  return window.location.pathname + ("" !== search ? "?" + search : "");
+}
+
+function searchArticles(e) {
+  const newURL = archiveURL();
   e.setAttribute("hx-get", newURL);
   e.setAttribute("hx-push-url", newURL);
   window.history.replaceState({}, "", newURL);
 }
 
+function sortArticles(e) {
+  searchArticles(e);
+}
+
 function setArchiveTopic(e) {
   document.querySelector("h3.topic-and-date").classList.remove("hide");
   document.querySelector("div.selected-tag-div").classList.add("hide");
@@ -269,3 +312,120 @@ function setArchiveTag(e) {
 
   htmx.process(document.body);
 }
+
+/* Shared drafts and patches take feedback on their paragraphs and
+   headings. Paragraphs are anchored by their position among the <p>
+   elements of the content, 'p-1' being the first one, and headings by
+   their IDs; the server identifies them the same way.  */
+function enableFeedback(content) {
+  content.querySelectorAll("p").forEach((p, i) => {
+    p.dataset.anchor = `p-${i + 1}`;
+  });
+
+  content.querySelectorAll("h1[id], h2[id], h3[id], h4[id], h5[id], h6[id]").forEach((h) => {
+    h.dataset.anchor = h.id;
+  });
+
+  content.querySelectorAll("[data-anchor]").forEach((block) => {
+    const button = document.createElement("button");
+    button.type = "button";
+    button.classList.add("feedback-button");
+    button.title = "Leave feedback";
+    button.textContent = "+";
+
+    /* Keep the selection, which a click on the button would clear.  */
+    button.addEventListener("mousedown", (e) => e.preventDefault());
+    button.addEventListener("click", () => openFeedbackDialog(block));
+
+    block.classList.add("feedback-anchor");
+    block.appendChild(button);
+  });
+}
+
+function openFeedbackDialog(block) {
+  const selection = window.getSelection();
+  let selected = "";
+
+  if (selection != null && !selection.isCollapsed && block.contains(selection.anchorNode) && block.contains(selection.focusNode)) {
+    selected = selection.toString().replace(/\s+/g, " ").trim();
+  }
+
+  const dialog = document.createElement("dialog");
+  const form = document.createElement("form");
+  const quote = document.createElement("blockquote");
+  const reviewer = document.createElement("input");
+  const comment = document.createElement("textarea");
+  const status = document.createElement("p");
+  const actions = document.createElement("div");
+  const cancel = document.createElement("button");
+  const submit = document.createElement("button");
+
+  dialog.classList.add("feedback-dialog");
+
+  quote.textContent = selected !== "" ? selected : block.textContent.replace(/\+$/, "").trim();
+
+  reviewer.name = "reviewer";
+  reviewer.placeholder = "Your name";
+  reviewer.maxLength = 64;
+  reviewer.required = true;
+  reviewer.value = localStorage.getItem("feedback-reviewer") ?? "";
+
+  comment.name = "comment";
+  comment.placeholder = "Your feedback";
+  comment.maxLength = 2048;
+  comment.required = true;
+  comment.rows = 5;
+
+  status.classList.add("small");
+
+  cancel.type = "button";
+  cancel.textContent = "Cancel";
+  cancel.addEventListener("click", () => dialog.close());
+
+  submit.type = "submit";
+  submit.textContent = "Send";
+
+  actions.classList.add("actions");
+  actions.append(cancel, submit);
+  form.append(quote, reviewer, comment, status, actions);
+  dialog.appendChild(form);
+
+  form.addEventListener("submit", async (e) => {
+    e.preventDefault();
+
+    const body = new URLSearchParams({
+      anchor: block.dataset.anchor,
+      reviewer: reviewer.value,
+      comment: comment.value,
+    });
+
+    if (selected !== "") {
+      body.set("selection", selected);
+    }
+
+    submit.disabled = true;
+
+    try {
+      const response = await fetch(window.location.pathname.replace(/\/$/, "") + "/feedback", {method: "POST", body});
+      if (!response.ok) {
+        const problem = await response.json().catch(() => null);
+        status.textContent = problem?.detail ?? "Your feedback could not be sent.";
+        submit.disabled = false;
+        return;
+      }
+
+      localStorage.setItem("feedback-reviewer", reviewer.value);
+      dialog.close();
+    } catch (error) {
+      console.error(error);
+      status.textContent = "Your feedback could not be sent.";
+      submit.disabled = false;
+    }
+  });
+
+  dialog.addEventListener("close", () => dialog.remove());
+
+  document.body.appendChild(dialog);
+  dialog.showModal();
+  (reviewer.value === "" ? reviewer : comment).focus();
+}
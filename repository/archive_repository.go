@@ -4,6 +4,7 @@ import (
   "context"
   "crypto/sha256"
   "database/sql"
+  "encoding/json"
   "errors"
   "fmt"
   "fontseca.dev/model"
@@ -11,6 +12,7 @@ import (
   "fontseca.dev/transfer"
   "github.com/gin-gonic/gin"
   "github.com/google/uuid"
+  "github.com/lib/pq"
   "log/slog"
   "net/http"
   "net/url"
@@ -56,6 +58,7 @@ type ArchiveRepository struct {
   done              chan struct{}
   mu                sync.RWMutex
   cleanOnce         sync.Once // for cleaning broken links once per share
+  baseURL           string    // prepended to shareable links
 }
 
 // visitor is the IP address of an article reader.
@@ -76,6 +79,7 @@ func NewArchiveRepository(db *sql.DB) *ArchiveRepository {
     publicationsCache: []*transfer.Publication{},
     articleViewsCache: articleViewsCache{},
     done:              make(chan struct{}),
+    baseURL:           "https://fontseca.dev",
   }
 
   go r.cacheWriter()
@@ -83,6 +87,12 @@ func NewArchiveRepository(db *sql.DB) *ArchiveRepository {
   return r
 }
 
+// SetBaseURL sets the URL, like 'https://fontseca.dev', shareable links
+// are relative to.
+func (r *ArchiveRepository) SetBaseURL(base string) {
+  r.baseURL = strings.TrimSuffix(base, "/")
+}
+
 // cacheWriter is a goroutine that writes articles view cache every midnight.
 func (r *ArchiveRepository) cacheWriter() {
   var (
@@ -354,7 +364,8 @@ func (r *ArchiveRepository) Draft(ctx context.Context, creation *transfer.Articl
                                    "content",
                                    "summary",
                                    "cover_url",
-                                   "cover_caption")
+                                   "cover_caption",
+                                   "topic")
                  VALUES ($1,
                          'fontseca',
                          $2,
@@ -362,7 +373,8 @@ func (r *ArchiveRepository) Draft(ctx context.Context, creation *transfer.Articl
                          coalesce(nullif($4, ''), 'no content'),
                          coalesce(nullif($5, ''), 'no summary'),
                          coalesce(nullif($6, ''), 'about:blank'),
-                         nullif($7, ''))
+                         nullif($7, ''),
+                         nullif($8, ''))
               RETURNING "uuid";`
 
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -376,6 +388,7 @@ func (r *ArchiveRepository) Draft(ctx context.Context, creation *transfer.Articl
     &creation.Summary,
     &creation.CoverURL,
     &creation.CoverCap,
+    &creation.Topic,
   )
 
   if err = result.Scan(&id); nil != err {
@@ -400,10 +413,34 @@ func (r *ArchiveRepository) Draft(ctx context.Context, creation *transfer.Articl
   return id, nil
 }
 
-// Publish makes a draft publicly available.
+// IsPublished reports whether the article with the UUID id is already
+// published. It is false for drafts and for articles that do not exist.
+func (r *ArchiveRepository) IsPublished(ctx context.Context, id string) (published bool, err error) {
+  isArticlePublishedQuery := `
+  SELECT count(*)
+    FROM "archive"."article"
+   WHERE "uuid" = $1
+     AND ("draft" IS FALSE
+      OR "published_at" IS NOT NULL);`
+
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  err = r.db.QueryRowContext(ctx, isArticlePublishedQuery, id).Scan(&published)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return false, err
+  }
+
+  return published, nil
+}
+
+// Publish makes a draft publicly available. The draft must belong to a
+// topic, since the URL of the article depends on it; any other check is
+// left to the linting of the service, which can be forced past.
 //
-// Invoking Publish on an already published article or a patch has
-// no effect.
+// Invoking Publish on an already published article or a patch
+// returns a conflict.
 func (r *ArchiveRepository) Publish(ctx context.Context, id string) error {
   isArticleDraftQuery := `
   SELECT "draft" IS TRUE
@@ -470,49 +507,6 @@ func (r *ArchiveRepository) Publish(ctx context.Context, id string) error {
     return p
   }
 
-  /* Check article draft has a summary and a cover image (#42).   */
-
-  getSummaryAndCoverQuery := `
-  SELECT "summary" <> 'no summary' AND length("summary") >= 120,
-         "cover_url" <> 'about:blank'
-    FROM  "archive"."article"
-   WHERE "uuid" = $1
-     AND "draft" IS TRUE
-     AND "published_at" IS NULL;`
-
-  var (
-    hasSummary  bool
-    hasCoverURL bool
-  )
-
-  ctx1, cancel = context.WithTimeout(ctx, 7*time.Second)
-  defer cancel()
-
-  err = r.db.QueryRowContext(ctx1, getSummaryAndCoverQuery, id).Scan(&hasSummary, &hasCoverURL)
-  if nil != err {
-    slog.Error(getErrMsg(err))
-    return err
-  }
-
-  if !hasSummary || !hasCoverURL {
-    p := &problem.Problem{}
-    p.Type(problem.TypeActionRefused)
-    p.Status(http.StatusUnprocessableEntity)
-    p.Title("Could not publish draft.")
-    p.With("draft_uuid", id)
-
-    switch {
-    case !hasSummary:
-      p.Detail("Cannot publish a draft without a valid summary.")
-      p.With("cannot", "be less than 100 characters long")
-      p.With("cannot", "be an empty string")
-    case !hasCoverURL:
-      p.Detail("Cannot publish a draft without a valid cover URL.")
-    }
-
-    return p
-  }
-
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
     slog.Error(getErrMsg(err))
@@ -742,7 +736,32 @@ func (r *ArchiveRepository) Publications(ctx context.Context) (publications []*t
 // function over non-hidden articles, so it attempts to find and
 // amass every article whose title contains any of the keywords
 // (if more than one) in the search string.
-func (r *ArchiveRepository) List(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (articles []*transfer.Article, err error) {
+//
+// Articles are sorted according to filter.Sort, and filter.Cursor encodes
+// the sort key of the last article of the previous page; drafts use their
+// drafted_at instead of their publication date. If filter.Page is greater
+// than zero, List skips the cursor and falls back to offset pagination.
+func (r *ArchiveRepository) List(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (page *transfer.Page[*transfer.Article], err error) {
+  var (
+    sortKey     = `coalesce(a."published_at", a."drafted_at")`
+    ascending   = false
+    pinnedFirst = false
+    numericKey  = false
+  )
+
+  switch filter.Sort {
+  default:
+    pinnedFirst = true
+  case transfer.SortOldest:
+    ascending = true
+  case transfer.SortMostViewed:
+    sortKey, numericKey = `a."views"`, true
+  case transfer.SortRecentlyModified:
+    sortKey = `coalesce(a."modified_at", a."published_at", a."drafted_at")`
+  case transfer.SortReadingTime:
+    sortKey, ascending, numericKey = `a."read_time"`, true, true
+  }
+
   query := strings.Builder{}
   query.WriteString(`
   SELECT a."uuid",
@@ -753,16 +772,11 @@ func (r *ArchiveRepository) List(ctx context.Context, filter *transfer.ArticleFi
          a."published_at",
          tp."name",
          a."summary",
-         a."cover_url"
+         a."cover_url",
+         a."outdated_at" IS NOT NULL,
+         ` + sortKey + `
     FROM "archive"."article" a
-  LEFT JOIN "archive"."topic" tp ON tp."id" = a."topic"`)
-
-  if "" != filter.Tag {
-    query.WriteString(`
-    INNER JOIN "archive"."article_tag" t ON t."article_uuid" = a."uuid"`)
-  }
-
-  query.WriteString(`
+  LEFT JOIN "archive"."topic" tp ON tp."id" = a."topic"
    WHERE "draft" = $1
      AND CASE WHEN $1 = TRUE
               THEN "published_at" IS NULL
@@ -779,14 +793,6 @@ func (r *ArchiveRepository) List(ctx context.Context, filter *transfer.ArticleFi
                    ELSE TRUE END
                END`)
 
-  if "" != filter.Tag {
-    query.WriteString(`
-               AND t."tag_id" = $8`)
-  } else {
-    query.WriteString(`
-               AND length($8) >= 0`)
-  }
-
   if "" != filter.Search {
     for _, chunk := range strings.Fields(filter.Search) {
       if strings.Contains(chunk, "'") {
@@ -797,14 +803,10 @@ func (r *ArchiveRepository) List(ctx context.Context, filter *transfer.ArticleFi
     }
   }
 
-  query.WriteString(`
-  ORDER BY "pinned" DESC, "published_at" DESC
-  LIMIT $4
-  OFFSET $4 * ($3 - 1);`)
-
   var (
     year  = 0
     month = 0
+    pg    = 1
   )
 
   if nil != filter.Publication {
@@ -812,20 +814,137 @@ func (r *ArchiveRepository) List(ctx context.Context, filter *transfer.ArticleFi
     month = int(filter.Publication.Month)
   }
 
-  ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
-  defer cancel()
+  if 0 < filter.Page {
+    pg = filter.Page
+  }
 
-  result, err := r.db.QueryContext(ctx, query.String(),
+  arguments := []any{
     draftsOnly,
     hidden,
-    filter.Page,
+    pg,
     filter.RPP,
     filter.Topic,
     year,
     month,
-    filter.Tag,
+  }
+
+  // argument appends value to the arguments of the query and
+  // returns its placeholder.
+  argument := func(value any) string {
+    arguments = append(arguments, value)
+    return "$" + strconv.Itoa(len(arguments))
+  }
+
+  if "" != filter.ExcludeTopic {
+    query.WriteString(`
+     AND (a."topic" IS NULL OR a."topic" <> ` + argument(filter.ExcludeTopic) + `)`)
+  }
+
+  if 0 < len(filter.Tags) {
+    tags := argument(pq.Array(filter.Tags))
+
+    if filter.MatchAllTags {
+      query.WriteString(`
+     AND (SELECT count(DISTINCT t."tag_id")
+            FROM "archive"."article_tag" t
+           WHERE t."article_uuid" = a."uuid"
+             AND t."tag_id" = ANY (` + tags + `)) = ` + argument(len(filter.Tags)))
+    } else {
+      query.WriteString(`
+     AND EXISTS (SELECT 1
+                   FROM "archive"."article_tag" t
+                  WHERE t."article_uuid" = a."uuid"
+                    AND t."tag_id" = ANY (` + tags + `))`)
+    }
+  }
+
+  if nil != filter.After {
+    query.WriteString(`
+     AND coalesce(a."published_at", a."drafted_at") >= ` + argument(*filter.After))
+  }
+
+  if nil != filter.Before {
+    query.WriteString(`
+     AND coalesce(a."published_at", a."drafted_at") < ` + argument(*filter.Before))
+  }
+
+  if 0 < filter.MinReadTime {
+    query.WriteString(`
+     AND a."read_time" >= ` + argument(filter.MinReadTime))
+  }
+
+  if 0 < filter.MaxReadTime {
+    query.WriteString(`
+     AND a."read_time" <= ` + argument(filter.MaxReadTime))
+  }
+
+  var (
+    direction  = "DESC"
+    comparison = "<"
+    sortName   = string(filter.Sort)
   )
 
+  if "" == sortName {
+    sortName = string(transfer.SortNewest)
+  }
+
+  if ascending {
+    direction, comparison = "ASC", ">"
+  }
+
+  if 0 >= filter.Page && "" != filter.Cursor {
+    var (
+      cursorSort string
+      pinned     bool
+      sortAt     time.Time
+      count      int64
+      id         uuid.UUID
+      key        any = &sortAt
+    )
+
+    if numericKey {
+      key = &count
+    }
+
+    err = transfer.DecodeCursor(filter.Cursor, &cursorSort, &pinned, key, &id)
+
+    if nil != err || cursorSort != sortName {
+      return nil, problem.NewUnparsableValue("cursor", "cursor", filter.Cursor)
+    }
+
+    var value any = sortAt
+
+    if numericKey {
+      value = count
+    }
+
+    if pinnedFirst {
+      query.WriteString(`
+     AND (a."pinned", ` + sortKey + `, a."uuid") < (` + argument(pinned) + `, ` + argument(sortAt) + `, ` + argument(id.String()) + `)`)
+    } else {
+      query.WriteString(`
+     AND (` + sortKey + `, a."uuid") ` + comparison + ` (` + argument(value) + `, ` + argument(id.String()) + `)`)
+    }
+  }
+
+  /* One more record than requested is fetched to know whether there is a next page.  */
+
+  query.WriteString(`
+  ORDER BY `)
+
+  if pinnedFirst {
+    query.WriteString(`a."pinned" DESC, `)
+  }
+
+  query.WriteString(sortKey + ` ` + direction + `, a."uuid" ` + direction + `
+  LIMIT $4 + 1
+  OFFSET $4 * ($3 - 1);`)
+
+  ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, query.String(), arguments...)
+
   if nil != err {
     slog.Error(getErrMsg(err))
     return nil, err
@@ -852,28 +971,44 @@ func (r *ArchiveRepository) List(ctx context.Context, filter *transfer.ArticleFi
     }
   }
 
-  articles = make([]*transfer.Article, 0)
+  page = &transfer.Page[*transfer.Article]{Items: make([]*transfer.Article, 0)}
+
+  var lastSortKey any
 
   for result.Next() {
     var (
       article       transfer.Article
-      slug          string
       nullableTopic sql.NullString
       topicName     sql.NullString
+      sortKey       any
     )
 
     err = result.Scan(
       &article.UUID,
       &article.Title,
-      &slug,
+      &article.Slug,
       &nullableTopic,
       &article.IsPinned,
       &article.PublishedAt,
       &topicName,
       &article.Summary,
       &article.CoverURL,
+      &article.IsOutdated,
+      &sortKey,
     )
 
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    if len(page.Items) == filter.RPP {
+      last := page.Items[len(page.Items)-1]
+      page.NextCursor = transfer.EncodeCursor(sortName, last.IsPinned, lastSortKey, last.UUID)
+      break
+    }
+
+    lastSortKey = sortKey
     topic := nullableTopic.String
 
     if "" == topic {
@@ -914,7 +1049,7 @@ func (r *ArchiveRepository) List(ctx context.Context, filter *transfer.ArticleFi
         article.Topic.ID,
         strconv.Itoa(year),
         strconv.Itoa(month),
-        slug)
+        article.Slug)
 
       if nil == err {
         article.URL = u
@@ -925,19 +1060,28 @@ func (r *ArchiveRepository) List(ctx context.Context, filter *transfer.ArticleFi
       article.URL = "about:blank"
     }
 
-    if nil != err {
-      slog.Error(err.Error())
-      return nil, err
-    }
-
-    articles = append(articles, &article)
+    page.Items = append(page.Items, &article)
   }
 
-  return articles, nil
+  return page, nil
 }
 
-// Get retrieves one published article by the URL '/archive/:topic/:year/:month/:slug'.
+// Get retrieves one published article by the URL '/archive/:topic/:year/:month/:slug'
+// and counts the request as a view of the article.
 func (r *ArchiveRepository) Get(ctx context.Context, request *transfer.ArticleRequest) (article *model.Article, err error) {
+  id, err := r.Lookup(ctx, request)
+  if nil != err {
+    return nil, err
+  }
+
+  go r.incrementViews(ctx, id)
+
+  return r.GetByID(ctx, id, false)
+}
+
+// Lookup retrieves the UUID of the published article with the URL
+// '/archive/:topic/:year/:month/:slug'.
+func (r *ArchiveRepository) Lookup(ctx context.Context, request *transfer.ArticleRequest) (id string, err error) {
   requestArticleUUIDQuery := `
   SELECT "uuid"
     FROM "archive"."article"
@@ -959,12 +1103,10 @@ func (r *ArchiveRepository) Get(ctx context.Context, request *transfer.ArticleRe
     month = int(request.Publication.Month)
   }
 
-  var id string
-
-  ctx1, cancel1 := context.WithTimeout(ctx, 5*time.Second)
-  defer cancel1()
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
 
-  err = r.db.QueryRowContext(ctx1, requestArticleUUIDQuery,
+  err = r.db.QueryRowContext(ctx, requestArticleUUIDQuery,
     request.Topic,
     year,
     month,
@@ -976,19 +1118,18 @@ func (r *ArchiveRepository) Get(ctx context.Context, request *transfer.ArticleRe
       slog.Error(getErrMsg(err))
     }
 
-    return nil, err
+    return "", err
   }
 
   if "" == id {
-    return nil, problem.NewNotFound(id, "article") // TODO: Do not return this kind of problem.
+    return "", problem.NewNotFound(id, "article") // TODO: Do not return this kind of problem.
   }
 
-  go r.incrementViews(ctx, id)
-
-  return r.GetByID(ctx, id, false)
+  return id, nil
 }
 
-// GetByLink retrieves a draft by its shareable link.
+// GetByLink retrieves a draft by its shareable link, counting a view
+// of the link.
 func (r *ArchiveRepository) GetByLink(ctx context.Context, link string) (article *model.Article, err error) {
   getByLinkQuery := `
   SELECT "article_uuid",
@@ -1045,6 +1186,18 @@ func (r *ArchiveRepository) GetByLink(ctx context.Context, link string) (article
 
   go r.incrementViews(ctx, id)
 
+  incrementLinkViewsQuery := `
+  UPDATE "archive"."article_link"
+     SET "views" = "views" + 1
+   WHERE "shareable_link" = $1;`
+
+  ctx2, cancel2 := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel2()
+
+  if _, err = r.db.ExecContext(ctx2, incrementLinkViewsQuery, link); nil != err {
+    slog.Error(getErrMsg(err))
+  }
+
   return r.GetByID(ctx, id, true)
 }
 
@@ -1129,6 +1282,44 @@ ORDER BY "lang";`
     files = append(files, file)
   }
 
+  getSyndicationQuery := `
+     SELECT "url",
+            "name",
+            "created_at"
+       FROM "archive"."article_syndication"
+      WHERE "article_uuid" = $1
+   ORDER BY "created_at";`
+
+  ctx1, cancel1 = context.WithTimeout(ctx, 20*time.Second)
+  defer cancel1()
+
+  result, err = r.db.QueryContext(ctx1, getSyndicationQuery, id)
+  if err != nil {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  syndication := make([]model.Syndication, 0)
+
+  for result.Next() {
+    var syndicated model.Syndication
+
+    err = result.Scan(
+      &syndicated.URL,
+      &syndicated.Name,
+      &syndicated.CreatedAt,
+    )
+
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    syndication = append(syndication, syndicated)
+  }
+
   getArticleByUUIDQuery := `
      SELECT a."uuid",
             a."title",
@@ -1149,16 +1340,34 @@ ORDER BY "lang";`
             t."updated_at",
             a."summary",
             a."cover_url",
-            a."cover_caption"
+            a."cover_caption",
+            a."canonical_url",
+            a."outdated_at",
+            a."outdated_reason",
+            s."uuid",
+            s."title",
+            s."topic",
+            s."published_at",
+            s."slug",
+            a."rendered_html",
+            a."table_of_contents",
+            a."word_count",
+            a."render_version",
+            a."version"
        FROM "archive"."article" a
   LEFT JOIN "archive"."topic" t
          ON t."id" = a."topic" 
-      WHERE "uuid" = $1
-        AND "draft" = $2
+  LEFT JOIN "archive"."article" s
+         ON s."uuid" = a."superseded_by"
+        AND s."draft" IS FALSE
+        AND s."published_at" IS NOT NULL
+        AND s."hidden" IS FALSE
+      WHERE a."uuid" = $1
+        AND a."draft" = $2
         AND CASE WHEN $2 = TRUE
-                 THEN "published_at" IS NULL
-                 ELSE "published_at" IS NOT NULL
-                  AND "hidden" IS FALSE
+                 THEN a."published_at" IS NULL
+                 ELSE a."published_at" IS NOT NULL
+                  AND a."hidden" IS FALSE
                   END;`
 
   ctx2, cancel2 := context.WithTimeout(ctx, 10*time.Second)
@@ -1170,6 +1379,10 @@ ORDER BY "lang";`
     article.DownloadFiles = files
   }
 
+  if 0 < len(syndication) {
+    article.Syndication = syndication
+  }
+
   if 0 < len(tags) {
     slices.SortFunc(tags, func(a, b *model.Tag) int {
       return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
@@ -1183,6 +1396,17 @@ ORDER BY "lang";`
     nullableTopicName      sql.NullString
     nullableTopicCreatedAt sql.Null[time.Time]
     nullableTopicUpdatedAt sql.Null[time.Time]
+    outdatedAt             sql.Null[time.Time]
+    outdatedReason         sql.NullString
+    replacementUUID        sql.Null[uuid.UUID]
+    replacementTitle       sql.NullString
+    replacementTopic       sql.NullString
+    replacementPublishedAt sql.Null[time.Time]
+    replacementSlug        sql.NullString
+    renderedHTML           sql.NullString
+    tableOfContents        []byte
+    wordCount              sql.NullInt64
+    renderVersion          sql.NullInt64
   )
 
   err = r.db.QueryRowContext(ctx2, getArticleByUUIDQuery, id, isDraft).Scan(
@@ -1206,10 +1430,62 @@ ORDER BY "lang";`
     &article.Summary,
     &article.CoverURL,
     &article.CoverCap,
+    &article.CanonicalURL,
+    &outdatedAt,
+    &outdatedReason,
+    &replacementUUID,
+    &replacementTitle,
+    &replacementTopic,
+    &replacementPublishedAt,
+    &replacementSlug,
+    &renderedHTML,
+    &tableOfContents,
+    &wordCount,
+    &renderVersion,
+    &article.Version,
   )
 
   article.Views += r.views(article.UUID.String())
 
+  if renderedHTML.Valid && renderVersion.Valid {
+    article.Rendering = &model.Rendering{
+      HTML:            renderedHTML.String,
+      TableOfContents: make([]*model.Heading, 0),
+      WordCount:       int(wordCount.Int64),
+      Version:         int(renderVersion.Int64),
+    }
+
+    if 0 < len(tableOfContents) {
+      if err := json.Unmarshal(tableOfContents, &article.Rendering.TableOfContents); nil != err {
+        slog.Error(err.Error())
+        article.Rendering = nil
+      }
+    }
+  }
+
+  if outdatedAt.Valid {
+    article.Outdated = &model.OutdatedNotice{
+      Reason:   outdatedReason.String,
+      MarkedAt: outdatedAt.V,
+    }
+
+    if replacementUUID.Valid && replacementTopic.Valid && replacementPublishedAt.Valid {
+      article.Outdated.Replacement = &struct {
+        UUID  uuid.UUID `json:"uuid"`
+        Title string    `json:"title"`
+        URL   string    `json:"url"`
+      }{
+        UUID:  replacementUUID.V,
+        Title: replacementTitle.String,
+        URL: fmt.Sprintf("/archive/%s/%d/%d/%s",
+          replacementTopic.String,
+          replacementPublishedAt.V.Year(),
+          int(replacementPublishedAt.V.Month()),
+          replacementSlug.String),
+      }
+    }
+  }
+
   if nullableTopicID.Valid {
     article.Topic = new(model.Topic)
     article.Topic.ID = nullableTopicID.String
@@ -1667,7 +1943,8 @@ func (r *ArchiveRepository) SetHidden(ctx context.Context, id string, hidden boo
 
   setHiddenQuery := `
   UPDATE "archive"."article"
-     SET "hidden" = $2
+     SET "hidden" = $2,
+         "show_at" = NULL
    WHERE "uuid" = $1
      AND "draft" IS FALSE
      AND "published_at" IS NOT NULL;`
@@ -1696,8 +1973,11 @@ func (r *ArchiveRepository) SetHidden(ctx context.Context, id string, hidden boo
   return nil
 }
 
-// SetPinned pins or unpins an article depending on the value of pinned.
-func (r *ArchiveRepository) SetPinned(ctx context.Context, id string, pinned bool) error {
+// ScheduleShow schedules a hidden article to be shown at the given time,
+// which is stored in UTC.
+// If hide is true, the article is also hidden right away, so it stays
+// hidden until then; otherwise, its current visibility is kept.
+func (r *ArchiveRepository) ScheduleShow(ctx context.Context, id string, at time.Time, hide bool) error {
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
   if nil != err {
     slog.Error(getErrMsg(err))
@@ -1706,9 +1986,10 @@ func (r *ArchiveRepository) SetPinned(ctx context.Context, id string, pinned boo
 
   defer tx.Rollback()
 
-  setPinnedQuery := `
+  scheduleShowQuery := `
   UPDATE "archive"."article"
-     SET "pinned" = $2
+     SET "hidden" = CASE WHEN $3 THEN TRUE ELSE "hidden" END,
+         "show_at" = $2
    WHERE "uuid" = $1
      AND "draft" IS FALSE
      AND "published_at" IS NOT NULL;`
@@ -1716,7 +1997,7 @@ func (r *ArchiveRepository) SetPinned(ctx context.Context, id string, pinned boo
   ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
-  result, err := tx.ExecContext(ctx, setPinnedQuery, id, pinned)
+  result, err := tx.ExecContext(ctx, scheduleShowQuery, id, at.UTC(), hide)
   if nil != err {
     slog.Error(getErrMsg(err))
     return err
@@ -1732,143 +2013,628 @@ func (r *ArchiveRepository) SetPinned(ctx context.Context, id string, pinned boo
     return err
   }
 
+  if hide {
+    r.setPublicationsCache(ctx)
+  }
+
   return nil
 }
 
-// Share creates a shareable link for a draft or a patch. Only users
-// with that link can see the progress and provide feedback.
-//
-// A shareable link does not make an article public. This link will
-// eventually expire after a certain amount of time.
-func (r *ArchiveRepository) Share(ctx context.Context, id string) (link string, err error) {
-  const base = "https://fontseca.dev"
-
-  defer func() {
-    if nil == err {
-      r.mu.Lock()
-      r.cleanOnce = sync.Once{}
-      r.mu.Unlock()
-    }
-  }()
+// ShowScheduled shows every article whose scheduled time to be shown has
+// come and returns how many of them were shown.
+func (r *ArchiveRepository) ShowScheduled(ctx context.Context) (shown int64, err error) {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return 0, err
+  }
 
-  assertIsArticlePatchQuery := `
-  SELECT count(*)
-    FROM "archive"."article_patch"
-   WHERE "article_uuid" = $1;`
+  defer tx.Rollback()
 
-  var isArticlePatch bool
+  showScheduledQuery := `
+  UPDATE "archive"."article"
+     SET "hidden" = FALSE,
+         "show_at" = NULL
+   WHERE "show_at" <= current_timestamp AT TIME ZONE 'UTC'
+     AND "draft" IS FALSE
+     AND "published_at" IS NOT NULL;`
 
-  ctx1, cancel := context.WithTimeout(ctx, 2*time.Second)
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
 
-  err = r.db.QueryRowContext(ctx1, assertIsArticlePatchQuery, id).Scan(&isArticlePatch)
+  result, err := tx.ExecContext(ctx, showScheduledQuery)
   if nil != err {
     slog.Error(getErrMsg(err))
-    return "", err
+    return 0, err
   }
 
-  if !isArticlePatch {
-    assertIsArticleDraftQuery := `
-    SELECT count (*)
-      FROM "archive"."article"
-     WHERE "uuid" = $1
-       AND "draft" IS TRUE
-       AND "published_at" IS NULL;`
-
-    ctx1, cancel = context.WithTimeout(ctx, 2*time.Second)
-    defer cancel()
-
-    var isDraft bool
+  shown, _ = result.RowsAffected()
 
-    err = r.db.QueryRowContext(ctx1, assertIsArticleDraftQuery, id).Scan(&isDraft)
-    if nil != err {
-      slog.Error(getErrMsg(err))
-      return "", err
-    }
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return 0, err
+  }
 
-    if !isDraft {
-      return "", problem.NewNotFound(id, "draft")
-    }
+  if 0 < shown {
+    r.setPublicationsCache(ctx)
   }
 
-  tryToGetCurrentLinkWithExpirationTimeQuery := `
-  SELECT "shareable_link",
+  return shown, nil
+}
+
+// SetOutdated marks an article as outdated for the given reason. If
+// supersededBy is not nil, it is the UUID of the article that replaces it.
+func (r *ArchiveRepository) SetOutdated(ctx context.Context, id, reason string, supersededBy *string) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  setOutdatedQuery := `
+  UPDATE "archive"."article"
+     SET "outdated_at" = current_timestamp,
+         "outdated_reason" = $2,
+         "superseded_by" = $3
+   WHERE "uuid" = $1
+     AND "draft" IS FALSE
+     AND "published_at" IS NOT NULL;`
+
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx, setOutdatedQuery, id, reason, supersededBy)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  affected, _ := result.RowsAffected()
+  if 1 != affected {
+    return problem.NewNotFound(id, "article")
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// UnsetOutdated removes the outdated notice of an article. This counts
+// as a review of the article, so MarkStale leaves it alone for a while.
+func (r *ArchiveRepository) UnsetOutdated(ctx context.Context, id string) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  unsetOutdatedQuery := `
+  UPDATE "archive"."article"
+     SET "outdated_at" = NULL,
+         "outdated_reason" = NULL,
+         "superseded_by" = NULL,
+         "reviewed_at" = current_timestamp
+   WHERE "uuid" = $1
+     AND "draft" IS FALSE
+     AND "published_at" IS NOT NULL;`
+
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx, unsetOutdatedQuery, id)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  affected, _ := result.RowsAffected()
+  if 1 != affected {
+    return problem.NewNotFound(id, "article")
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// MarkStale marks as outdated, for the given reason, every article of the
+// given topics that has not been published, modified or reviewed since
+// before. It returns how many articles were marked.
+func (r *ArchiveRepository) MarkStale(ctx context.Context, topics []string, before time.Time, reason string) (marked int64, err error) {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return 0, err
+  }
+
+  defer tx.Rollback()
+
+  markStaleQuery := `
+  UPDATE "archive"."article"
+     SET "outdated_at" = current_timestamp,
+         "outdated_reason" = $3
+   WHERE "draft" IS FALSE
+     AND "published_at" IS NOT NULL
+     AND "outdated_at" IS NULL
+     AND "topic" = ANY ($1)
+     AND coalesce("reviewed_at", "modified_at", "published_at") < $2;`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx, markStaleQuery, pq.Array(topics), before, reason)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return 0, err
+  }
+
+  marked, _ = result.RowsAffected()
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return 0, err
+  }
+
+  return marked, nil
+}
+
+// AddSyndication records a copy of an article published on another
+// platform. If the copy is already recorded, its name is updated.
+func (r *ArchiveRepository) AddSyndication(ctx context.Context, id, copyURL string, name *string) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  articleExistsQuery := `
+  SELECT count(1)
+    FROM "archive"."article"
+   WHERE "uuid" = $1
+     AND "draft" IS FALSE
+     AND "published_at" IS NOT NULL;`
+
+  ctx1, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  var exists bool
+
+  if err = tx.QueryRowContext(ctx1, articleExistsQuery, id).Scan(&exists); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if !exists {
+    return problem.NewNotFound(id, "article")
+  }
+
+  addSyndicationQuery := `
+  INSERT INTO "archive"."article_syndication" ("article_uuid", "url", "name")
+       VALUES ($1, $2, $3)
+  ON CONFLICT ("article_uuid", "url")
+    DO UPDATE SET "name" = excluded."name";`
+
+  ctx1, cancel = context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  if _, err = tx.ExecContext(ctx1, addSyndicationQuery, id, copyURL, name); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// RemoveSyndication removes a copy of an article from its syndication.
+func (r *ArchiveRepository) RemoveSyndication(ctx context.Context, id, copyURL string) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  removeSyndicationQuery := `
+  DELETE FROM "archive"."article_syndication"
+        WHERE "article_uuid" = $1
+          AND "url" = $2;`
+
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx, removeSyndicationQuery, id, copyURL)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(copyURL, "syndication")
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// SetPinned pins or unpins an article depending on the value of pinned.
+func (r *ArchiveRepository) SetPinned(ctx context.Context, id string, pinned bool) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  setPinnedQuery := `
+  UPDATE "archive"."article"
+     SET "pinned" = $2
+   WHERE "uuid" = $1
+     AND "draft" IS FALSE
+     AND "published_at" IS NOT NULL;`
+
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx, setPinnedQuery, id, pinned)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  affected, _ := result.RowsAffected()
+  if 1 != affected {
+    return problem.NewNotFound(id, "article")
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// Share creates a shareable link for a draft or a patch. Only users
+// with that link can see the progress and provide feedback.
+//
+// A shareable link does not make an article public. This link will
+// eventually expire after creation.ExpiresIn days. A draft or a patch
+// can have several links, one per label; if it already has a valid
+// link with the same label, that link and its views are kept, but its
+// password and expiry are replaced by the ones in creation, and a link
+// shared again without a password loses the one it had. The password
+// in creation, if any, is expected to be already hashed.
+func (r *ArchiveRepository) Share(ctx context.Context, id string, creation *transfer.ShareableLinkCreation) (link *model.ShareableLink, err error) {
+  defer func() {
+    if nil == err {
+      r.mu.Lock()
+      r.cleanOnce = sync.Once{}
+      r.mu.Unlock()
+    }
+  }()
+
+  assertIsArticlePatchQuery := `
+  SELECT count(*)
+    FROM "archive"."article_patch"
+   WHERE "article_uuid" = $1;`
+
+  var isArticlePatch bool
+
+  ctx1, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  err = r.db.QueryRowContext(ctx1, assertIsArticlePatchQuery, id).Scan(&isArticlePatch)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  if !isArticlePatch {
+    assertIsArticleDraftQuery := `
+    SELECT count (*)
+      FROM "archive"."article"
+     WHERE "uuid" = $1
+       AND "draft" IS TRUE
+       AND "published_at" IS NULL;`
+
+    ctx1, cancel = context.WithTimeout(ctx, 2*time.Second)
+    defer cancel()
+
+    var isDraft bool
+
+    err = r.db.QueryRowContext(ctx1, assertIsArticleDraftQuery, id).Scan(&isDraft)
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    if !isDraft {
+      return nil, problem.NewNotFound(id, "draft")
+    }
+  }
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer tx.Rollback()
+
+  removeObsoleteLinkQuery := `
+  DELETE FROM "archive"."article_link"
+        WHERE "article_uuid" = $1
+          AND "label" = $2
+          AND "expires_at" <= current_timestamp;`
+
+  ctx1, cancel = context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx1, removeObsoleteLinkQuery, id, creation.Label)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  if affected, _ := result.RowsAffected(); 0 < affected {
+    slog.Info("shareable link expired, generating a new one",
+      slog.String("label", creation.Label),
+      slog.String("article_uuid", id))
+  }
+
+  data := fmt.Sprintf("%s for %s at %s", id, creation.Label, time.Now().String())
+  hash := sha256.Sum256([]byte(data))
+
+  var passwordHash *string
+  if "" != creation.Password {
+    passwordHash = &creation.Password
+  }
+
+  // Sharing a label again keeps its link and views, but its password
+  // and expiry are replaced by the new ones.
+  makeShareableLinkQuery := `
+       INSERT INTO "archive"."article_link" ("article_uuid", "label", "shareable_link", "password_hash", "expires_at")
+            VALUES ($1, $2, $3, $4, current_timestamp + make_interval(days => $5))
+  ON CONFLICT ("article_uuid", "label")
+  DO UPDATE SET "password_hash" = excluded."password_hash",
+                "expires_at" = excluded."expires_at";`
+
+  ctx1, cancel = context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  _, err = tx.ExecContext(ctx1, makeShareableLinkQuery,
+    id,
+    creation.Label,
+    fmt.Sprintf("/archive/sharing/%x", hash),
+    passwordHash,
+    creation.ExpiresIn)
+
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  getShareableLinkQuery := `
+  SELECT "uuid",
+         "label",
+         "shareable_link",
+         "password_hash" IS NOT NULL,
+         "views",
+         "created_at",
          "expires_at"
     FROM "archive"."article_link"
-   WHERE "article_uuid" = $1;`
+   WHERE "article_uuid" = $1
+     AND "label" = $2;`
 
-  ctx, cancel = context.WithTimeout(ctx, 2*time.Second)
+  ctx1, cancel = context.WithTimeout(ctx, 2*time.Second)
   defer cancel()
 
-  var expiresAt time.Time
+  link = &model.ShareableLink{}
+
+  err = tx.QueryRowContext(ctx1, getShareableLinkQuery, id, creation.Label).Scan(
+    &link.UUID,
+    &link.Label,
+    &link.Link,
+    &link.Protected,
+    &link.Views,
+    &link.CreatedAt,
+    &link.ExpiresAt,
+  )
 
-  err = r.db.QueryRowContext(ctx, tryToGetCurrentLinkWithExpirationTimeQuery, id).Scan(&link, &expiresAt)
   if nil != err {
-    if !errors.Is(err, sql.ErrNoRows) {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  link.Link = r.baseURL + link.Link
+
+  return link, nil
+}
+
+// Links retrieves the valid shareable links of a draft or a patch, from
+// the oldest to the newest.
+func (r *ArchiveRepository) Links(ctx context.Context, id string) (links []*model.ShareableLink, err error) {
+  listLinksQuery := `
+    SELECT "uuid",
+           "label",
+           "shareable_link",
+           "password_hash" IS NOT NULL,
+           "views",
+           "created_at",
+           "expires_at"
+      FROM "archive"."article_link"
+     WHERE "article_uuid" = $1
+       AND "expires_at" > current_timestamp
+  ORDER BY "created_at";`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, listLinksQuery, id)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  links = make([]*model.ShareableLink, 0)
+
+  for result.Next() {
+    var link model.ShareableLink
+
+    err = result.Scan(
+      &link.UUID,
+      &link.Label,
+      &link.Link,
+      &link.Protected,
+      &link.Views,
+      &link.CreatedAt,
+      &link.ExpiresAt,
+    )
+
+    if nil != err {
       slog.Error(getErrMsg(err))
-      return "", err
+      return nil, err
     }
+
+    link.Link = r.baseURL + link.Link
+    links = append(links, &link)
   }
 
-  if "" != link {
-    now := time.Now()
-    if -1 == expiresAt.Compare(now) || 0 == expiresAt.Compare(now) { // link has expired
-      removeObsoleteLinkQuery := `
-      DELETE FROM "archive"."article_link"
-            WHERE "article_uuid" = $1;`
+  if err = result.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
 
-      ctx, cancel = context.WithTimeout(ctx, 2*time.Second)
-      defer cancel()
+  return links, nil
+}
 
-      _, err = r.db.ExecContext(ctx, removeObsoleteLinkQuery, id)
-      if nil != err {
-        slog.Error(getErrMsg(err))
-        return "", err
-      }
+// LinkPassword retrieves the hash of the password that protects a
+// shareable link, or an empty string if the link is not protected or
+// does not exist.
+func (r *ArchiveRepository) LinkPassword(ctx context.Context, link string) (hash string, err error) {
+  getLinkPasswordQuery := `
+  SELECT coalesce("password_hash", '')
+    FROM "archive"."article_link"
+   WHERE "shareable_link" = $1;`
 
-      slog.Info("shareable link expired, generating a new one",
-        slog.String("elapsed", now.Sub(expiresAt).String()),
-        slog.String("article_uuid", id))
-    } else {
-      return base + link, nil
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  err = r.db.QueryRowContext(ctx, getLinkPasswordQuery, link).Scan(&hash)
+  if nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      return "", nil
     }
+
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  return hash, nil
+}
+
+// RevokeLink removes a shareable link, so that it can no longer be
+// used to see its draft or patch.
+func (r *ArchiveRepository) RevokeLink(ctx context.Context, linkID string) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  revokeLinkQuery := `
+  DELETE FROM "archive"."article_link"
+        WHERE "uuid" = $1;`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx, revokeLinkQuery, linkID)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(linkID, "shareable link")
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
   }
 
+  return nil
+}
+
+// ExtendLink postpones the expiration of a shareable link by days; if
+// the link has already expired, but has not been cleaned up yet, it is
+// extended from now.
+func (r *ArchiveRepository) ExtendLink(ctx context.Context, linkID string, days int) error {
   tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
   if nil != err {
     slog.Error(getErrMsg(err))
-    return "", err
+    return err
   }
 
   defer tx.Rollback()
 
-  makeShareableLinkQuery := `
-  INSERT INTO "archive"."article_link" ("article_uuid", "shareable_link")
-                      VALUES ($1, $2)
-    RETURNING "shareable_link";`
-
-  data := fmt.Sprintf("%s at %s", id, time.Now().String())
-  hash := sha256.Sum256([]byte(data))
-
-  ctx2, cancel2 := context.WithTimeout(ctx, 5*time.Second)
-  defer cancel2()
+  extendLinkQuery := `
+  UPDATE "archive"."article_link"
+     SET "expires_at" = greatest("expires_at", current_timestamp) + make_interval(days => $2)
+   WHERE "uuid" = $1;`
 
-  err = tx.QueryRowContext(ctx2, makeShareableLinkQuery,
-    id,
-    fmt.Sprintf("/archive/sharing/%x", hash)).
-    Scan(&link)
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
 
+  result, err := tx.ExecContext(ctx, extendLinkQuery, linkID, days)
   if nil != err {
     slog.Error(getErrMsg(err))
-    return "", nil
+    return err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(linkID, "shareable link")
   }
 
   if err = tx.Commit(); nil != err {
     slog.Error(getErrMsg(err))
-    return "", err
+    return err
   }
 
-  return base + link, nil
+  return nil
 }
 
 // Discard completely drops a draft; otherwise if called on a patch
@@ -1936,6 +2702,12 @@ func (r *ArchiveRepository) Discard(ctx context.Context, id string) error {
     return problem.NewNotFound(id, "draft")
   }
 
+  if isArticlePatch {
+    if err = r.clearPatchHistory(ctx, tx, id); nil != err {
+      return err
+    }
+  }
+
   if err = tx.Commit(); nil != err {
     slog.Error(getErrMsg(err))
     return err
@@ -1944,6 +2716,33 @@ func (r *ArchiveRepository) Discard(ctx context.Context, id string) error {
   return nil
 }
 
+// clearPatchHistory removes the workflow transitions of the patch of an
+// article and the feedback left on it, so that the next patch of the
+// article starts afresh.
+func (r *ArchiveRepository) clearPatchHistory(ctx context.Context, tx *sql.Tx, id string) error {
+  clearPatchHistoryQuery := `
+  DELETE FROM "archive"."workflow_transition"
+        WHERE "article_uuid" = $1
+          AND "patch" IS TRUE;`
+
+  clearPatchFeedbackQuery := `
+  DELETE FROM "archive"."feedback"
+        WHERE "article_uuid" = $1
+          AND "patch" IS TRUE;`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  for _, query := range []string{clearPatchHistoryQuery, clearPatchFeedbackQuery} {
+    if _, err := tx.ExecContext(ctx, query, id); nil != err {
+      slog.Error(getErrMsg(err))
+      return err
+    }
+  }
+
+  return nil
+}
+
 // Revise adds a correction or inclusion to a draft or patch in order
 // to correct or improve it.
 func (r *ArchiveRepository) Revise(ctx context.Context, id string, revision *transfer.ArticleRevision) error {
@@ -2007,11 +2806,24 @@ func (r *ArchiveRepository) Revise(ctx context.Context, id string, revision *tra
          "content" = coalesce (nullif ($6, ''), "content"),
          "summary" = coalesce(nullif($7, ''), "summary"),
          "cover_url" = coalesce(nullif($8, ''), "cover_url"),
-         "cover_caption" = coalesce(nullif($9, ''), "cover_caption")
+         "cover_caption" = coalesce(nullif($9, ''), "cover_caption"),
+         "canonical_url" = coalesce(nullif($10, ''), "canonical_url"),
+         "render_version" = NULL,
+         "version" = "version" + 1
+   WHERE "uuid" = $1
+     AND "draft" IS TRUE
+     AND "published_at" IS NULL
+     AND ($11 = 0 OR "version" = $11);`
+
+  currentVersionQuery := `
+  SELECT "version"
+    FROM "archive"."article"
    WHERE "uuid" = $1
      AND "draft" IS TRUE
      AND "published_at" IS NULL;`
 
+  recordType := "draft"
+
   if isArticlePatch {
     reviseArticleQuery = `
     UPDATE "archive"."article_patch"
@@ -2024,11 +2836,21 @@ func (r *ArchiveRepository) Revise(ctx context.Context, id string, revision *tra
                               THEN "read_time"
                               ELSE $5
                                END,
-           "content" = coalesce (nullif ($6, ''), "content")
+           "content" = coalesce (nullif ($6, ''), "content"),
+           "canonical_url" = coalesce (nullif ($10, ''), "canonical_url"),
+           "version" = "version" + 1
      WHERE "article_uuid" = $1
+       AND ($11 = 0 OR "version" = $11)
        AND length($7) >= 0
        AND length($8) >= 0
        AND length($9) >= 0;` /* (Just ignore these parameters.)  */
+
+    currentVersionQuery = `
+    SELECT "version"
+      FROM "archive"."article_patch"
+     WHERE "article_uuid" = $1;`
+
+    recordType = "article patch"
   }
 
   ctx, cancel = context.WithTimeout(ctx, 20*time.Second)
@@ -2044,6 +2866,8 @@ func (r *ArchiveRepository) Revise(ctx context.Context, id string, revision *tra
     &revision.Summary,
     &revision.CoverURL,
     &revision.CoverCap,
+    &revision.CanonicalURL,
+    &revision.Version,
   )
 
   if nil != err {
@@ -2053,11 +2877,7 @@ func (r *ArchiveRepository) Revise(ctx context.Context, id string, revision *tra
 
   affected, _ := result.RowsAffected()
   if 1 != affected {
-    if isArticlePatch {
-      return problem.NewNotFound(id, "article patch")
-    }
-
-    return problem.NewNotFound(id, "draft")
+    return staleVersion(ctx, tx, currentVersionQuery, id, recordType)
   }
 
   if err = tx.Commit(); nil != err {
@@ -2079,7 +2899,8 @@ func (r *ArchiveRepository) Release(ctx context.Context, id string) error {
          "slug",
          "topic",
          "read_time",
-         "content"
+         "content",
+         "canonical_url"
     FROM "archive"."article_patch"
    WHERE "article_uuid" = $1;`
 
@@ -2097,6 +2918,7 @@ func (r *ArchiveRepository) Release(ctx context.Context, id string) error {
       &patch.TopicID,
       &patch.ReadTime,
       &patch.Content,
+      &patch.CanonicalURL,
     )
 
   if nil != err {
@@ -2126,6 +2948,8 @@ func (r *ArchiveRepository) Release(ctx context.Context, id string) error {
                             ELSE $5
                              END,
          "content" = coalesce(nullif($6, ''), "content"),
+         "canonical_url" = coalesce(nullif($7, ''), "canonical_url"),
+         "render_version" = NULL,
          "modified_at" = current_timestamp,
          "updated_at" = current_timestamp
    WHERE "uuid" = $1
@@ -2141,7 +2965,8 @@ func (r *ArchiveRepository) Release(ctx context.Context, id string) error {
     patch.Slug,
     patch.TopicID,
     patch.ReadTime,
-    patch.Content)
+    patch.Content,
+    patch.CanonicalURL)
 
   if nil != err {
     slog.Error(getErrMsg(err))
@@ -2165,6 +2990,10 @@ func (r *ArchiveRepository) Release(ctx context.Context, id string) error {
     return nil
   }
 
+  if err = r.clearPatchHistory(ctx, tx, id); nil != err {
+    return err
+  }
+
   defer tx.Rollback()
 
   if err = tx.Commit(); nil != err {
@@ -2182,7 +3011,9 @@ func (r *ArchiveRepository) ListPatches(ctx context.Context) (patches []*model.A
          "title",
          "slug",
          "topic",
-         "content"
+         "content",
+         "canonical_url",
+         "version"
     FROM "archive"."article_patch";`
 
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -2206,7 +3037,9 @@ func (r *ArchiveRepository) ListPatches(ctx context.Context) (patches []*model.A
       &patch.Title,
       &patch.Slug,
       &patch.TopicID,
-      &patch.Content)
+      &patch.Content,
+      &patch.CanonicalURL,
+      &patch.Version)
 
     if nil != err {
       slog.Error(getErrMsg(err))
@@ -2218,3 +3051,299 @@ func (r *ArchiveRepository) ListPatches(ctx context.Context) (patches []*model.A
 
   return patches, nil
 }
+
+// GetContent retrieves the content of an article, be it a draft, hidden
+// or published.
+func (r *ArchiveRepository) GetContent(ctx context.Context, id string) (content string, err error) {
+  getContentQuery := `
+  SELECT "content"
+    FROM "archive"."article"
+   WHERE "uuid" = $1;`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  err = r.db.QueryRowContext(ctx, getContentQuery, id).Scan(&content)
+  if nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      return "", problem.NewNotFound(id, "article")
+    }
+
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  return content, nil
+}
+
+// SetRendering stores the rendering of the content of an article.
+func (r *ArchiveRepository) SetRendering(ctx context.Context, id string, rendering *model.Rendering) error {
+  tableOfContents, err := json.Marshal(rendering.TableOfContents)
+  if nil != err {
+    slog.Error(err.Error())
+    return err
+  }
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  setRenderingQuery := `
+  UPDATE "archive"."article"
+     SET "rendered_html" = $2,
+         "table_of_contents" = $3,
+         "word_count" = $4,
+         "render_version" = $5
+   WHERE "uuid" = $1;`
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx, setRenderingQuery,
+    id,
+    rendering.HTML,
+    tableOfContents,
+    rendering.WordCount,
+    rendering.Version,
+  )
+
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(id, "article")
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// ListStaleRenderings retrieves the UUIDs of up to limit articles that
+// have not been rendered yet or were rendered by a version of the
+// renderer other than version.
+func (r *ArchiveRepository) ListStaleRenderings(ctx context.Context, version, limit int) (ids []string, err error) {
+  listStaleRenderingsQuery := `
+  SELECT "uuid"
+    FROM "archive"."article"
+   WHERE "render_version" IS DISTINCT FROM $1
+      OR "rendered_html" IS NULL
+ORDER BY "published_at" DESC NULLS LAST
+   LIMIT $2;`
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, listStaleRenderingsQuery, version, limit)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  ids = make([]string, 0)
+
+  for result.Next() {
+    var id string
+
+    if err = result.Scan(&id); nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    ids = append(ids, id)
+  }
+
+  return ids, nil
+}
+
+// WorkflowState retrieves the state of a draft or a patch in the
+// editorial workflow. Since a patch shares its UUID with the article it
+// patches, the state of the patch takes precedence.
+func (r *ArchiveRepository) WorkflowState(ctx context.Context, id string) (state string, err error) {
+  getWorkflowStateQuery := `
+  SELECT coalesce(p."workflow_state", a."workflow_state")
+    FROM "archive"."article" a
+         LEFT JOIN "archive"."article_patch" p
+                ON p."article_uuid" = a."uuid"
+   WHERE a."uuid" = $1
+     AND (p."article_uuid" IS NOT NULL
+      OR (a."draft" IS TRUE AND a."published_at" IS NULL));`
+
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  err = r.db.QueryRowContext(ctx, getWorkflowStateQuery, id).Scan(&state)
+  if nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      return "", problem.NewNotFound(id, "draft")
+    }
+
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  return state, nil
+}
+
+// Transition moves a draft or a patch from the state transition.From
+// to the state transition.To and records the transition in its history.
+//
+// If the draft or the patch is no longer in transition.From, because it
+// was moved by someone else, nothing changes and it returns a conflict.
+func (r *ArchiveRepository) Transition(ctx context.Context, id string, transition *model.WorkflowTransition) error {
+  isArticlePatchQuery := `
+  SELECT count(*)
+    FROM "archive"."article_patch"
+   WHERE "article_uuid" = $1;`
+
+  var isArticlePatch bool
+
+  ctx1, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  err := r.db.QueryRowContext(ctx1, isArticlePatchQuery, id).Scan(&isArticlePatch)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  setWorkflowStateQuery := `
+  UPDATE "archive"."article"
+     SET "workflow_state" = $3
+   WHERE "uuid" = $1
+     AND "workflow_state" = $2
+     AND "draft" IS TRUE
+     AND "published_at" IS NULL;`
+
+  if isArticlePatch {
+    setWorkflowStateQuery = `
+    UPDATE "archive"."article_patch"
+       SET "workflow_state" = $3
+     WHERE "article_uuid" = $1
+       AND "workflow_state" = $2;`
+  }
+
+  ctx1, cancel = context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx1, setWorkflowStateQuery, id, transition.From, transition.To)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    var p problem.Problem
+    p.Type(problem.TypeActionRefused)
+    p.Status(http.StatusConflict)
+    p.Title("Could not transition draft.")
+    p.Detail("The draft is no longer in the state it was moved from. Check its history and try again.")
+    p.With("draft_uuid", id)
+    p.With("from", transition.From)
+    return &p
+  }
+
+  recordTransitionQuery := `
+  INSERT INTO "archive"."workflow_transition" ("article_uuid", "patch", "from_state", "to_state", "actor", "comment")
+       VALUES ($1, $2, $3, $4, $5, $6)
+    RETURNING "transitioned_at";`
+
+  ctx1, cancel = context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  err = tx.QueryRowContext(ctx1, recordTransitionQuery,
+    id,
+    isArticlePatch,
+    transition.From,
+    transition.To,
+    transition.Actor,
+    transition.Comment,
+  ).Scan(&transition.TransitionedAt)
+
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// History retrieves the transitions of a draft or a patch through the
+// editorial workflow, from the oldest to the newest.
+func (r *ArchiveRepository) History(ctx context.Context, id string) (transitions []*model.WorkflowTransition, err error) {
+  getHistoryQuery := `
+  SELECT t."from_state",
+         t."to_state",
+         t."actor",
+         t."comment",
+         t."transitioned_at"
+    FROM "archive"."workflow_transition" t
+   WHERE t."article_uuid" = $1
+     AND t."patch" = EXISTS (SELECT 1
+                               FROM "archive"."article_patch" p
+                              WHERE p."article_uuid" = $1)
+ORDER BY t."transitioned_at";`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, getHistoryQuery, id)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  transitions = make([]*model.WorkflowTransition, 0)
+
+  for result.Next() {
+    var transition model.WorkflowTransition
+
+    err = result.Scan(
+      &transition.From,
+      &transition.To,
+      &transition.Actor,
+      &transition.Comment,
+      &transition.TransitionedAt,
+    )
+
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    transitions = append(transitions, &transition)
+  }
+
+  if err = result.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return transitions, nil
+}
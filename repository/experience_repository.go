@@ -92,7 +92,8 @@ func (r *ExperienceRepository) doGet(ctx context.Context, id string, strict bool
          "active",
          "hidden",
          "created_at",
-         "updated_at"
+         "updated_at",
+         "version"
     FROM "me"."experience"
    WHERE "uuid" = $1`
 
@@ -117,7 +118,8 @@ func (r *ExperienceRepository) doGet(ctx context.Context, id string, strict bool
     &experience.Active,
     &experience.Hidden,
     &experience.CreatedAt,
-    &experience.UpdatedAt)
+    &experience.UpdatedAt,
+    &experience.Version)
 
   if nil != err {
     if errors.Is(err, sql.ErrNoRows) {
@@ -203,6 +205,8 @@ func (r *ExperienceRepository) Update(ctx context.Context, id string, update *tr
     return err
   }
 
+  defer tx.Rollback()
+
   updateExperienceQuery := `
   UPDATE "me"."experience"
      SET "date_start" = CASE WHEN $2 <> '' AND $2::DATE <> "date_start" THEN $2::DATE ELSE "date_start" END,
@@ -214,8 +218,10 @@ func (r *ExperienceRepository) Update(ctx context.Context, id string, update *tr
          "company_homepage" = coalesce (nullif ($6, ''), "company_homepage"),
          "country" = coalesce (nullif ($7, ''), "country"),
          "summary" = coalesce (nullif ($8, ''), "summary"),
-         "updated_at" = current_timestamp
-   WHERE "uuid" = $1;`
+         "updated_at" = current_timestamp,
+         "version" = "version" + 1
+   WHERE "uuid" = $1
+     AND ($9 = 0 OR "version" = $9);`
 
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
@@ -228,7 +234,8 @@ func (r *ExperienceRepository) Update(ctx context.Context, id string, update *tr
     update.Company,
     update.CompanyHomepage,
     update.Country,
-    update.Summary)
+    update.Summary,
+    update.Version)
 
   if nil != err {
     slog.Error(getErrMsg(err))
@@ -238,7 +245,12 @@ func (r *ExperienceRepository) Update(ctx context.Context, id string, update *tr
   affected, _ := result.RowsAffected()
 
   if 1 != affected {
-    return problem.NewNotFound(id, "experience")
+    currentVersionQuery := `
+    SELECT "version"
+      FROM "me"."experience"
+     WHERE "uuid" = $1;`
+
+    return staleVersion(ctx, tx, currentVersionQuery, id, "experience")
   }
 
   if err = tx.Commit(); nil != err {
@@ -260,7 +272,8 @@ func (r *ExperienceRepository) SetHidden(ctx context.Context, id string, hidden
   query := `
   UPDATE "me"."experience"
      SET "hidden" = $2,
-         "updated_at" = current_timestamp
+         "updated_at" = current_timestamp,
+         "version" = "version" + 1
    WHERE "uuid" = $1;`
 
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
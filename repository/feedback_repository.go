@@ -0,0 +1,311 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "log/slog"
+  "net/http"
+  "time"
+)
+
+// FeedbackRepository is a low level API that provides methods for
+// interacting with the feedback reviewers leave on shared drafts and
+// patches in the database.
+//
+// Since a patch shares its UUID with the article it patches, feedback
+// on a patch is told apart from feedback on the article by its "patch"
+// column.
+type FeedbackRepository struct {
+  db *sql.DB
+}
+
+func NewFeedbackRepository(db *sql.DB) *FeedbackRepository {
+  return &FeedbackRepository{db}
+}
+
+// SharedContent retrieves the UUID and the content of the draft or the
+// patch a shareable link points to, as long as the link has not expired,
+// and the hash of the password that protects the link, if any.
+func (r *FeedbackRepository) SharedContent(ctx context.Context, link string) (id, content, passwordHash string, err error) {
+  getSharedContentQuery := `
+  SELECT a."uuid",
+         coalesce(p."content", a."content"),
+         coalesce(l."password_hash", '')
+    FROM "archive"."article_link" l
+         INNER JOIN "archive"."article" a
+                 ON a."uuid" = l."article_uuid"
+          LEFT JOIN "archive"."article_patch" p
+                 ON p."article_uuid" = a."uuid"
+   WHERE l."shareable_link" = $1
+     AND l."expires_at" > current_timestamp
+     AND (p."article_uuid" IS NOT NULL
+      OR (a."draft" IS TRUE AND a."published_at" IS NULL));`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  err = r.db.QueryRowContext(ctx, getSharedContentQuery, link).Scan(&id, &content, &passwordHash)
+  if nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      p := &problem.Problem{}
+      p.Status(http.StatusGone)
+      p.Title("Broken shareable link.")
+      p.Detail("This shareable link is no longer valid; it might have expired, or its draft might have been published or discarded.")
+      p.With("shareable_link", link)
+      return "", "", "", p
+    }
+
+    slog.Error(getErrMsg(err))
+    return "", "", "", err
+  }
+
+  return id, content, passwordHash, nil
+}
+
+// Content retrieves the content of a draft or, if the article has one,
+// of its patch.
+func (r *FeedbackRepository) Content(ctx context.Context, id string) (content string, err error) {
+  getContentQuery := `
+  SELECT coalesce(p."content", a."content")
+    FROM "archive"."article" a
+         LEFT JOIN "archive"."article_patch" p
+                ON p."article_uuid" = a."uuid"
+   WHERE a."uuid" = $1
+     AND (p."article_uuid" IS NOT NULL
+      OR (a."draft" IS TRUE AND a."published_at" IS NULL));`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  err = r.db.QueryRowContext(ctx, getContentQuery, id).Scan(&content)
+  if nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      return "", problem.NewNotFound(id, "draft")
+    }
+
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  return content, nil
+}
+
+// Add stores feedback on a draft or, if the article has one, on its
+// patch. It returns the UUID of the feedback.
+func (r *FeedbackRepository) Add(ctx context.Context, id string, feedback *model.Feedback) (feedbackID string, err error) {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  defer tx.Rollback()
+
+  addFeedbackQuery := `
+  INSERT INTO "archive"."feedback" ("article_uuid", "patch", "anchor", "anchor_text", "selection", "reviewer", "comment")
+       SELECT $1,
+              EXISTS (SELECT 1
+                        FROM "archive"."article_patch"
+                       WHERE "article_uuid" = $1),
+              $2, $3, $4, $5, $6
+    RETURNING "uuid";`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  err = tx.QueryRowContext(ctx, addFeedbackQuery,
+    id,
+    feedback.Anchor,
+    feedback.AnchorText,
+    feedback.Selection,
+    feedback.Reviewer,
+    feedback.Comment,
+  ).Scan(&feedbackID)
+
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  return feedbackID, nil
+}
+
+// List retrieves the feedback on a draft or, if the article has one, on
+// its patch: the unresolved feedback first, then from the oldest to the
+// newest.
+func (r *FeedbackRepository) List(ctx context.Context, id string) (feedback []*model.Feedback, err error) {
+  listFeedbackQuery := `
+  SELECT f."uuid",
+         f."anchor",
+         f."anchor_text",
+         f."selection",
+         f."reviewer",
+         f."comment",
+         f."orphaned",
+         f."resolved_at",
+         f."created_at"
+    FROM "archive"."feedback" f
+   WHERE f."article_uuid" = $1
+     AND f."patch" = EXISTS (SELECT 1
+                               FROM "archive"."article_patch" p
+                              WHERE p."article_uuid" = $1)
+ORDER BY f."resolved_at" IS NOT NULL, f."created_at";`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, listFeedbackQuery, id)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  feedback = make([]*model.Feedback, 0)
+
+  for result.Next() {
+    var f model.Feedback
+
+    err = result.Scan(
+      &f.UUID,
+      &f.Anchor,
+      &f.AnchorText,
+      &f.Selection,
+      &f.Reviewer,
+      &f.Comment,
+      &f.Orphaned,
+      &f.ResolvedAt,
+      &f.CreatedAt,
+    )
+
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    feedback = append(feedback, &f)
+  }
+
+  if err = result.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return feedback, nil
+}
+
+// Reanchor stores the anchors of feedback that were moved after its
+// draft or patch was revised, and whether they are orphaned.
+func (r *FeedbackRepository) Reanchor(ctx context.Context, feedback []*model.Feedback) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  reanchorFeedbackQuery := `
+  UPDATE "archive"."feedback"
+     SET "anchor" = $2,
+         "anchor_text" = $3,
+         "orphaned" = $4
+   WHERE "uuid" = $1;`
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  statement, err := tx.PrepareContext(ctx, reanchorFeedbackQuery)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer statement.Close()
+
+  for _, f := range feedback {
+    _, err = statement.ExecContext(ctx, f.UUID, f.Anchor, f.AnchorText, f.Orphaned)
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return err
+    }
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// Resolve marks feedback as resolved.
+func (r *FeedbackRepository) Resolve(ctx context.Context, feedbackID string) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  resolveFeedbackQuery := `
+  UPDATE "archive"."feedback"
+     SET "resolved_at" = current_timestamp
+   WHERE "uuid" = $1
+     AND "resolved_at" IS NULL;`
+
+  ctx1, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx1, resolveFeedbackQuery, feedbackID)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    feedbackExistsQuery := `
+    SELECT count(*)
+      FROM "archive"."feedback"
+     WHERE "uuid" = $1;`
+
+    var exists bool
+
+    ctx1, cancel = context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    if err = tx.QueryRowContext(ctx1, feedbackExistsQuery, feedbackID).Scan(&exists); nil != err {
+      slog.Error(getErrMsg(err))
+      return err
+    }
+
+    if !exists {
+      return problem.NewNotFound(feedbackID, "feedback")
+    }
+
+    var p problem.Problem
+    p.Type(problem.TypeActionAlreadyCompleted)
+    p.Title("Feedback already resolved.")
+    p.Status(http.StatusConflict)
+    p.Detail("Cannot resolve feedback that is already resolved.")
+    p.With("feedback_uuid", feedbackID)
+    return &p
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "fontseca.dev/model"
+  "log/slog"
+  "time"
+)
+
+// FollowersRepository is a low level API that provides methods for
+// interacting with the fediverse followers of the archive in the
+// database.
+type FollowersRepository struct {
+  db *sql.DB
+}
+
+func NewFollowersRepository(db *sql.DB) *FollowersRepository {
+  return &FollowersRepository{db}
+}
+
+// Add adds a new follower. If the actor already follows the archive, its
+// inboxes are updated instead.
+func (r *FollowersRepository) Add(ctx context.Context, follower *model.Follower) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  addFollowerQuery := `
+  INSERT INTO "archive"."follower" ("actor", "inbox", "shared_inbox")
+       VALUES ($1, $2, $3)
+  ON CONFLICT ("actor")
+DO UPDATE SET "inbox" = excluded."inbox",
+              "shared_inbox" = excluded."shared_inbox";`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  _, err = tx.ExecContext(ctx, addFollowerQuery,
+    follower.Actor,
+    follower.Inbox,
+    follower.SharedInbox,
+  )
+
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// Remove removes a follower. Removing an actor that does not follow
+// the archive has no effect.
+func (r *FollowersRepository) Remove(ctx context.Context, actor string) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  removeFollowerQuery := `
+  DELETE FROM "archive"."follower"
+        WHERE "actor" = $1;`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  _, err = tx.ExecContext(ctx, removeFollowerQuery, actor)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// List retrieves all the followers, from the oldest to the newest.
+func (r *FollowersRepository) List(ctx context.Context) (followers []*model.Follower, err error) {
+  listFollowersQuery := `
+  SELECT "actor",
+         "inbox",
+         "shared_inbox",
+         "followed_at"
+    FROM "archive"."follower"
+ORDER BY "followed_at";`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, listFollowersQuery)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  followers = make([]*model.Follower, 0)
+
+  for result.Next() {
+    var follower model.Follower
+
+    err = result.Scan(
+      &follower.Actor,
+      &follower.Inbox,
+      &follower.SharedInbox,
+      &follower.FollowedAt,
+    )
+
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    followers = append(followers, &follower)
+  }
+
+  if err = result.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return followers, nil
+}
+
+// Count counts the followers.
+func (r *FollowersRepository) Count(ctx context.Context) (count int64, err error) {
+  countFollowersQuery := `
+  SELECT count(*)
+    FROM "archive"."follower";`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  if err = r.db.QueryRowContext(ctx, countFollowersQuery).Scan(&count); nil != err {
+    slog.Error(getErrMsg(err))
+    return 0, err
+  }
+
+  return count, nil
+}
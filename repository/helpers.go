@@ -1,10 +1,15 @@
 package repository
 
 import (
+  "context"
+  "database/sql"
   "errors"
   "fmt"
+  "fontseca.dev/problem"
   "github.com/lib/pq"
+  "log/slog"
   "strings"
+  "time"
 )
 
 // getErrMsg formats and returns a detailed error message from a given error.
@@ -39,3 +44,25 @@ func getErrMsg(err error) string {
 
   return err.Error()
 }
+
+// staleVersion is used once an update guarded by a version affected no
+// rows, to tell apart a record that does not exist from one that was
+// modified since its expected version was read. The query must select
+// the current version of the record given its ID.
+func staleVersion(ctx context.Context, tx *sql.Tx, query, id, recordType string) error {
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  var current int
+
+  if err := tx.QueryRowContext(ctx, query, id).Scan(&current); nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      return problem.NewNotFound(id, recordType)
+    }
+
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return problem.NewConflict(id, recordType, current)
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "fontseca.dev/model"
+  "log/slog"
+  "time"
+)
+
+// IdempotencyRepository is a low level API that provides methods for
+// interacting with the responses stored by idempotency key in the
+// database.
+type IdempotencyRepository struct {
+  db *sql.DB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+  return &IdempotencyRepository{db}
+}
+
+// Reserve claims key for the first execution of a request to method,
+// after forgetting the responses stored longer ago than retention and
+// the claims still in progress after lease, whose execution is taken
+// for dead. If the key was already claimed, the stored response is
+// returned instead, even if its execution is still in progress;
+// otherwise, it is nil.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key, method, fingerprint string, retention, lease time.Duration) (stored *model.IdempotentResponse, err error) {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer tx.Rollback()
+
+  forgetExpiredQuery := `
+  DELETE FROM "idempotency"."response"
+        WHERE "created_at" < current_timestamp - make_interval(secs => $1)
+           OR ("status" IS NULL AND "created_at" < current_timestamp - make_interval(secs => $2));`
+
+  ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  if _, err = tx.ExecContext(ctx1, forgetExpiredQuery, retention.Seconds(), lease.Seconds()); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  reserveKeyQuery := `
+       INSERT INTO "idempotency"."response" ("key", "method", "fingerprint")
+            VALUES ($1, $2, $3)
+  ON CONFLICT ("key", "method") DO NOTHING;`
+
+  ctx2, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx2, reserveKeyQuery, key, method, fingerprint)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    getResponseQuery := `
+    SELECT "key",
+           "method",
+           "fingerprint",
+           coalesce ("status", 0),
+           coalesce ("content_type", ''),
+           "body",
+           "created_at"
+      FROM "idempotency"."response"
+     WHERE "key" = $1
+       AND "method" = $2;`
+
+    ctx3, cancel := context.WithTimeout(ctx, 2*time.Second)
+    defer cancel()
+
+    stored = new(model.IdempotentResponse)
+
+    err = tx.QueryRowContext(ctx3, getResponseQuery, key, method).Scan(
+      &stored.Key,
+      &stored.Method,
+      &stored.Fingerprint,
+      &stored.Status,
+      &stored.ContentType,
+      &stored.Body,
+      &stored.CreatedAt,
+    )
+
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return stored, nil
+}
+
+// Save stores the response to the first execution of a request, so that
+// it is replayed on its repeats.
+func (r *IdempotencyRepository) Save(ctx context.Context, response *model.IdempotentResponse) error {
+  saveResponseQuery := `
+  UPDATE "idempotency"."response"
+     SET "status" = $3,
+         "content_type" = $4,
+         "body" = $5
+   WHERE "key" = $1
+     AND "method" = $2;`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  _, err := r.db.ExecContext(ctx, saveResponseQuery,
+    response.Key,
+    response.Method,
+    response.Status,
+    response.ContentType,
+    response.Body)
+
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// Release frees key when the first execution of a request to method
+// could not be completed, so that the request can be retried.
+func (r *IdempotencyRepository) Release(ctx context.Context, key, method string) error {
+  releaseKeyQuery := `
+  DELETE FROM "idempotency"."response"
+        WHERE "key" = $1
+          AND "method" = $2
+          AND "status" IS NULL;`
+
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  if _, err := r.db.ExecContext(ctx, releaseKeyQuery, key, method); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
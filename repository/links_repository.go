@@ -0,0 +1,243 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "fontseca.dev/model"
+  "log/slog"
+  "time"
+)
+
+// LinksRepository is a low level API that provides methods for
+// interacting with the outbound links of articles and projects in the
+// database.
+type LinksRepository struct {
+  db *sql.DB
+}
+
+func NewLinksRepository(db *sql.DB) *LinksRepository {
+  return &LinksRepository{db}
+}
+
+// Sources retrieves the published articles and the projects whose links
+// are checked, with their content and the URLs of their fields.
+func (r *LinksRepository) Sources(ctx context.Context) (sources []*model.LinkSource, err error) {
+  listArticleSourcesQuery := `
+  SELECT "uuid",
+         "title",
+         "content",
+         "rendered_html",
+         "cover_url"
+    FROM "archive"."article"
+   WHERE NOT "draft"
+ORDER BY "published_at";`
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, listArticleSourcesQuery)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  sources = make([]*model.LinkSource, 0)
+
+  for result.Next() {
+    var (
+      source   = model.LinkSource{Type: "article"}
+      coverURL string
+    )
+
+    if err = result.Scan(&source.ID, &source.Title, &source.Content, &source.HTML, &coverURL); nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    source.URLs = []string{coverURL}
+    sources = append(sources, &source)
+  }
+
+  if err = result.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  listProjectSourcesQuery := `
+  SELECT "slug",
+         "name",
+         "content",
+         "homepage",
+         coalesce("company_homepage", ''),
+         "github_url",
+         "collection_url",
+         "playground_url",
+         "first_image_url",
+         "second_image_url"
+    FROM "projects"."project"
+ORDER BY "created_at";`
+
+  projects, err := r.db.QueryContext(ctx, listProjectSourcesQuery)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer projects.Close()
+
+  for projects.Next() {
+    var (
+      source = model.LinkSource{Type: "project"}
+      urls   = make([]string, 6)
+    )
+
+    err = projects.Scan(
+      &source.ID,
+      &source.Title,
+      &source.Content,
+      &urls[0],
+      &urls[1],
+      &urls[2],
+      &urls[3],
+      &urls[4],
+      &urls[5],
+    )
+
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    source.URLs = urls
+    sources = append(sources, &source)
+  }
+
+  if err = projects.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return sources, nil
+}
+
+// Save replaces the results of the previous check of the links with
+// the given ones.
+func (r *LinksRepository) Save(ctx context.Context, links []*model.Link) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  clearLinksQuery := `DELETE FROM "links"."link";`
+
+  ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+  defer cancel()
+
+  if _, err = tx.ExecContext(ctx, clearLinksQuery); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  saveLinkQuery := `
+  INSERT INTO "links"."link" ("source_type", "source_id", "source_title", "url", "status", "error", "broken", "checked_at")
+       VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+  ON CONFLICT ("source_type", "source_id", "url")
+   DO NOTHING;`
+
+  statement, err := tx.PrepareContext(ctx, saveLinkQuery)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer statement.Close()
+
+  for _, link := range links {
+    _, err = statement.ExecContext(ctx,
+      link.SourceType,
+      link.SourceID,
+      link.SourceTitle,
+      link.URL,
+      link.Status,
+      link.Error,
+      link.Broken,
+      link.CheckedAt,
+    )
+
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return err
+    }
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// ListBroken retrieves the links that were broken in their last check,
+// ordered by their article or project.
+func (r *LinksRepository) ListBroken(ctx context.Context) (links []*model.Link, err error) {
+  listBrokenLinksQuery := `
+  SELECT "source_type",
+         "source_id",
+         "source_title",
+         "url",
+         "status",
+         "error",
+         "broken",
+         "checked_at"
+    FROM "links"."link"
+   WHERE "broken"
+ORDER BY "source_type", "source_title", "source_id", "url";`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, listBrokenLinksQuery)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  links = make([]*model.Link, 0)
+
+  for result.Next() {
+    var link model.Link
+
+    err = result.Scan(
+      &link.SourceType,
+      &link.SourceID,
+      &link.SourceTitle,
+      &link.URL,
+      &link.Status,
+      &link.Error,
+      &link.Broken,
+      &link.CheckedAt,
+    )
+
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    links = append(links, &link)
+  }
+
+  if err = result.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return links, nil
+}
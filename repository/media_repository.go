@@ -0,0 +1,265 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "log/slog"
+  "time"
+)
+
+// MediaRepository is a low level API that provides methods for
+// interacting with the files of the media library in the database.
+type MediaRepository struct {
+  db *sql.DB
+}
+
+func NewMediaRepository(db *sql.DB) *MediaRepository {
+  return &MediaRepository{db}
+}
+
+// mediaURL is the URL a file of the media library is served at.
+func mediaURL(hash string) string {
+  return "/media/" + hash
+}
+
+// Add adds a new file to the media library. Adding a file that is
+// already there has no effect.
+func (r *MediaRepository) Add(ctx context.Context, media *model.Media) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  addMediaQuery := `
+  INSERT INTO "media"."file" ("hash", "name", "mime_type", "size", "width", "height", "alt_text")
+       VALUES ($1, $2, $3, $4, $5, $6, $7)
+  ON CONFLICT ("hash")
+   DO NOTHING;`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  _, err = tx.ExecContext(ctx, addMediaQuery,
+    media.Hash,
+    media.Name,
+    media.MIMEType,
+    media.Size,
+    media.Width,
+    media.Height,
+    media.AltText,
+  )
+
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// List retrieves all the files of the media library, from the newest
+// to the oldest.
+func (r *MediaRepository) List(ctx context.Context) (files []*model.Media, err error) {
+  listMediaQuery := `
+  SELECT "hash",
+         "name",
+         "mime_type",
+         "size",
+         "width",
+         "height",
+         "alt_text",
+         "uploaded_at"
+    FROM "media"."file"
+ORDER BY "uploaded_at" DESC, "hash";`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, listMediaQuery)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  files = make([]*model.Media, 0)
+
+  for result.Next() {
+    var media model.Media
+
+    err = result.Scan(
+      &media.Hash,
+      &media.Name,
+      &media.MIMEType,
+      &media.Size,
+      &media.Width,
+      &media.Height,
+      &media.AltText,
+      &media.UploadedAt,
+    )
+
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    media.URL = mediaURL(media.Hash)
+    files = append(files, &media)
+  }
+
+  if err = result.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return files, nil
+}
+
+// Get retrieves a file of the media library by its hash.
+func (r *MediaRepository) Get(ctx context.Context, hash string) (media *model.Media, err error) {
+  getMediaQuery := `
+  SELECT "hash",
+         "name",
+         "mime_type",
+         "size",
+         "width",
+         "height",
+         "alt_text",
+         "uploaded_at"
+    FROM "media"."file"
+   WHERE "hash" = $1;`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  media = new(model.Media)
+
+  err = r.db.QueryRowContext(ctx, getMediaQuery, hash).Scan(
+    &media.Hash,
+    &media.Name,
+    &media.MIMEType,
+    &media.Size,
+    &media.Width,
+    &media.Height,
+    &media.AltText,
+    &media.UploadedAt,
+  )
+
+  if nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      return nil, problem.NewNotFound(hash, "media")
+    }
+
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  media.URL = mediaURL(media.Hash)
+
+  return media, nil
+}
+
+// References retrieves the records whose content or fields refer to the
+// URL of a file of the media library, in the form 'type:id': articles
+// and drafts by their UUID, article patches by the UUID of their
+// article, projects by their slug and the profile as just 'me'.
+func (r *MediaRepository) References(ctx context.Context, hash string) (references []string, err error) {
+  getReferencesQuery := `
+  SELECT 'article:' || "uuid"
+    FROM "archive"."article"
+   WHERE strpos("content", $1) > 0
+      OR strpos(coalesce("cover_url", ''), $1) > 0
+   UNION ALL
+  SELECT 'article_patch:' || "article_uuid"
+    FROM "archive"."article_patch"
+   WHERE strpos(coalesce("content", ''), $1) > 0
+   UNION ALL
+  SELECT 'project:' || "slug"
+    FROM "projects"."project"
+   WHERE strpos("content", $1) > 0
+      OR strpos("first_image_url", $1) > 0
+      OR strpos("second_image_url", $1) > 0
+   UNION ALL
+  SELECT 'me'
+    FROM "me"."me"
+   WHERE strpos("photo_url", $1) > 0
+      OR strpos("resume_url", $1) > 0;`
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, getReferencesQuery, mediaURL(hash))
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  references = make([]string, 0)
+
+  for result.Next() {
+    var reference string
+
+    if err = result.Scan(&reference); nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    references = append(references, reference)
+  }
+
+  if err = result.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return references, nil
+}
+
+// Remove removes a file from the media library.
+func (r *MediaRepository) Remove(ctx context.Context, hash string) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  removeMediaQuery := `
+  DELETE FROM "media"."file"
+        WHERE "hash" = $1;`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx, removeMediaQuery, hash)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(hash, "media")
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
@@ -133,6 +133,7 @@ func (r *ProjectsRepository) Get(ctx context.Context, id string) (project *model
             p."finished",
             p."created_at",
             p."updated_at",
+            p."version",
             string_agg (tt."name", ',')
        FROM "projects"."project" p
   LEFT JOIN "projects"."project_tag" ptt
@@ -172,6 +173,7 @@ func (r *ProjectsRepository) Get(ctx context.Context, id string) (project *model
       &project.Finished,
       &project.CreatedAt,
       &project.UpdatedAt,
+      &project.Version,
       &tags)
 
   if nil != err {
@@ -394,8 +396,10 @@ func (r *ProjectsRepository) Update(ctx context.Context, id string, update *tran
          "company_homepage" = coalesce (nullif ($15, ''), "company_homepage"),
          "date_start" = coalesce (nullif ($16, '')::DATE, "date_start"),
          "date_end" = coalesce (nullif ($17, '')::DATE, "date_end"),
-         "updated_at" = current_timestamp
-   WHERE "uuid" = $1;`
+         "updated_at" = current_timestamp,
+         "version" = "version" + 1
+   WHERE "uuid" = $1
+     AND ($18 = 0 OR "version" = $18);`
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
   result, err := tx.ExecContext(ctx, updateProjectQuery,
@@ -415,14 +419,19 @@ func (r *ProjectsRepository) Update(ctx context.Context, id string, update *tran
     update.Company,
     update.CompanyHomepage,
     update.Starts,
-    update.Ends)
+    update.Ends,
+    update.Version)
   if nil != err {
     slog.Error(getErrMsg(err))
     return err
   }
   var affected, _ = result.RowsAffected()
   if 1 != affected {
-    return problem.NewNotFound(id, "project")
+    var currentVersionQuery = `
+    SELECT "version"
+      FROM "projects"."project"
+     WHERE "uuid" = $1;`
+    return staleVersion(ctx, tx, currentVersionQuery, id, "project")
   }
   if err = tx.Commit(); nil != err {
     slog.Error(getErrMsg(err))
@@ -442,7 +451,8 @@ func (r *ProjectsRepository) SetArchived(ctx context.Context, id string, archive
   var query = `
   UPDATE "projects"."project"
      SET "archived" = $2,
-         "updated_at" = current_timestamp
+         "updated_at" = current_timestamp,
+         "version" = "version" + 1
    WHERE "uuid" = $1;`
   ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
   defer cancel()
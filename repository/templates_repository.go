@@ -0,0 +1,369 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/lib/pq"
+  "log/slog"
+  "net/http"
+  "strings"
+  "time"
+)
+
+// TemplatesRepository is a low level API that provides methods for
+// interacting with the templates of article drafts in the database.
+type TemplatesRepository struct {
+  db *sql.DB
+}
+
+func NewTemplatesRepository(db *sql.DB) *TemplatesRepository {
+  return &TemplatesRepository{db}
+}
+
+// checkTemplateDefaults makes sure the default topic and tags of a
+// template exist.
+func checkTemplateDefaults(ctx context.Context, tx *sql.Tx, topic string, tags []string) error {
+  if "" != topic {
+    exists := false
+    topicExistsQuery := `
+    SELECT count (1)
+      FROM "archive"."topic"
+     WHERE "id" = $1;`
+
+    ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+    defer cancel()
+
+    if err := tx.QueryRowContext(ctx, topicExistsQuery, topic).Scan(&exists); nil != err {
+      slog.Error(getErrMsg(err))
+      return err
+    }
+
+    if !exists {
+      return problem.NewNotFound(topic, "topic")
+    }
+  }
+
+  if 0 == len(tags) {
+    return nil
+  }
+
+  missingTagQuery := `
+  SELECT unnest ($1::VARCHAR[])
+  EXCEPT
+  SELECT "id"
+    FROM "archive"."tag"
+   LIMIT 1;`
+
+  ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+  defer cancel()
+
+  var missing string
+
+  err := tx.QueryRowContext(ctx, missingTagQuery, pq.Array(tags)).Scan(&missing)
+  switch {
+  case errors.Is(err, sql.ErrNoRows):
+    return nil
+  case nil != err:
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return problem.NewNotFound(missing, "tag")
+}
+
+// setTemplateTags replaces the default tags of a template.
+func setTemplateTags(ctx context.Context, tx *sql.Tx, id string, tags []string) error {
+  clearTagsQuery := `
+  DELETE FROM "archive"."template_tag"
+        WHERE "template_uuid" = $1;`
+
+  addTagsQuery := `
+  INSERT INTO "archive"."template_tag" ("template_uuid", "tag_id")
+       SELECT $1, unnest ($2::VARCHAR[]);`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  if _, err := tx.ExecContext(ctx, clearTagsQuery, id); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if _, err := tx.ExecContext(ctx, addTagsQuery, id, pq.Array(tags)); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// duplicateTemplateName tells whether err is due to another template
+// already having name, and if so, returns the problem for it.
+func duplicateTemplateName(err error, name string) (*problem.Problem, bool) {
+  if !strings.Contains(err.Error(), `duplicate key value violates unique constraint "template_name_key"`) {
+    return nil, false
+  }
+
+  p := &problem.Problem{}
+  p.Type(problem.TypeDuplicateKey)
+  p.Status(http.StatusConflict)
+  p.Title("Duplicate template name.")
+  p.Detail("The provided template name is already registered. Try using a different one.")
+  p.With("name", name)
+  return p, true
+}
+
+// Create adds a new template of article drafts.
+func (r *TemplatesRepository) Create(ctx context.Context, creation *transfer.TemplateCreation) (id string, err error) {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  defer tx.Rollback()
+
+  if err = checkTemplateDefaults(ctx, tx, creation.Topic, creation.Tags); nil != err {
+    return "", err
+  }
+
+  createTemplateQuery := `
+  INSERT INTO "archive"."template" ("name",
+                                    "title_pattern",
+                                    "summary",
+                                    "content",
+                                    "topic")
+                 VALUES ($1,
+                         coalesce(nullif($2, ''), '{title}'),
+                         nullif($3, ''),
+                         nullif($4, ''),
+                         nullif($5, ''))
+              RETURNING "uuid";`
+
+  ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  err = tx.QueryRowContext(ctx1, createTemplateQuery,
+    creation.Name,
+    creation.TitlePattern,
+    creation.Summary,
+    creation.Content,
+    creation.Topic,
+  ).Scan(&id)
+
+  if nil != err {
+    if p, ok := duplicateTemplateName(err, creation.Name); ok {
+      return "", p
+    }
+
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  if err = setTemplateTags(ctx, tx, id, creation.Tags); nil != err {
+    return "", err
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return "", err
+  }
+
+  return id, nil
+}
+
+const selectTemplatesQuery = `
+     SELECT t."uuid",
+            t."name",
+            t."title_pattern",
+            t."summary",
+            t."content",
+            t."topic",
+            t."created_at",
+            t."updated_at",
+            string_agg (tt."tag_id", ',' ORDER BY tt."tag_id")
+       FROM "archive"."template" t
+  LEFT JOIN "archive"."template_tag" tt
+         ON tt."template_uuid" = t."uuid"`
+
+// scanTemplate scans a template selected by selectTemplatesQuery.
+func scanTemplate(row interface{ Scan(...any) error }) (*model.Template, error) {
+  var (
+    template = new(model.Template)
+    tags     sql.NullString
+  )
+
+  err := row.Scan(
+    &template.UUID,
+    &template.Name,
+    &template.TitlePattern,
+    &template.Summary,
+    &template.Content,
+    &template.TopicID,
+    &template.CreatedAt,
+    &template.UpdatedAt,
+    &tags,
+  )
+
+  if nil != err {
+    return nil, err
+  }
+
+  template.Tags = make([]string, 0)
+
+  if "" != tags.String {
+    template.Tags = strings.Split(tags.String, ",")
+  }
+
+  return template, nil
+}
+
+// List retrieves all the templates of article drafts, sorted by name.
+func (r *TemplatesRepository) List(ctx context.Context) (templates []*model.Template, err error) {
+  listTemplatesQuery := selectTemplatesQuery + `
+   GROUP BY t."uuid"
+   ORDER BY lower (t."name");`
+
+  ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+  defer cancel()
+
+  result, err := r.db.QueryContext(ctx, listTemplatesQuery)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  defer result.Close()
+
+  templates = make([]*model.Template, 0)
+
+  for result.Next() {
+    template, err := scanTemplate(result)
+    if nil != err {
+      slog.Error(getErrMsg(err))
+      return nil, err
+    }
+
+    templates = append(templates, template)
+  }
+
+  if err = result.Err(); nil != err {
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return templates, nil
+}
+
+// Get retrieves a template of article drafts by its UUID.
+func (r *TemplatesRepository) Get(ctx context.Context, id string) (template *model.Template, err error) {
+  getTemplateQuery := selectTemplatesQuery + `
+      WHERE t."uuid" = $1
+   GROUP BY t."uuid";`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  template, err = scanTemplate(r.db.QueryRowContext(ctx, getTemplateQuery, id))
+  if nil != err {
+    if errors.Is(err, sql.ErrNoRows) {
+      return nil, problem.NewNotFound(id, "template")
+    }
+
+    slog.Error(getErrMsg(err))
+    return nil, err
+  }
+
+  return template, nil
+}
+
+// Update updates an existing template of article drafts. Its default
+// tags are replaced only if update.Tags is not nil.
+func (r *TemplatesRepository) Update(ctx context.Context, id string, update *transfer.TemplateUpdate) error {
+  tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  defer tx.Rollback()
+
+  if err = checkTemplateDefaults(ctx, tx, update.Topic, update.Tags); nil != err {
+    return err
+  }
+
+  updateTemplateQuery := `
+  UPDATE "archive"."template"
+     SET "name" = coalesce (nullif ($2, ''), "name"),
+         "title_pattern" = coalesce (nullif ($3, ''), "title_pattern"),
+         "summary" = coalesce (nullif ($4, ''), "summary"),
+         "content" = coalesce (nullif ($5, ''), "content"),
+         "topic" = coalesce (nullif ($6, ''), "topic"),
+         "updated_at" = current_timestamp
+   WHERE "uuid" = $1;`
+
+  ctx1, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  result, err := tx.ExecContext(ctx1, updateTemplateQuery,
+    id,
+    update.Name,
+    update.TitlePattern,
+    update.Summary,
+    update.Content,
+    update.Topic,
+  )
+
+  if nil != err {
+    if p, ok := duplicateTemplateName(err, update.Name); ok {
+      return p
+    }
+
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(id, "template")
+  }
+
+  if nil != update.Tags {
+    if err = setTemplateTags(ctx, tx, id, update.Tags); nil != err {
+      return err
+    }
+  }
+
+  if err = tx.Commit(); nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  return nil
+}
+
+// Remove removes a template of article drafts. The drafts started with
+// it are kept as they are.
+func (r *TemplatesRepository) Remove(ctx context.Context, id string) error {
+  removeTemplateQuery := `
+  DELETE FROM "archive"."template"
+        WHERE "uuid" = $1;`
+
+  ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+  defer cancel()
+
+  result, err := r.db.ExecContext(ctx, removeTemplateQuery, id)
+  if nil != err {
+    slog.Error(getErrMsg(err))
+    return err
+  }
+
+  if affected, _ := result.RowsAffected(); 1 != affected {
+    return problem.NewNotFound(id, "template")
+  }
+
+  return nil
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+  "fmt"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/gomarkdown/markdown/ast"
+  "github.com/gomarkdown/markdown/parser"
+  "golang.org/x/net/html"
+  "strings"
+)
+
+const (
+  // lintMinSummaryLength is the number of characters a summary needs to
+  // describe an article in listings and link previews.
+  lintMinSummaryLength = 120
+
+  // lintMinTags is the number of tags an article needs to be found by
+  // them.
+  lintMinTags = 2
+
+  // lintMaxParagraphWords is the number of words a paragraph can have
+  // before it becomes hard to read.
+  lintMaxParagraphWords = 150
+)
+
+const (
+  lintError   = "error"
+  lintWarning = "warning"
+)
+
+// lintRule checks an article for a problem, returning a message for
+// every time it is found. Articles whose blocking rules fail are not
+// published unless it is forced.
+type lintRule struct {
+  name     string
+  blocking bool
+  check    func(article *model.Article, document ast.Node) []string
+}
+
+// lintRules are the rules articles are checked against before they are
+// published.
+var lintRules = []lintRule{
+  {name: "summary", blocking: true, check: lintSummary},
+  {name: "cover", blocking: true, check: lintCover},
+  {name: "cover_caption", check: lintCoverCaption},
+  {name: "topic", blocking: true, check: lintTopic},
+  {name: "tags", blocking: true, check: lintTags},
+  {name: "heading_order", check: lintHeadingOrder},
+  {name: "image_alt", check: lintImageAlt},
+  {name: "paragraph_length", check: lintParagraphLength},
+}
+
+// lintArticle checks an article against every lint rule.
+func lintArticle(article *model.Article) []*transfer.LintViolation {
+  var (
+    violations = make([]*transfer.LintViolation, 0)
+    document   = parser.NewWithExtensions(headingExtensions).Parse([]byte(article.Content))
+  )
+
+  for _, rule := range lintRules {
+    severity := lintWarning
+    if rule.blocking {
+      severity = lintError
+    }
+
+    for _, message := range rule.check(article, document) {
+      violations = append(violations, &transfer.LintViolation{
+        Rule:     rule.name,
+        Severity: severity,
+        Message:  message,
+      })
+    }
+  }
+
+  return violations
+}
+
+func lintSummary(article *model.Article, _ ast.Node) []string {
+  summary := strings.TrimSpace(article.Summary)
+
+  switch {
+  case "" == summary || "no summary" == summary:
+    return []string{"The article has no summary."}
+  case lintMinSummaryLength > len(summary):
+    return []string{fmt.Sprintf("The summary is %d characters long; it must be at least %d.", len(summary), lintMinSummaryLength)}
+  }
+
+  return nil
+}
+
+func hasCover(article *model.Article) bool {
+  cover := strings.TrimSpace(article.CoverURL)
+  return "" != cover && "about:blank" != cover
+}
+
+func lintCover(article *model.Article, _ ast.Node) []string {
+  if !hasCover(article) {
+    return []string{"The article has no cover."}
+  }
+
+  return nil
+}
+
+func lintCoverCaption(article *model.Article, _ ast.Node) []string {
+  if hasCover(article) && (nil == article.CoverCap || "" == strings.TrimSpace(*article.CoverCap)) {
+    return []string{"The cover has no caption, so its alternative text falls back to the summary."}
+  }
+
+  return nil
+}
+
+func lintTopic(article *model.Article, _ ast.Node) []string {
+  if nil == article.Topic || "" == article.Topic.ID {
+    return []string{"The article does not belong to a topic."}
+  }
+
+  return nil
+}
+
+func lintTags(article *model.Article, _ ast.Node) []string {
+  if lintMinTags > len(article.Tags) {
+    return []string{fmt.Sprintf("The article has %d tags; it needs at least %d.", len(article.Tags), lintMinTags)}
+  }
+
+  return nil
+}
+
+// lintHeadingOrder checks that headings never skip a level. The title of
+// the article is its only first-level heading, so the content starts at
+// the second level.
+func lintHeadingOrder(_ *model.Article, document ast.Node) []string {
+  var (
+    messages = make([]string, 0)
+    previous = 1
+  )
+
+  ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+    heading, ok := node.(*ast.Heading)
+    if !ok || !entering || heading.IsTitleblock {
+      return ast.GoToNext
+    }
+
+    switch {
+    case 1 == heading.Level:
+      messages = append(messages, fmt.Sprintf("The heading %q is a first-level heading; only the title can be one.", headingText(heading)))
+    case previous+1 < heading.Level:
+      messages = append(messages, fmt.Sprintf("The heading %q is a level-%d heading after a level-%d one.", headingText(heading), heading.Level, previous))
+    }
+
+    previous = heading.Level
+
+    return ast.SkipChildren
+  })
+
+  return messages
+}
+
+// lintImageAlt checks that every image has an alternative text, be it
+// a Markdown image or an <img> tag.
+func lintImageAlt(_ *model.Article, document ast.Node) []string {
+  messages := make([]string, 0)
+
+  ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+    if !entering {
+      return ast.GoToNext
+    }
+
+    switch n := node.(type) {
+    case *ast.Image:
+      if "" == plainText(n) {
+        messages = append(messages, fmt.Sprintf("The image %s has no alternative text.", n.Destination))
+      }
+
+      return ast.SkipChildren
+    case *ast.HTMLBlock:
+      messages = append(messages, lintHTMLImageAlt(n.Literal)...)
+    case *ast.HTMLSpan:
+      messages = append(messages, lintHTMLImageAlt(n.Literal)...)
+    }
+
+    return ast.GoToNext
+  })
+
+  return messages
+}
+
+func lintHTMLImageAlt(literal []byte) []string {
+  var (
+    messages = make([]string, 0)
+    z        = html.NewTokenizer(strings.NewReader(string(literal)))
+  )
+
+  for {
+    tt := z.Next()
+    if html.ErrorToken == tt {
+      return messages
+    }
+
+    if html.StartTagToken != tt && html.SelfClosingTagToken != tt {
+      continue
+    }
+
+    token := z.Token()
+    if "img" != token.Data {
+      continue
+    }
+
+    var src, alt string
+
+    for _, attr := range token.Attr {
+      switch attr.Key {
+      case "src":
+        src = attr.Val
+      case "alt":
+        alt = strings.TrimSpace(attr.Val)
+      }
+    }
+
+    if "" == alt {
+      messages = append(messages, fmt.Sprintf("The image %s has no alternative text.", src))
+    }
+  }
+}
+
+func lintParagraphLength(_ *model.Article, document ast.Node) []string {
+  messages := make([]string, 0)
+
+  ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+    paragraph, ok := node.(*ast.Paragraph)
+    if !ok || !entering {
+      return ast.GoToNext
+    }
+
+    text := plainText(paragraph)
+
+    if words := wordsIn(text); lintMaxParagraphWords < words {
+      opening := strings.Join(strings.Fields(text)[:5], " ")
+      messages = append(messages, fmt.Sprintf("The paragraph that starts with %q has %d words; keep paragraphs under %d.", opening+"…", words, lintMaxParagraphWords))
+    }
+
+    return ast.SkipChildren
+  })
+
+  return messages
+}
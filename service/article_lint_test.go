@@ -0,0 +1,62 @@
+package service
+
+import (
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "strings"
+  "testing"
+)
+
+// lintableArticle is a draft that breaks no lint rule.
+func lintableArticle() *model.Article {
+  caption := "Lorem ipsum dolor sit amet."
+
+  return &model.Article{
+    Title:    "Lorem ipsum",
+    Summary:  strings.Repeat("Lorem ipsum dolor sit amet. ", 5),
+    CoverURL: "https://example.com/cover.png",
+    CoverCap: &caption,
+    Topic:    &model.Topic{ID: "lorem"},
+    Tags:     []*model.Tag{{ID: "lorem"}, {ID: "ipsum"}},
+    Content:  "## Lorem\n\nIpsum dolor sit amet.\n\n### Ipsum\n\n![Lorem](/media/lorem)",
+  }
+}
+
+func TestLintArticle(t *testing.T) {
+  rules := func(violations []*transfer.LintViolation) []string {
+    names := make([]string, 0)
+    for _, violation := range violations {
+      names = append(names, violation.Rule+":"+violation.Severity)
+    }
+    return names
+  }
+
+  t.Run("success", func(t *testing.T) {
+    assert.Empty(t, lintArticle(lintableArticle()))
+  })
+
+  t.Run("default draft", func(t *testing.T) {
+    draft := &model.Article{Title: "Lorem ipsum", Summary: "no summary", CoverURL: "about:blank", Content: "no content"}
+    assert.Equal(t, []string{"summary:error", "cover:error", "topic:error", "tags:error"}, rules(lintArticle(draft)))
+  })
+
+  t.Run("short summary and uncaptioned cover", func(t *testing.T) {
+    draft := lintableArticle()
+    draft.Summary = "Lorem ipsum."
+    draft.CoverCap = nil
+    assert.Equal(t, []string{"summary:error", "cover_caption:warning"}, rules(lintArticle(draft)))
+  })
+
+  t.Run("content", func(t *testing.T) {
+    draft := lintableArticle()
+    draft.Content = "# Lorem\n\n## Ipsum\n\n#### Dolor\n\n![](/media/lorem)\n\n<img src=\"/media/ipsum\">\n\n" + strings.Repeat("lorem ", 151)
+
+    violations := lintArticle(draft)
+
+    assert.Equal(t, []string{"heading_order:warning", "heading_order:warning", "image_alt:warning", "image_alt:warning", "paragraph_length:warning"}, rules(violations))
+    assert.Contains(t, violations[1].Message, `"Dolor" is a level-4 heading after a level-2 one`)
+    assert.Contains(t, violations[3].Message, "/media/ipsum")
+    assert.Contains(t, violations[4].Message, "151 words")
+  })
+}
@@ -3,11 +3,13 @@ package service
 import (
   "context"
   "errors"
+  "fmt"
   "fontseca.dev/model"
   "fontseca.dev/problem"
   "fontseca.dev/transfer"
   "log/slog"
   "strings"
+  "time"
 )
 
 type archiveRepositoryAPIForArticles interface {
@@ -15,7 +17,7 @@ type archiveRepositoryAPIForArticles interface {
   SetArticleSummary(ctx context.Context, articleID, summary string, readtime int64) error
   SetArticleCover(ctx context.Context, articleID, coverURL, coverCaption string, readtime int64) error
   Publications(ctx context.Context) (publications []*transfer.Publication, err error)
-  List(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (articles []*transfer.Article, err error)
+  List(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (page *transfer.Page[*transfer.Article], err error)
   Get(ctx context.Context, request *transfer.ArticleRequest) (article *model.Article, err error)
   GetByID(ctx context.Context, articleID string, isDraft bool) (article *model.Article, err error)
   Amend(ctx context.Context, articleID string) error
@@ -23,6 +25,13 @@ type archiveRepositoryAPIForArticles interface {
   AddTag(ctx context.Context, articleID, tagID string, isDraft ...bool) error
   RemoveTag(ctx context.Context, articleID, tagID string, isDraft ...bool) error
   SetHidden(ctx context.Context, articleID string, hidden bool) error
+  ScheduleShow(ctx context.Context, articleID string, at time.Time, hide bool) error
+  ShowScheduled(ctx context.Context) (shown int64, err error)
+  SetOutdated(ctx context.Context, articleID, reason string, supersededBy *string) error
+  UnsetOutdated(ctx context.Context, articleID string) error
+  MarkStale(ctx context.Context, topics []string, before time.Time, reason string) (marked int64, err error)
+  AddSyndication(ctx context.Context, articleID, copyURL string, name *string) error
+  RemoveSyndication(ctx context.Context, articleID, copyURL string) error
   SetPinned(ctx context.Context, articleID string, pinned bool) error
 }
 
@@ -30,11 +39,20 @@ type cacher interface {
   SetCache(ctx context.Context)
 }
 
+// OutdatedRule tells which articles are considered outdated by age: those
+// of Topics that have not been modified nor reviewed for more than Years.
+type OutdatedRule struct {
+  Years  int
+  Topics []string
+}
+
 // ArticlesService is a high level provider for articles.
 type ArticlesService struct {
   r            archiveRepositoryAPIForArticles
   topicsCacher cacher
   tagsCacher   cacher
+  outdatedRule *OutdatedRule
+  renderer     renderer
 }
 
 func NewArticlesService(r archiveRepositoryAPIForArticles, topicsService cacher, tagsService cacher) *ArticlesService {
@@ -45,7 +63,11 @@ func NewArticlesService(r archiveRepositoryAPIForArticles, topicsService cacher,
   }
 }
 
-func (s *ArticlesService) list(ctx context.Context, filter *transfer.ArticleFilter, hidden ...bool) (articles []*transfer.Article, err error) {
+func (s *ArticlesService) list(ctx context.Context, filter *transfer.ArticleFilter, hidden ...bool) (page *transfer.Page[*transfer.Article], err error) {
+  if err = validateArticleFilter(filter); nil != err {
+    return nil, err
+  }
+
   if 0 < len(hidden) {
     return s.r.List(ctx, filter, hidden[0], false)
   }
@@ -59,7 +81,7 @@ func (s *ArticlesService) list(ctx context.Context, filter *transfer.ArticleFilt
 // function over articles, so it attempts to find and amass every
 // article whose title contains any of the keywords (if more than one)
 // in filter.Search.
-func (s *ArticlesService) List(ctx context.Context, filter *transfer.ArticleFilter) (articles []*transfer.Article, err error) {
+func (s *ArticlesService) List(ctx context.Context, filter *transfer.ArticleFilter) (page *transfer.Page[*transfer.Article], err error) {
   return s.list(ctx, filter)
 }
 
@@ -74,7 +96,7 @@ func (s *ArticlesService) Publications(ctx context.Context) (publications []*tra
 // function over articles, so it attempts to find and amass every
 // article whose title contains any of the keywords (if more than one)
 // in filter.Search.
-func (s *ArticlesService) ListHidden(ctx context.Context, filter *transfer.ArticleFilter) (articles []*transfer.Article, err error) {
+func (s *ArticlesService) ListHidden(ctx context.Context, filter *transfer.ArticleFilter) (page *transfer.Page[*transfer.Article], err error) {
   return s.list(ctx, filter, true)
 }
 
@@ -86,7 +108,9 @@ func (s *ArticlesService) Get(ctx context.Context, request *transfer.ArticleRequ
     return nil, err
   }
 
-  return s.r.Get(ctx, request)
+  article, err = s.r.Get(ctx, request)
+  applyRendering(s.renderer, article)
+  return article, err
 }
 
 // GetByID retrieves one article by its UUID.
@@ -95,7 +119,9 @@ func (s *ArticlesService) GetByID(ctx context.Context, articleUUID string) (arti
     return nil, err
   }
 
-  return s.r.GetByID(ctx, articleUUID, false)
+  article, err = s.r.GetByID(ctx, articleUUID, false)
+  applyRendering(s.renderer, article)
+  return article, err
 }
 
 // Hide hides an article.
@@ -128,6 +154,226 @@ func (s *ArticlesService) Show(ctx context.Context, id string) error {
   return nil
 }
 
+// HideUntil hides an article until the given date, which can be either
+// in the form YYYY-MM-DD or an RFC 3339 timestamp. Once it comes, the
+// article is shown again by RunScheduler.
+func (s *ArticlesService) HideUntil(ctx context.Context, id, until string) error {
+  if err := validateUUID(&id); nil != err {
+    return err
+  }
+
+  date, err := parseFutureTime("until", until)
+  if nil != err {
+    return err
+  }
+
+  err = s.r.ScheduleShow(ctx, id, date, true)
+  if nil != err {
+    return err
+  }
+
+  s.setCaches(ctx)
+  return nil
+}
+
+// ShowAt schedules a hidden article to be shown at the given date, which
+// can be either in the form YYYY-MM-DD or an RFC 3339 timestamp. Until
+// then, the visibility of the article remains unchanged.
+func (s *ArticlesService) ShowAt(ctx context.Context, id, at string) error {
+  if err := validateUUID(&id); nil != err {
+    return err
+  }
+
+  date, err := parseFutureTime("at", at)
+  if nil != err {
+    return err
+  }
+
+  return s.r.ScheduleShow(ctx, id, date, false)
+}
+
+// ShowScheduled shows every article whose scheduled time to be shown has
+// come. If any article is shown, the caches are refreshed.
+func (s *ArticlesService) ShowScheduled(ctx context.Context) error {
+  shown, err := s.r.ShowScheduled(ctx)
+  if nil != err {
+    return err
+  }
+
+  if 0 < shown {
+    slog.Info("shown scheduled articles", slog.Int64("count", shown))
+    s.setCaches(ctx)
+  }
+
+  return nil
+}
+
+// RunScheduler calls ShowScheduled and MarkStale every interval until ctx
+// is done.
+func (s *ArticlesService) RunScheduler(ctx context.Context, interval time.Duration) {
+  ticker := time.NewTicker(interval)
+  defer ticker.Stop()
+
+  for {
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+      if err := s.ShowScheduled(ctx); nil != err {
+        slog.Error(err.Error())
+      }
+
+      if err := s.MarkStale(ctx); nil != err {
+        slog.Error(err.Error())
+      }
+    }
+  }
+}
+
+// SetOutdatedRule sets the rule used by MarkStale. A nil rule, or one with
+// no topics or years, disables it.
+func (s *ArticlesService) SetOutdatedRule(rule *OutdatedRule) {
+  s.outdatedRule = rule
+}
+
+// SetRenderer sets the renderer whose stored renderings complete the
+// retrieved articles. A nil renderer disables it.
+func (s *ArticlesService) SetRenderer(r renderer) {
+  s.renderer = r
+}
+
+// MarkStale marks as outdated the articles that meet the outdated rule,
+// if any is set with SetOutdatedRule.
+func (s *ArticlesService) MarkStale(ctx context.Context) error {
+  rule := s.outdatedRule
+
+  if nil == rule || 0 >= rule.Years || 0 == len(rule.Topics) {
+    return nil
+  }
+
+  var (
+    before = time.Now().AddDate(-rule.Years, 0, 0)
+    reason = fmt.Sprintf("This article was written more than %d years ago and some of its content may no longer apply.", rule.Years)
+  )
+
+  if 1 == rule.Years {
+    reason = "This article was written more than a year ago and some of its content may no longer apply."
+  }
+
+  marked, err := s.r.MarkStale(ctx, rule.Topics, before, reason)
+  if nil != err {
+    return err
+  }
+
+  if 0 < marked {
+    slog.Info("marked stale articles as outdated", slog.Int64("count", marked))
+  }
+
+  return nil
+}
+
+// MarkOutdated marks an article as outdated for the given reason. If
+// supersededBy is not empty, it must be the UUID of the published article
+// that replaces it, so readers are pointed to it.
+func (s *ArticlesService) MarkOutdated(ctx context.Context, id, reason, supersededBy string) error {
+  if err := validateUUID(&id); nil != err {
+    return err
+  }
+
+  reason = strings.TrimSpace(reason)
+  sanitizeTextWordIntersections(&reason)
+
+  if "" == reason {
+    return problem.NewMissingParameter("reason")
+  }
+
+  if 512 < len(reason) {
+    return problem.NewValidation([3]string{"reason", "max", "512"})
+  }
+
+  var replacement *string
+
+  if "" != strings.TrimSpace(supersededBy) {
+    if err := validateUUID(&supersededBy); nil != err {
+      return err
+    }
+
+    if id == supersededBy {
+      return problem.NewValidation([3]string{"superseded_by", "nefield", "article_uuid"})
+    }
+
+    if _, err := s.r.GetByID(ctx, supersededBy, false); nil != err {
+      return err
+    }
+
+    replacement = &supersededBy
+  }
+
+  return s.r.SetOutdated(ctx, id, reason, replacement)
+}
+
+// UnmarkOutdated removes the outdated notice of an article.
+func (s *ArticlesService) UnmarkOutdated(ctx context.Context, id string) error {
+  if err := validateUUID(&id); nil != err {
+    return err
+  }
+
+  return s.r.UnsetOutdated(ctx, id)
+}
+
+// AddSyndication records a copy of an article published on another
+// platform at copyURL. The name of the platform is optional.
+func (s *ArticlesService) AddSyndication(ctx context.Context, id, copyURL, name string) error {
+  if err := validateUUID(&id); nil != err {
+    return err
+  }
+
+  copyURL = strings.TrimSpace(copyURL)
+
+  if "" == copyURL {
+    return problem.NewMissingParameter("url")
+  }
+
+  if err := sanitizeWebURL(&copyURL); nil != err {
+    return err
+  }
+
+  name = strings.TrimSpace(name)
+  sanitizeTextWordIntersections(&name)
+
+  switch {
+  case 2048 < len(copyURL):
+    return problem.NewValidation([3]string{"url", "max", "2048"})
+  case 64 < len(name):
+    return problem.NewValidation([3]string{"name", "max", "64"})
+  }
+
+  var platform *string
+
+  if "" != name {
+    platform = &name
+  }
+
+  return s.r.AddSyndication(ctx, id, copyURL, platform)
+}
+
+// RemoveSyndication removes the copy of an article at copyURL.
+func (s *ArticlesService) RemoveSyndication(ctx context.Context, id, copyURL string) error {
+  if err := validateUUID(&id); nil != err {
+    return err
+  }
+
+  if err := sanitizeURL(&copyURL); nil != err {
+    return err
+  }
+
+  if "" == copyURL {
+    return problem.NewMissingParameter("url")
+  }
+
+  return s.r.RemoveSyndication(ctx, id, copyURL)
+}
+
 // SetSlug changes the slug of an article.
 func (s *ArticlesService) SetSlug(ctx context.Context, id, slug string) error {
   if err := validateUUID(&id); nil != err {
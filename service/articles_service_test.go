@@ -8,7 +8,9 @@ import (
   "github.com/google/uuid"
   "github.com/stretchr/testify/assert"
   "github.com/stretchr/testify/require"
+  "strings"
   "testing"
+  "time"
 )
 
 type archiveRepositoryMockAPIForArticles struct {
@@ -20,7 +22,7 @@ type archiveRepositoryMockAPIForArticles struct {
   called    bool
 }
 
-func (mock *archiveRepositoryMockAPIForArticles) List(_ context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (articles []*transfer.Article, err error) {
+func (mock *archiveRepositoryMockAPIForArticles) List(_ context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (page *transfer.Page[*transfer.Article], err error) {
   mock.called = true
 
   if nil != mock.t {
@@ -29,7 +31,7 @@ func (mock *archiveRepositoryMockAPIForArticles) List(_ context.Context, filter
     require.Equal(mock.t, mock.arguments[3], draftsOnly)
   }
 
-  return mock.returns[0].([]*transfer.Article), mock.errors
+  return mock.returns[0].(*transfer.Page[*transfer.Article]), mock.errors
 }
 
 type cacherMock struct{}
@@ -43,7 +45,7 @@ func TestArticlesService_Get(t *testing.T) {
   filter := &transfer.ArticleFilter{}
 
   t.Run("success", func(t *testing.T) {
-    expectedArticles := []*transfer.Article{{}, {}, {}}
+    expectedArticles := &transfer.Page[*transfer.Article]{Items: []*transfer.Article{{}, {}, {}}}
 
     r := &archiveRepositoryMockAPIForArticles{t: t, arguments: []any{ctx, filter, false, false}, returns: []any{expectedArticles}}
     articles, err := NewArticlesService(r, cacherImpl, cacherImpl).List(ctx, filter)
@@ -54,7 +56,7 @@ func TestArticlesService_Get(t *testing.T) {
 
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
-    r := &archiveRepositoryMockAPIForArticles{returns: []any{([]*transfer.Article)(nil)}, errors: unexpected}
+    r := &archiveRepositoryMockAPIForArticles{returns: []any{(*transfer.Page[*transfer.Article])(nil)}, errors: unexpected}
     _, err := NewArticlesService(r, cacherImpl, cacherImpl).List(ctx, filter)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -65,7 +67,7 @@ func TestArticlesService_GetHidden(t *testing.T) {
   filter := &transfer.ArticleFilter{}
 
   t.Run("success", func(t *testing.T) {
-    expectedArticles := []*transfer.Article{{}, {}, {}}
+    expectedArticles := &transfer.Page[*transfer.Article]{Items: []*transfer.Article{{}, {}, {}}}
 
     r := &archiveRepositoryMockAPIForArticles{t: t, arguments: []any{ctx, filter, true, false}, returns: []any{expectedArticles}}
     articles, err := NewArticlesService(r, cacherImpl, cacherImpl).ListHidden(ctx, filter)
@@ -77,7 +79,7 @@ func TestArticlesService_GetHidden(t *testing.T) {
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
 
-    r := &archiveRepositoryMockAPIForArticles{returns: []any{([]*transfer.Article)(nil)}, errors: unexpected}
+    r := &archiveRepositoryMockAPIForArticles{returns: []any{(*transfer.Page[*transfer.Article])(nil)}, errors: unexpected}
     _, err := NewArticlesService(r, cacherImpl, cacherImpl).ListHidden(ctx, filter)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -165,6 +167,114 @@ func TestArticlesService_Hide(t *testing.T) {
   })
 }
 
+func (mock *archiveRepositoryMockAPIForArticles) ScheduleShow(_ context.Context, articleID string, at time.Time, hide bool) error {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], articleID)
+    require.Equal(mock.t, mock.arguments[2], at)
+    require.Equal(mock.t, mock.arguments[3], hide)
+  }
+
+  return mock.errors
+}
+
+func TestArticlesService_HideUntil(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+  until := time.Now().AddDate(1, 0, 0).Truncate(time.Second).UTC()
+
+  t.Run("success", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{t: t, arguments: []any{ctx, id, until, true}}
+    assert.NoError(t, NewArticlesService(r, cacherImpl, cacherImpl).HideUntil(ctx, id, until.Format(time.RFC3339)))
+  })
+
+  t.Run("accepts a date only", func(t *testing.T) {
+    date, _ := time.Parse(time.DateOnly, until.Format(time.DateOnly))
+    r := &archiveRepositoryMockAPIForArticles{t: t, arguments: []any{ctx, id, date, true}}
+    assert.NoError(t, NewArticlesService(r, cacherImpl, cacherImpl).HideUntil(ctx, id, until.Format(time.DateOnly)))
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &archiveRepositoryMockAPIForArticles{errors: unexpected}
+    assert.ErrorIs(t, NewArticlesService(r, cacherImpl, cacherImpl).HideUntil(ctx, id, until.Format(time.RFC3339)), unexpected)
+  })
+
+  t.Run("wrong dates", func(t *testing.T) {
+    dates := []string{
+      "",
+      "tomorrow",
+      "2020-01-01",
+      time.Now().Add(-time.Minute).Format(time.RFC3339),
+    }
+
+    for _, date := range dates {
+      r := &archiveRepositoryMockAPIForArticles{}
+      assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).HideUntil(ctx, id, date), "date was: %q", date)
+      assert.False(t, r.called)
+    }
+  })
+
+  t.Run("wrong uuid", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).HideUntil(ctx, "e4d06ba7-f086-47dc-9f5e", until.Format(time.RFC3339)))
+    assert.False(t, r.called)
+  })
+}
+
+func TestArticlesService_ShowAt(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+  at := time.Now().AddDate(0, 1, 0).Truncate(time.Second).UTC()
+
+  t.Run("success", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{t: t, arguments: []any{ctx, id, at, false}}
+    assert.NoError(t, NewArticlesService(r, cacherImpl, cacherImpl).ShowAt(ctx, id, at.Format(time.RFC3339)))
+  })
+
+  t.Run("date in the past", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).ShowAt(ctx, id, "2020-01-01"))
+    assert.False(t, r.called)
+  })
+}
+
+func (mock *archiveRepositoryMockAPIForArticles) ShowScheduled(context.Context) (int64, error) {
+  mock.called = true
+  return mock.returns[0].(int64), mock.errors
+}
+
+type cacherCountingMock struct{ calls int }
+
+func (mock *cacherCountingMock) SetCache(context.Context) { mock.calls++ }
+
+func TestArticlesService_ShowScheduled(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("refreshes caches when articles are shown", func(t *testing.T) {
+    c := &cacherCountingMock{}
+    r := &archiveRepositoryMockAPIForArticles{returns: []any{int64(2)}}
+    assert.NoError(t, NewArticlesService(r, c, c).ShowScheduled(ctx))
+    assert.Equal(t, 2, c.calls)
+  })
+
+  t.Run("keeps caches when nothing is shown", func(t *testing.T) {
+    c := &cacherCountingMock{}
+    r := &archiveRepositoryMockAPIForArticles{returns: []any{int64(0)}}
+    assert.NoError(t, NewArticlesService(r, c, c).ShowScheduled(ctx))
+    assert.Zero(t, c.calls)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    c := &cacherCountingMock{}
+    r := &archiveRepositoryMockAPIForArticles{returns: []any{int64(0)}, errors: unexpected}
+    assert.ErrorIs(t, NewArticlesService(r, c, c).ShowScheduled(ctx), unexpected)
+    assert.Zero(t, c.calls)
+  })
+}
+
 func TestArticlesService_Show(t *testing.T) {
   ctx := context.TODO()
   id := uuid.NewString()
@@ -190,6 +300,140 @@ func TestArticlesService_Show(t *testing.T) {
   })
 }
 
+type outdatedRepositoryMock struct {
+  archiveRepositoryAPIForArticles
+  found        bool // whether GetByID finds the superseding article
+  marked       int64
+  errors       error
+  called       bool
+  reason       string
+  supersededBy *string
+  topics       []string
+}
+
+func (mock *outdatedRepositoryMock) GetByID(_ context.Context, id string, _ bool) (*model.Article, error) {
+  if !mock.found {
+    return nil, errors.New("not found")
+  }
+
+  return &model.Article{UUID: uuid.MustParse(id)}, nil
+}
+
+func (mock *outdatedRepositoryMock) SetOutdated(_ context.Context, _, reason string, supersededBy *string) error {
+  mock.called = true
+  mock.reason = reason
+  mock.supersededBy = supersededBy
+  return mock.errors
+}
+
+func (mock *outdatedRepositoryMock) UnsetOutdated(context.Context, string) error {
+  mock.called = true
+  return mock.errors
+}
+
+func (mock *outdatedRepositoryMock) MarkStale(_ context.Context, topics []string, _ time.Time, reason string) (int64, error) {
+  mock.called = true
+  mock.topics = topics
+  mock.reason = reason
+  return mock.marked, mock.errors
+}
+
+func TestArticlesService_MarkOutdated(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+  replacement := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    r := &outdatedRepositoryMock{}
+    assert.NoError(t, NewArticlesService(r, cacherImpl, cacherImpl).MarkOutdated(ctx, id, "  Go 1.22   changed it. ", ""))
+    assert.True(t, r.called)
+    assert.Equal(t, "Go 1.22 changed it.", r.reason)
+    assert.Nil(t, r.supersededBy)
+  })
+
+  t.Run("success with a replacement", func(t *testing.T) {
+    r := &outdatedRepositoryMock{found: true}
+    assert.NoError(t, NewArticlesService(r, cacherImpl, cacherImpl).MarkOutdated(ctx, id, "Superseded.", replacement))
+    require.NotNil(t, r.supersededBy)
+    assert.Equal(t, replacement, *r.supersededBy)
+  })
+
+  t.Run("replacement not found", func(t *testing.T) {
+    r := &outdatedRepositoryMock{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).MarkOutdated(ctx, id, "Superseded.", replacement))
+    assert.False(t, r.called)
+  })
+
+  t.Run("superseded by itself", func(t *testing.T) {
+    r := &outdatedRepositoryMock{found: true}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).MarkOutdated(ctx, id, "Superseded.", id))
+    assert.False(t, r.called)
+  })
+
+  t.Run("wrong reason", func(t *testing.T) {
+    for _, reason := range []string{" \t\n ", strings.Repeat("x", 513)} {
+      r := &outdatedRepositoryMock{}
+      assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).MarkOutdated(ctx, id, reason, ""))
+      assert.False(t, r.called)
+    }
+  })
+
+  t.Run("wrong uuid", func(t *testing.T) {
+    r := &outdatedRepositoryMock{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).MarkOutdated(ctx, "e4d06ba7-f086-47dc-9f5e", "Superseded.", ""))
+    assert.False(t, r.called)
+  })
+}
+
+func TestArticlesService_UnmarkOutdated(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    r := &outdatedRepositoryMock{}
+    assert.NoError(t, NewArticlesService(r, cacherImpl, cacherImpl).UnmarkOutdated(ctx, uuid.NewString()))
+    assert.True(t, r.called)
+  })
+
+  t.Run("wrong uuid", func(t *testing.T) {
+    r := &outdatedRepositoryMock{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).UnmarkOutdated(ctx, "e4d06ba7-f086-47dc-9f5e"))
+    assert.False(t, r.called)
+  })
+}
+
+func TestArticlesService_MarkStale(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("disabled without a rule", func(t *testing.T) {
+    rules := []*OutdatedRule{nil, {Years: 2}, {Topics: []string{"go"}}}
+
+    for _, rule := range rules {
+      r := &outdatedRepositoryMock{}
+      s := NewArticlesService(r, cacherImpl, cacherImpl)
+      s.SetOutdatedRule(rule)
+      assert.NoError(t, s.MarkStale(ctx))
+      assert.False(t, r.called)
+    }
+  })
+
+  t.Run("success", func(t *testing.T) {
+    r := &outdatedRepositoryMock{marked: 3}
+    s := NewArticlesService(r, cacherImpl, cacherImpl)
+    s.SetOutdatedRule(&OutdatedRule{Years: 2, Topics: []string{"go", "sql"}})
+    assert.NoError(t, s.MarkStale(ctx))
+    assert.Equal(t, []string{"go", "sql"}, r.topics)
+    assert.Contains(t, r.reason, "more than 2 years ago")
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &outdatedRepositoryMock{errors: unexpected}
+    s := NewArticlesService(r, cacherImpl, cacherImpl)
+    s.SetOutdatedRule(&OutdatedRule{Years: 1, Topics: []string{"go"}})
+    assert.ErrorIs(t, s.MarkStale(ctx), unexpected)
+  })
+}
+
 func (mock *archiveRepositoryMockAPIForArticles) Amend(_ context.Context, articleID string) error {
   mock.called = true
 
@@ -393,3 +637,97 @@ func TestArticlesService_RemoveTag(t *testing.T) {
     assert.ErrorIs(t, err, unexpected)
   })
 }
+
+func (mock *archiveRepositoryMockAPIForArticles) AddSyndication(_ context.Context, articleID, copyURL string, name *string) error {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], articleID)
+    require.Equal(mock.t, mock.arguments[2], copyURL)
+    require.Equal(mock.t, mock.arguments[3], name)
+  }
+
+  return mock.errors
+}
+
+func TestArticlesService_AddSyndication(t *testing.T) {
+  ctx := context.TODO()
+  articleUUID := uuid.NewString()
+  copyURL := "https://dev.to/fontseca/article"
+
+  t.Run("success", func(t *testing.T) {
+    name := "DEV Community"
+    r := &archiveRepositoryMockAPIForArticles{t: t, arguments: []any{ctx, articleUUID, copyURL, &name}}
+    assert.NoError(t, NewArticlesService(r, cacherImpl, cacherImpl).AddSyndication(ctx, articleUUID, " "+copyURL+" ", "  DEV   Community "))
+  })
+
+  t.Run("success without name", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{t: t, arguments: []any{ctx, articleUUID, copyURL, (*string)(nil)}}
+    assert.NoError(t, NewArticlesService(r, cacherImpl, cacherImpl).AddSyndication(ctx, articleUUID, copyURL, ""))
+  })
+
+  t.Run("missing url", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).AddSyndication(ctx, articleUUID, "  ", ""))
+    assert.False(t, r.called)
+  })
+
+  t.Run("wrong url", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).AddSyndication(ctx, articleUUID, "not a url", ""))
+    assert.False(t, r.called)
+  })
+
+  t.Run("script url", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).AddSyndication(ctx, articleUUID, "javascript:alert(document.cookie)", ""))
+    assert.False(t, r.called)
+  })
+
+  t.Run("name too long", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).AddSyndication(ctx, articleUUID, copyURL, strings.Repeat("x", 65)))
+    assert.False(t, r.called)
+  })
+
+  t.Run("wrong article uuid", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).AddSyndication(ctx, "e4d06ba7-f086-47dc-9f5e", copyURL, ""))
+    assert.False(t, r.called)
+  })
+}
+
+func (mock *archiveRepositoryMockAPIForArticles) RemoveSyndication(_ context.Context, articleID, copyURL string) error {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], articleID)
+    require.Equal(mock.t, mock.arguments[2], copyURL)
+  }
+
+  return mock.errors
+}
+
+func TestArticlesService_RemoveSyndication(t *testing.T) {
+  ctx := context.TODO()
+  articleUUID := uuid.NewString()
+  copyURL := "https://dev.to/fontseca/article"
+
+  t.Run("success", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{t: t, arguments: []any{ctx, articleUUID, copyURL}}
+    assert.NoError(t, NewArticlesService(r, cacherImpl, cacherImpl).RemoveSyndication(ctx, articleUUID, copyURL))
+  })
+
+  t.Run("missing url", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForArticles{}
+    assert.Error(t, NewArticlesService(r, cacherImpl, cacherImpl).RemoveSyndication(ctx, articleUUID, ""))
+    assert.False(t, r.called)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &archiveRepositoryMockAPIForArticles{errors: unexpected}
+    err := NewArticlesService(r, cacherImpl, cacherImpl).RemoveSyndication(ctx, articleUUID, copyURL)
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
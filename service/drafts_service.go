@@ -3,34 +3,81 @@ package service
 import (
   "context"
   "errors"
+  "fmt"
   "fontseca.dev/model"
   "fontseca.dev/problem"
   "fontseca.dev/transfer"
   "github.com/google/uuid"
   "log/slog"
+  "net/http"
+  "slices"
   "strings"
+  "time"
 )
 
 type archiveRepositoryAPIForDrafts interface {
   Draft(ctx context.Context, creation *transfer.ArticleCreation) (draft string, err error)
   Publish(ctx context.Context, draftID string) error
-  List(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (drafts []*transfer.Article, err error)
+  IsPublished(ctx context.Context, draftID string) (published bool, err error)
+  List(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (page *transfer.Page[*transfer.Article], err error)
   GetByLink(ctx context.Context, link string) (article *model.Article, err error)
   GetByID(ctx context.Context, draftID string, isDraft bool) (draft *model.Article, err error)
   AddTag(ctx context.Context, draftID, tagID string, isDraft ...bool) error
   RemoveTag(ctx context.Context, draftID, tagID string, isDraft ...bool) error
-  Share(ctx context.Context, draftID string) (link string, err error)
+  Share(ctx context.Context, draftID string, creation *transfer.ShareableLinkCreation) (link *model.ShareableLink, err error)
+  Links(ctx context.Context, draftID string) (links []*model.ShareableLink, err error)
+  LinkPassword(ctx context.Context, link string) (hash string, err error)
+  RevokeLink(ctx context.Context, linkID string) error
+  ExtendLink(ctx context.Context, linkID string, days int) error
   Discard(ctx context.Context, draftID string) error
   Revise(ctx context.Context, draftID string, revision *transfer.ArticleRevision) error
+  WorkflowState(ctx context.Context, draftID string) (state string, err error)
+  Transition(ctx context.Context, draftID string, transition *model.WorkflowTransition) error
+  History(ctx context.Context, draftID string) (transitions []*model.WorkflowTransition, err error)
 }
 
 // DraftsService is a high level provider for article drafts.
 type DraftsService struct {
-  r archiveRepositoryAPIForDrafts
+  r         archiveRepositoryAPIForDrafts
+  federator federator
+  renderer  renderer
+  workflow  *Workflow
+  feedback  reanchorer
+  templates templater
 }
 
 func NewDraftsService(r archiveRepositoryAPIForDrafts) *DraftsService {
-  return &DraftsService{r}
+  return &DraftsService{r: r, workflow: DefaultWorkflow()}
+}
+
+// SetFederator sets the federator that shares every newly published
+// article with the fediverse. A nil federator disables it.
+func (s *DraftsService) SetFederator(f federator) {
+  s.federator = f
+}
+
+// SetRenderer sets the renderer that renders every draft when it is
+// revised or published. A nil renderer disables it.
+func (s *DraftsService) SetRenderer(r renderer) {
+  s.renderer = r
+}
+
+// SetFeedback sets the reanchorer that moves the feedback on every draft
+// when it is revised. A nil reanchorer disables it.
+func (s *DraftsService) SetFeedback(f reanchorer) {
+  s.feedback = f
+}
+
+// SetWorkflow sets the editorial workflow drafts and patches go through
+// before they are published. A nil workflow disables it.
+func (s *DraftsService) SetWorkflow(w *Workflow) {
+  s.workflow = w
+}
+
+// SetTemplates sets the provider of the templates drafts can be started
+// with. A nil provider disables them.
+func (s *DraftsService) SetTemplates(t templater) {
+  s.templates = t
 }
 
 // Draft starts the creation process of an article. It returns the
@@ -38,6 +85,9 @@ func NewDraftsService(r archiveRepositoryAPIForDrafts) *DraftsService {
 //
 // To draft an article, only its title is required, other fields
 // are completely optional and can be added in an eventual revision.
+//
+// If creation names a template, the draft is seeded with it and the
+// default tags of the template are attached to it.
 func (s *DraftsService) Draft(ctx context.Context, creation *transfer.ArticleCreation) (insertedUUID uuid.UUID, err error) {
   if nil == creation {
     err = errors.New("nil value for parameter: creation")
@@ -45,6 +95,27 @@ func (s *DraftsService) Draft(ctx context.Context, creation *transfer.ArticleCre
     return uuid.Nil, err
   }
 
+  var template *model.Template
+
+  if creation.Template = strings.TrimSpace(creation.Template); "" != creation.Template {
+    if nil == s.templates {
+      var p problem.Problem
+      p.Type(problem.TypeActionRefused)
+      p.Status(http.StatusUnprocessableEntity)
+      p.Title("Templates unavailable.")
+      p.Detail("Drafts can't be started with a template at the moment. Please start it without one.")
+      p.With("template_id", creation.Template)
+      return uuid.Nil, &p
+    }
+
+    template, err = s.templates.Get(ctx, creation.Template)
+    if nil != err {
+      return uuid.Nil, err
+    }
+
+    seedFromTemplate(creation, template, time.Now())
+  }
+
   creation.Title = strings.TrimSpace(creation.Title)
   creation.Summary = strings.TrimSpace(creation.Summary)
   creation.Content = strings.TrimSpace(creation.Content)
@@ -61,6 +132,8 @@ func (s *DraftsService) Draft(ctx context.Context, creation *transfer.ArticleCre
   }
 
   switch {
+  case "" == creation.Title:
+    return uuid.Nil, problem.NewValidation([3]string{"title", "required", ""})
   case 256 < len(creation.Title):
     return uuid.Nil, problem.NewValidation([3]string{"title", "max", "256"})
   case 0 != len(creation.Content) && 3145728 < len(creation.Content):
@@ -100,18 +173,109 @@ func (s *DraftsService) Draft(ctx context.Context, creation *transfer.ArticleCre
     return uuid.Nil, err
   }
 
+  if nil != template {
+    for _, tag := range template.Tags {
+      // The draft already exists, so failing to attach a tag should not
+      // make the client start it again.
+      if err = s.r.AddTag(ctx, id, tag, true); nil != err {
+        slog.Error("could not attach template tag",
+          slog.String("tag_id", tag),
+          slog.String("template_uuid", template.UUID.String()),
+          slog.String("draft_uuid", id),
+          slog.String("error", err.Error()),
+        )
+      }
+    }
+  }
+
   return uuid.Parse(id)
 }
 
 // Publish makes a draft publicly available.
 //
-// Invoking Publish on an already published article has no effect.
-func (s *DraftsService) Publish(ctx context.Context, draftUUID string) error {
+// If there is a workflow, the draft must be approved first; not even
+// force skips this.
+//
+// Unless force is true, the draft is linted first and it is not published
+// if any blocking rule fails; the problem lists the violations of those
+// rules.
+//
+// Invoking Publish on an already published article returns a conflict,
+// before the workflow or the linting are checked.
+func (s *DraftsService) Publish(ctx context.Context, draftUUID string, force bool) error {
   if err := validateUUID(&draftUUID); nil != err {
     return err
   }
 
-  return s.r.Publish(ctx, draftUUID)
+  published, err := s.r.IsPublished(ctx, draftUUID)
+  if nil != err {
+    return err
+  }
+
+  if published {
+    var p problem.Problem
+    p.Type(problem.TypeActionAlreadyCompleted)
+    p.Status(http.StatusConflict)
+    p.Title("Article already published.")
+    p.Detail("Cannot publish an article that is already published.")
+    p.With("article_uuid", draftUUID)
+    return &p
+  }
+
+  if err := checkApproved(ctx, s.workflow, s.r, draftUUID, "Could not publish draft."); nil != err {
+    return err
+  }
+
+  if !force {
+    violations, err := s.Lint(ctx, draftUUID)
+    if nil != err {
+      return err
+    }
+
+    blocking := make([]*transfer.LintViolation, 0)
+
+    for _, violation := range violations {
+      if lintError == violation.Severity {
+        blocking = append(blocking, violation)
+      }
+    }
+
+    if 0 < len(blocking) {
+      var p problem.Problem
+      p.Type(problem.TypeActionRefused)
+      p.Status(http.StatusUnprocessableEntity)
+      p.Title("Could not publish draft.")
+      p.Detail("The draft breaks blocking lint rules. Fix the violations, or publish it with 'force' set to true.")
+      p.With("draft_uuid", draftUUID)
+      p.With("violations", blocking)
+      return &p
+    }
+  }
+
+  if err := s.r.Publish(ctx, draftUUID); nil != err {
+    return err
+  }
+
+  render(ctx, s.renderer, draftUUID)
+
+  if nil != s.federator {
+    s.federator.Federate(ctx, draftUUID, "Create")
+  }
+
+  return nil
+}
+
+// Lint checks an article draft against the rules drafts are checked
+// against before they are published, like having a cover or images with
+// alternative text. Violations of blocking rules have the severity
+// 'error' and the rest, 'warning'.
+func (s *DraftsService) Lint(ctx context.Context, draftUUID string) (violations []*transfer.LintViolation, err error) {
+  draft, err := s.Get(ctx, draftUUID)
+  if nil != err {
+    return nil, err
+  }
+
+  return lintArticle(draft), nil
 }
 
 // List retrieves all the ongoing articles drafts.
@@ -120,13 +284,42 @@ func (s *DraftsService) Publish(ctx context.Context, draftUUID string) error {
 // function over draft articles, so it attempts to find and
 // amass every article whose title contains any of the keywords
 // (if more than one) in filter.Search.
-func (s *DraftsService) List(ctx context.Context, filter *transfer.ArticleFilter) (drafts []*transfer.Article, err error) {
+func (s *DraftsService) List(ctx context.Context, filter *transfer.ArticleFilter) (page *transfer.Page[*transfer.Article], err error) {
+  if err = validateArticleFilter(filter); nil != err {
+    return nil, err
+  }
+
   return s.r.List(ctx, filter, false, true)
 }
 
-// GetByLink retrieves a draft by its shareable link.
-func (s *DraftsService) GetByLink(ctx context.Context, link string) (article *model.Article, err error) {
-  return s.r.GetByLink(ctx, link)
+// GetByLink retrieves a draft, or a patch, by its shareable link. If
+// the link is protected by a password, token must be the one Unlock
+// retrieved for it.
+func (s *DraftsService) GetByLink(ctx context.Context, link, token string) (article *model.Article, err error) {
+  hash, err := s.r.LinkPassword(ctx, link)
+  if nil != err {
+    return nil, err
+  }
+
+  if err = checkShareableLinkToken(link, hash, token); nil != err {
+    return nil, err
+  }
+
+  article, err = s.r.GetByLink(ctx, link)
+  applyRendering(s.renderer, article)
+  return article, err
+}
+
+// Unlock checks the password of a shareable link and retrieves the
+// token GetByLink needs to retrieve its draft. The token is empty if
+// the link is not protected.
+func (s *DraftsService) Unlock(ctx context.Context, link, password string) (token string, err error) {
+  hash, err := s.r.LinkPassword(ctx, link)
+  if nil != err {
+    return "", err
+  }
+
+  return unlockShareableLink(link, hash, password)
 }
 
 // Get retrieves one article draft by its UUID.
@@ -135,7 +328,9 @@ func (s *DraftsService) Get(ctx context.Context, draftUUID string) (draft *model
     return nil, err
   }
 
-  return s.r.GetByID(ctx, draftUUID, true)
+  draft, err = s.r.GetByID(ctx, draftUUID, true)
+  applyRendering(s.renderer, draft)
+  return draft, err
 }
 
 // AddTag adds a tag to the article draft. If the tag already
@@ -163,19 +358,56 @@ func (s *DraftsService) RemoveTag(ctx context.Context, draftUUID, tagID string)
 // with that link can see the progress and provide feedback.
 //
 // A shareable link does not make an article public. This link will
-// eventually expire after a certain amount of time.
-func (s *DraftsService) Share(ctx context.Context, draftUUID string) (link string, err error) {
+// eventually expire after a certain amount of time. A draft can have
+// one link per label, each with its own expiry and password.
+func (s *DraftsService) Share(ctx context.Context, draftUUID string, creation *transfer.ShareableLinkCreation) (link *model.ShareableLink, err error) {
+  if nil == creation {
+    err = errors.New("nil value for parameter: creation")
+    slog.Error(err.Error())
+    return nil, err
+  }
+
   if err = validateUUID(&draftUUID); nil != err {
-    return "about:blank", err
+    return nil, err
   }
 
-  link, err = s.r.Share(ctx, draftUUID)
+  if err = prepareShareableLink(creation); nil != err {
+    return nil, err
+  }
 
-  if nil != err {
-    return "about:blank", err
+  return s.r.Share(ctx, draftUUID, creation)
+}
+
+// Links retrieves the valid shareable links of an article draft or
+// patch.
+func (s *DraftsService) Links(ctx context.Context, draftUUID string) (links []*model.ShareableLink, err error) {
+  if err = validateUUID(&draftUUID); nil != err {
+    return nil, err
+  }
+
+  return s.r.Links(ctx, draftUUID)
+}
+
+// RevokeLink removes a shareable link before it expires.
+func (s *DraftsService) RevokeLink(ctx context.Context, linkUUID string) error {
+  if err := validateUUID(&linkUUID); nil != err {
+    return err
+  }
+
+  return s.r.RevokeLink(ctx, linkUUID)
+}
+
+// ExtendLink postpones the expiration of a shareable link by days.
+func (s *DraftsService) ExtendLink(ctx context.Context, linkUUID string, days int) error {
+  if err := validateUUID(&linkUUID); nil != err {
+    return err
   }
 
-  return link, nil
+  if err := validateExpiryExtension(days); nil != err {
+    return err
+  }
+
+  return s.r.ExtendLink(ctx, linkUUID, days)
 }
 
 // Discard completely drops an article draft.
@@ -207,11 +439,16 @@ func (s *DraftsService) Revise(ctx context.Context, draftUUID string, revision *
     sanitizeTextWordIntersections(&revision.Title)
   }
 
-  if "" != revision.CoverURL {
+  if "" != revision.CoverURL || "" != revision.CanonicalURL {
     err := sanitizeURL(&revision.CoverURL)
     if nil != err {
       return err
     }
+
+    err = sanitizeWebURL(&revision.CanonicalURL)
+    if nil != err {
+      return err
+    }
   }
 
   switch {
@@ -225,6 +462,10 @@ func (s *DraftsService) Revise(ctx context.Context, draftUUID string, revision *
     return problem.NewValidation([3]string{"summary", "min", "120"})
   case 0 != len(revision.CoverCap) && 256 < len(revision.CoverCap):
     return problem.NewValidation([3]string{"cover_caption", "max", "256"})
+  case 2048 < len(revision.CanonicalURL):
+    return problem.NewValidation([3]string{"canonical_url", "max", "2048"})
+  case 0 > revision.Version:
+    return problem.NewValidation([3]string{"version", "min", "0"})
   }
 
   if "" != revision.Title {
@@ -288,5 +529,99 @@ func (s *DraftsService) Revise(ctx context.Context, draftUUID string, revision *
     revision.ReadTime = computePostReadingTimeInMinutes(r)
   }
 
-  return s.r.Revise(ctx, draftUUID, revision)
+  if err := s.r.Revise(ctx, draftUUID, revision); nil != err {
+    return err
+  }
+
+  render(ctx, s.renderer, draftUUID)
+  reanchor(ctx, s.feedback, draftUUID)
+
+  return nil
+}
+
+// Transition moves a draft or a patch to another state of the editorial
+// workflow, recording who moved it and why. Since a patch shares its UUID
+// with the article it patches, draftUUID can be either.
+func (s *DraftsService) Transition(ctx context.Context, draftUUID string, transition *transfer.WorkflowTransition) error {
+  if nil == transition {
+    err := errors.New("nil value for parameter: transition")
+    slog.Error(err.Error())
+    return err
+  }
+
+  if err := validateUUID(&draftUUID); nil != err {
+    return err
+  }
+
+  if nil == s.workflow {
+    var p problem.Problem
+    p.Type(problem.TypeActionRefused)
+    p.Status(http.StatusConflict)
+    p.Title("Could not transition draft.")
+    p.Detail("There is no editorial workflow to move drafts through.")
+    return &p
+  }
+
+  transition.State = strings.TrimSpace(transition.State)
+  transition.Actor = strings.TrimSpace(transition.Actor)
+  transition.Comment = strings.TrimSpace(transition.Comment)
+
+  switch {
+  case "" == transition.Actor:
+    return problem.NewValidation([3]string{"actor", "required", ""})
+  case 64 < len(transition.Actor):
+    return problem.NewValidation([3]string{"actor", "max", "64"})
+  case 512 < len(transition.Comment):
+    return problem.NewValidation([3]string{"comment", "max", "512"})
+  case !slices.Contains(s.workflow.States(), transition.State):
+    return problem.NewValidation([3]string{"state", "oneof", strings.Join(s.workflow.States(), " ")})
+  }
+
+  from, err := s.r.WorkflowState(ctx, draftUUID)
+  if nil != err {
+    return err
+  }
+
+  if !s.workflow.allows(from, transition.State) {
+    var p problem.Problem
+    p.Type(problem.TypeActionRefused)
+    p.Status(http.StatusConflict)
+    p.Title("Could not transition draft.")
+    p.Detail(fmt.Sprintf("A draft cannot move from '%s' to '%s'.", from, transition.State))
+    p.With("draft_uuid", draftUUID)
+    p.With("state", from)
+    return &p
+  }
+
+  record := &model.WorkflowTransition{
+    From:  from,
+    To:    transition.State,
+    Actor: transition.Actor,
+  }
+
+  if "" != transition.Comment {
+    record.Comment = &transition.Comment
+  }
+
+  return s.r.Transition(ctx, draftUUID, record)
+}
+
+// History retrieves the state of a draft or a patch in the editorial
+// workflow and the transitions that led to it.
+func (s *DraftsService) History(ctx context.Context, draftUUID string) (history *model.WorkflowHistory, err error) {
+  if err = validateUUID(&draftUUID); nil != err {
+    return nil, err
+  }
+
+  state, err := s.r.WorkflowState(ctx, draftUUID)
+  if nil != err {
+    return nil, err
+  }
+
+  transitions, err := s.r.History(ctx, draftUUID)
+  if nil != err {
+    return nil, err
+  }
+
+  return &model.WorkflowHistory{State: state, Transitions: transitions}, nil
 }
@@ -4,12 +4,17 @@ import (
   "context"
   "errors"
   "fontseca.dev/model"
+  "fontseca.dev/problem"
   "fontseca.dev/transfer"
   "github.com/google/uuid"
   "github.com/stretchr/testify/assert"
   "github.com/stretchr/testify/require"
+  "golang.org/x/crypto/bcrypt"
+  "net/http"
+  "net/http/httptest"
   "strings"
   "testing"
+  "time"
 )
 
 type archiveRepositoryMockAPIForDrafts struct {
@@ -19,6 +24,18 @@ type archiveRepositoryMockAPIForDrafts struct {
   arguments []any
   errors    error
   called    bool
+  state     string // of the workflow; an empty one is approved
+  password  string // the hash of the password of every shareable link
+  tagged    []string
+  published bool
+}
+
+func (mock *archiveRepositoryMockAPIForDrafts) WorkflowState(context.Context, string) (string, error) {
+  if "" == mock.state {
+    return WorkflowApprovedState, nil
+  }
+
+  return mock.state, nil
 }
 
 func (mock *archiveRepositoryMockAPIForDrafts) Draft(_ context.Context, creation *transfer.ArticleCreation) (draft string, err error) {
@@ -70,6 +87,103 @@ func TestDraftsService_Draft(t *testing.T) {
     assert.ErrorIs(t, err, unexpected)
     assert.Equal(t, uuid.Nil, insertedID)
   })
+
+  t.Run("blank title", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{}
+
+    _, err := NewDraftsService(r).Draft(ctx, &transfer.ArticleCreation{Title: " \n\t "})
+
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+}
+
+type templaterMock struct {
+  template *model.Template
+  errors   error
+}
+
+func (mock *templaterMock) Get(context.Context, string) (*model.Template, error) {
+  return mock.template, mock.errors
+}
+
+func TestDraftsService_Draft_withTemplate(t *testing.T) {
+  id := uuid.New()
+  ctx := context.TODO()
+  summary, content, topic := "Template summary.", "## Timeline", "reliability"
+  template := &model.Template{
+    UUID:         uuid.New(),
+    Name:         "Post-mortem",
+    TitlePattern: "Post-mortem: {title} ({date})",
+    Summary:      &summary,
+    Content:      &content,
+    TopicID:      &topic,
+    Tags:         []string{"incident", "postgres"},
+  }
+
+  t.Run("success", func(t *testing.T) {
+    creation := &transfer.ArticleCreation{Title: " Database outage ", Template: template.UUID.String()}
+    r := &archiveRepositoryMockAPIForDrafts{returns: []any{id.String()}}
+    s := NewDraftsService(r)
+    s.SetTemplates(&templaterMock{template: template})
+
+    insertedID, err := s.Draft(ctx, creation)
+
+    require.NoError(t, err)
+    assert.Equal(t, id, insertedID)
+    assert.Equal(t, "Post-mortem: Database outage ("+time.Now().Format(time.DateOnly)+")", creation.Title)
+    assert.Equal(t, summary, creation.Summary)
+    assert.Equal(t, content, creation.Content)
+    assert.Equal(t, topic, creation.Topic)
+    assert.Equal(t, template.Tags, r.tagged)
+  })
+
+  t.Run("given fields take precedence", func(t *testing.T) {
+    creation := &transfer.ArticleCreation{Title: "Database outage", Summary: "Given summary.", Template: template.UUID.String()}
+    r := &archiveRepositoryMockAPIForDrafts{returns: []any{id.String()}}
+    s := NewDraftsService(r)
+    s.SetTemplates(&templaterMock{template: template})
+
+    _, err := s.Draft(ctx, creation)
+
+    require.NoError(t, err)
+    assert.Equal(t, "Given summary.", creation.Summary)
+    assert.Equal(t, content, creation.Content)
+  })
+
+  t.Run("template not found", func(t *testing.T) {
+    expected := problem.NewNotFound(template.UUID.String(), "template")
+    r := &archiveRepositoryMockAPIForDrafts{}
+    s := NewDraftsService(r)
+    s.SetTemplates(&templaterMock{errors: expected})
+
+    _, err := s.Draft(ctx, &transfer.ArticleCreation{Title: "Database outage", Template: template.UUID.String()})
+
+    assert.ErrorIs(t, err, expected)
+    assert.False(t, r.called)
+  })
+
+  t.Run("without templates", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{}
+
+    _, err := NewDraftsService(r).Draft(ctx, &transfer.ArticleCreation{Title: "Database outage", Template: template.UUID.String()})
+
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+
+  t.Run("title from the pattern alone", func(t *testing.T) {
+    creation := &transfer.ArticleCreation{Template: template.UUID.String()}
+    r := &archiveRepositoryMockAPIForDrafts{returns: []any{id.String()}}
+    s := NewDraftsService(r)
+    s.SetTemplates(&templaterMock{template: &model.Template{TitlePattern: "Release notes {date}"}})
+
+    _, err := s.Draft(ctx, creation)
+
+    require.NoError(t, err)
+    assert.Equal(t, "Release notes "+time.Now().Format(time.DateOnly), creation.Title)
+    assert.Empty(t, r.tagged)
+  })
 }
 
 func (mock *archiveRepositoryMockAPIForDrafts) Publish(_ context.Context, draftID string) error {
@@ -82,32 +196,157 @@ func (mock *archiveRepositoryMockAPIForDrafts) Publish(_ context.Context, draftI
   return mock.errors
 }
 
+func (mock *archiveRepositoryMockAPIForDrafts) IsPublished(context.Context, string) (bool, error) {
+  return mock.published, nil
+}
+
+type federatorMock struct {
+  federated []string
+}
+
+func (mock *federatorMock) Federate(_ context.Context, articleID, activityType string) {
+  mock.federated = append(mock.federated, articleID+" "+activityType)
+}
+
+type rendererMock struct {
+  renderer
+  rendered []string
+}
+
+func (mock *rendererMock) Render(_ context.Context, articleID string) error {
+  mock.rendered = append(mock.rendered, articleID)
+  return nil
+}
+
+type reanchorerMock struct {
+  reanchored []string
+}
+
+func (mock *reanchorerMock) Reanchor(_ context.Context, draftID string) error {
+  mock.reanchored = append(mock.reanchored, draftID)
+  return nil
+}
+
 func TestDraftsService_Publish(t *testing.T) {
   ctx := context.TODO()
   id := uuid.New().String()
 
   t.Run("success", func(t *testing.T) {
     r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, id}}
-    assert.NoError(t, NewDraftsService(r).Publish(ctx, id))
+    assert.NoError(t, NewDraftsService(r).Publish(ctx, id, true))
+  })
+
+  t.Run("federates the article", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, id}}
+    f := &federatorMock{}
+    s := NewDraftsService(r)
+    s.SetFederator(f)
+    assert.NoError(t, s.Publish(ctx, id, true))
+    assert.Equal(t, []string{id + " Create"}, f.federated)
+  })
+
+  t.Run("renders the article", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, id}}
+    m := &rendererMock{}
+    s := NewDraftsService(r)
+    s.SetRenderer(m)
+    assert.NoError(t, s.Publish(ctx, id, true))
+    assert.Equal(t, []string{id}, m.rendered)
+  })
+
+  t.Run("refuses a draft that is not approved even if forced", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{state: "in_review"}
+
+    err := NewDraftsService(r).Publish(ctx, id, true)
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), `"state":"in_review"`)
+    assert.False(t, r.called)
+  })
+
+  t.Run("refuses an article that is already published", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{published: true}
+    f := &federatorMock{}
+    s := NewDraftsService(r)
+    s.SetFederator(f)
+
+    err := s.Publish(ctx, id, false)
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), `"type":"action_already_completed"`)
+    assert.Contains(t, recorder.Body.String(), `"article_uuid":"`+id+`"`)
+    assert.False(t, r.called)
+    assert.Empty(t, f.federated)
+  })
+
+  t.Run("publishes any draft without a workflow", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, id}, state: "writing"}
+    s := NewDraftsService(r)
+    s.SetWorkflow(nil)
+    assert.NoError(t, s.Publish(ctx, id, true))
+    assert.True(t, r.called)
+  })
+
+  t.Run("refuses a draft that breaks blocking lint rules", func(t *testing.T) {
+    draft := lintableArticle()
+    draft.Topic = nil
+
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, id, true}, returns: []any{draft}}
+    f := &federatorMock{}
+    s := NewDraftsService(r)
+    s.SetFederator(f)
+
+    err := s.Publish(ctx, id, false)
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), `"rule":"topic"`)
+    assert.Empty(t, f.federated)
+  })
+
+  t.Run("publishes a draft with only lint warnings", func(t *testing.T) {
+    draft := lintableArticle()
+    draft.CoverCap = nil
+
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, id, true}, returns: []any{draft}}
+    assert.NoError(t, NewDraftsService(r).Publish(ctx, id, false))
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
 
     r := &archiveRepositoryMockAPIForDrafts{errors: unexpected}
-    assert.ErrorIs(t, NewDraftsService(r).Publish(ctx, id), unexpected)
+    f := &federatorMock{}
+    s := NewDraftsService(r)
+    s.SetFederator(f)
+    assert.ErrorIs(t, s.Publish(ctx, id, true), unexpected)
+    assert.Empty(t, f.federated)
   })
 
   t.Run("wrong uuid", func(t *testing.T) {
     id = "e4d06ba7-f086-47dc-9f5e"
 
     r := &archiveRepositoryMockAPIForDrafts{}
-    assert.Error(t, NewDraftsService(r).Publish(ctx, id))
+    assert.Error(t, NewDraftsService(r).Publish(ctx, id, true))
     assert.False(t, r.called)
   })
 }
 
-func (mock *archiveRepositoryMockAPIForDrafts) List(_ context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (drafts []*transfer.Article, err error) {
+func (mock *archiveRepositoryMockAPIForDrafts) List(_ context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (page *transfer.Page[*transfer.Article], err error) {
   mock.called = true
 
   if nil != mock.t {
@@ -116,7 +355,7 @@ func (mock *archiveRepositoryMockAPIForDrafts) List(_ context.Context, filter *t
     require.Equal(mock.t, mock.arguments[3], draftsOnly)
   }
 
-  return mock.returns[0].([]*transfer.Article), mock.errors
+  return mock.returns[0].(*transfer.Page[*transfer.Article]), mock.errors
 }
 
 func TestDraftsService_Get(t *testing.T) {
@@ -124,7 +363,7 @@ func TestDraftsService_Get(t *testing.T) {
   filter := &transfer.ArticleFilter{}
 
   t.Run("success", func(t *testing.T) {
-    expectedDrafts := []*transfer.Article{{}, {}, {}}
+    expectedDrafts := &transfer.Page[*transfer.Article]{Items: []*transfer.Article{{}, {}, {}}}
     r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, filter, false, true}, returns: []any{expectedDrafts}}
     drafts, err := NewDraftsService(r).List(ctx, filter)
     assert.Equal(t, expectedDrafts, drafts)
@@ -133,7 +372,7 @@ func TestDraftsService_Get(t *testing.T) {
 
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
-    r := &archiveRepositoryMockAPIForDrafts{returns: []any{([]*transfer.Article)(nil)}, errors: unexpected}
+    r := &archiveRepositoryMockAPIForDrafts{returns: []any{(*transfer.Page[*transfer.Article])(nil)}, errors: unexpected}
     _, err := NewDraftsService(r).List(ctx, filter)
     assert.ErrorIs(t, err, unexpected)
   })
@@ -182,6 +421,7 @@ func TestDraftsService_GetByID(t *testing.T) {
 
 func (mock *archiveRepositoryMockAPIForDrafts) AddTag(_ context.Context, draftID, tagID string, isDraft ...bool) error {
   mock.called = true
+  mock.tagged = append(mock.tagged, tagID)
 
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], draftID)
@@ -258,14 +498,16 @@ func TestDraftsService_RemoveTag(t *testing.T) {
   })
 }
 
-func (mock *archiveRepositoryMockAPIForDrafts) Share(_ context.Context, draftID string) (link string, err error) {
+func (mock *archiveRepositoryMockAPIForDrafts) Share(_ context.Context, draftID string, creation *transfer.ShareableLinkCreation) (link *model.ShareableLink, err error) {
   mock.called = true
+  mock.password = creation.Password
 
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], draftID)
+    require.Equal(mock.t, mock.arguments[2], creation)
   }
 
-  return mock.returns[0].(string), mock.errors
+  return mock.returns[0].(*model.ShareableLink), mock.errors
 }
 
 func TestDraftsService_Share(t *testing.T) {
@@ -273,40 +515,259 @@ func TestDraftsService_Share(t *testing.T) {
   draftUUID := uuid.NewString()
 
   t.Run("success", func(t *testing.T) {
-    expectedLink := "link-to-resource"
+    expectedLink := &model.ShareableLink{Label: "Jane", Link: "link-to-resource"}
+    expectedCreation := &transfer.ShareableLinkCreation{Label: "Jane", ExpiresIn: 30}
 
-    r := &archiveRepositoryMockAPIForDrafts{arguments: []any{ctx, draftUUID}, returns: []any{expectedLink}}
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, draftUUID, expectedCreation}, returns: []any{expectedLink}}
 
-    link, err := NewDraftsService(r).Share(ctx, draftUUID)
+    link, err := NewDraftsService(r).Share(ctx, draftUUID, &transfer.ShareableLinkCreation{Label: " Jane ", ExpiresIn: 30})
 
     assert.Equal(t, expectedLink, link)
     assert.NoError(t, err)
   })
 
+  t.Run("hashes the password", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{returns: []any{&model.ShareableLink{}}}
+    creation := &transfer.ShareableLinkCreation{Password: "correct horse"}
+
+    _, err := NewDraftsService(r).Share(ctx, draftUUID, creation)
+
+    require.NoError(t, err)
+    assert.Equal(t, ShareableLinkDefaultLabel, creation.Label)
+    assert.Equal(t, ShareableLinkDefaultExpiry, creation.ExpiresIn)
+    assert.NotEqual(t, "correct horse", creation.Password)
+    assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(creation.Password), []byte("correct horse")))
+  })
+
+  t.Run("shares again with a new password", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{returns: []any{&model.ShareableLink{}}}
+    s := NewDraftsService(r)
+
+    _, err := s.Share(ctx, draftUUID, &transfer.ShareableLinkCreation{Label: "Jane", Password: "correct horse"})
+    require.NoError(t, err)
+
+    old, err := s.Unlock(ctx, "/archive/sharing/6d7f", "correct horse")
+    require.NoError(t, err)
+
+    _, err = s.Share(ctx, draftUUID, &transfer.ShareableLinkCreation{Label: "Jane", Password: "battery staple"})
+    require.NoError(t, err)
+
+    _, err = s.Unlock(ctx, "/archive/sharing/6d7f", "correct horse")
+    assert.Error(t, err)
+
+    r.returns = []any{&model.Article{}}
+    _, err = s.GetByLink(ctx, "/archive/sharing/6d7f", old)
+    assert.Error(t, err)
+
+    token, err := s.Unlock(ctx, "/archive/sharing/6d7f", "battery staple")
+    require.NoError(t, err)
+    assert.NotEqual(t, old, token)
+  })
+
   t.Run("wrong draft uuid", func(t *testing.T) {
-    draftUUID = "e4d06ba7-f086-47dc-9f5e"
+    r := &archiveRepositoryMockAPIForDrafts{}
+
+    link, err := NewDraftsService(r).Share(ctx, "e4d06ba7-f086-47dc-9f5e", &transfer.ShareableLinkCreation{})
 
+    require.False(t, r.called)
+    assert.Error(t, err)
+    assert.Nil(t, link)
+  })
+
+  t.Run("too short a password", func(t *testing.T) {
     r := &archiveRepositoryMockAPIForDrafts{}
 
-    link, err := NewDraftsService(r).Share(ctx, draftUUID)
+    _, err := NewDraftsService(r).Share(ctx, draftUUID, &transfer.ShareableLinkCreation{Password: "1234"})
 
     require.False(t, r.called)
     assert.Error(t, err)
-    assert.Equal(t, "about:blank", link)
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
 
-    r := &archiveRepositoryMockAPIForDrafts{returns: []any{""}, errors: unexpected}
+    r := &archiveRepositoryMockAPIForDrafts{returns: []any{(*model.ShareableLink)(nil)}, errors: unexpected}
 
-    link, err := NewDraftsService(r).Share(ctx, uuid.NewString())
+    link, err := NewDraftsService(r).Share(ctx, uuid.NewString(), &transfer.ShareableLinkCreation{})
 
-    assert.Equal(t, "about:blank", link)
+    assert.Nil(t, link)
     assert.ErrorIs(t, err, unexpected)
   })
 }
 
+func (mock *archiveRepositoryMockAPIForDrafts) Links(_ context.Context, draftID string) ([]*model.ShareableLink, error) {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], draftID)
+  }
+
+  return mock.returns[0].([]*model.ShareableLink), mock.errors
+}
+
+func TestDraftsService_Links(t *testing.T) {
+  ctx := context.TODO()
+  draftUUID := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    expected := []*model.ShareableLink{{Label: "Jane"}, {Label: "John"}}
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, draftUUID}, returns: []any{expected}}
+
+    links, err := NewDraftsService(r).Links(ctx, draftUUID)
+
+    assert.NoError(t, err)
+    assert.Equal(t, expected, links)
+  })
+
+  t.Run("wrong draft uuid", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{}
+
+    _, err := NewDraftsService(r).Links(ctx, "e4d06ba7-f086-47dc-9f5e")
+
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+}
+
+func (mock *archiveRepositoryMockAPIForDrafts) RevokeLink(_ context.Context, linkID string) error {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], linkID)
+  }
+
+  return mock.errors
+}
+
+func TestDraftsService_RevokeLink(t *testing.T) {
+  ctx := context.TODO()
+  linkUUID := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, linkUUID}}
+    assert.NoError(t, NewDraftsService(r).RevokeLink(ctx, linkUUID))
+    assert.True(t, r.called)
+  })
+
+  t.Run("wrong link uuid", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{}
+    assert.Error(t, NewDraftsService(r).RevokeLink(ctx, "e4d06ba7-f086-47dc-9f5e"))
+    assert.False(t, r.called)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &archiveRepositoryMockAPIForDrafts{errors: unexpected}
+    assert.ErrorIs(t, NewDraftsService(r).RevokeLink(ctx, linkUUID), unexpected)
+  })
+}
+
+func (mock *archiveRepositoryMockAPIForDrafts) ExtendLink(_ context.Context, linkID string, days int) error {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], linkID)
+    require.Equal(mock.t, mock.arguments[2], days)
+  }
+
+  return mock.errors
+}
+
+func TestDraftsService_ExtendLink(t *testing.T) {
+  ctx := context.TODO()
+  linkUUID := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, linkUUID, 14}}
+    assert.NoError(t, NewDraftsService(r).ExtendLink(ctx, linkUUID, 14))
+    assert.True(t, r.called)
+  })
+
+  t.Run("wrong link uuid", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{}
+    assert.Error(t, NewDraftsService(r).ExtendLink(ctx, "e4d06ba7-f086-47dc-9f5e", 14))
+    assert.False(t, r.called)
+  })
+
+  t.Run("days out of range", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{}
+    assert.Error(t, NewDraftsService(r).ExtendLink(ctx, linkUUID, 0))
+    assert.Error(t, NewDraftsService(r).ExtendLink(ctx, linkUUID, ShareableLinkMaxExpiry+1))
+    assert.False(t, r.called)
+  })
+}
+
+func (mock *archiveRepositoryMockAPIForDrafts) LinkPassword(context.Context, string) (string, error) {
+  return mock.password, nil
+}
+
+func (mock *archiveRepositoryMockAPIForDrafts) GetByLink(_ context.Context, link string) (*model.Article, error) {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], link)
+  }
+
+  return mock.returns[0].(*model.Article), mock.errors
+}
+
+func TestDraftsService_GetByLink(t *testing.T) {
+  ctx := context.TODO()
+  link := "/archive/sharing/6d7f"
+  hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+  require.NoError(t, err)
+
+  t.Run("success", func(t *testing.T) {
+    expected := &model.Article{Title: "Title"}
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, link}, returns: []any{expected}}
+
+    article, err := NewDraftsService(r).GetByLink(ctx, link, "")
+
+    assert.NoError(t, err)
+    assert.Equal(t, expected, article)
+  })
+
+  t.Run("unlocks a protected link", func(t *testing.T) {
+    expected := &model.Article{Title: "Title"}
+    r := &archiveRepositoryMockAPIForDrafts{returns: []any{expected}, password: string(hash)}
+    s := NewDraftsService(r)
+
+    token, err := s.Unlock(ctx, link, "correct horse")
+    require.NoError(t, err)
+    require.NotEmpty(t, token)
+
+    article, err := s.GetByLink(ctx, link, token)
+
+    assert.NoError(t, err)
+    assert.Equal(t, expected, article)
+  })
+
+  t.Run("protected link without a token", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{password: string(hash)}
+
+    _, err := NewDraftsService(r).GetByLink(ctx, link, "")
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+    assert.Contains(t, p.Error(), "protected by a password")
+    assert.False(t, r.called)
+  })
+
+  t.Run("wrong password", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{password: string(hash)}
+
+    token, err := NewDraftsService(r).Unlock(ctx, link, "wrong horse")
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+    assert.Empty(t, token)
+  })
+}
+
 func (mock *archiveRepositoryMockAPIForDrafts) Discard(_ context.Context, draftID string) error {
   mock.called = true
 
@@ -395,6 +856,15 @@ func TestDraftsService_Revise(t *testing.T) {
     assert.NoError(t, NewDraftsService(r).Revise(ctx, draftUUID, dirty))
   })
 
+  t.Run("reanchors the feedback", func(t *testing.T) {
+    r := &archiveRepositoryMockForRevise{}
+    f := &reanchorerMock{}
+    s := NewDraftsService(r)
+    s.SetFeedback(f)
+    assert.NoError(t, s.Revise(ctx, draftUUID, &transfer.ArticleRevision{CoverURL: "http://placeimg.com/640/480"}))
+    assert.Equal(t, []string{draftUUID}, f.reanchored)
+  })
+
   t.Run("success: changing title", func(t *testing.T) {
     revision := &transfer.ArticleRevision{
       Title:    "Consectetur-Adipiscing!!... Quis nostrud: ELIT??? +-'\"",
@@ -438,6 +908,12 @@ func TestDraftsService_Revise(t *testing.T) {
     assert.False(t, r.called)
   })
 
+  t.Run("script canonical url", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{}
+    assert.Error(t, NewDraftsService(r).Revise(ctx, draftUUID, &transfer.ArticleRevision{CanonicalURL: "javascript:alert(document.cookie)"}))
+    assert.False(t, r.called)
+  })
+
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
 
@@ -446,3 +922,137 @@ func TestDraftsService_Revise(t *testing.T) {
     assert.ErrorIs(t, NewDraftsService(r).Revise(ctx, draftUUID, &transfer.ArticleRevision{}), unexpected)
   })
 }
+
+func (mock *archiveRepositoryMockAPIForDrafts) Transition(_ context.Context, draftID string, transition *model.WorkflowTransition) error {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], draftID)
+    require.Equal(mock.t, mock.arguments[2], transition)
+  }
+
+  return mock.errors
+}
+
+func TestDraftsService_Transition(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+  comment := "Ready for a second look."
+
+  t.Run("success", func(t *testing.T) {
+    expected := &model.WorkflowTransition{From: "writing", To: "in_review", Actor: "jane", Comment: &comment}
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, id, expected}, state: "writing"}
+
+    transition := &transfer.WorkflowTransition{State: " in_review ", Actor: " jane ", Comment: " " + comment + " "}
+    assert.NoError(t, NewDraftsService(r).Transition(ctx, id, transition))
+    assert.True(t, r.called)
+  })
+
+  t.Run("leaves out an empty comment", func(t *testing.T) {
+    expected := &model.WorkflowTransition{From: "in_review", To: "approved", Actor: "jane"}
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, id, expected}, state: "in_review"}
+
+    transition := &transfer.WorkflowTransition{State: "approved", Actor: "jane", Comment: " \n "}
+    assert.NoError(t, NewDraftsService(r).Transition(ctx, id, transition))
+  })
+
+  t.Run("refuses a transition the workflow does not allow", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{state: "writing"}
+
+    err := NewDraftsService(r).Transition(ctx, id, &transfer.WorkflowTransition{State: "approved", Actor: "jane"})
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.False(t, r.called)
+  })
+
+  t.Run("refuses an unknown state", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{state: "writing"}
+
+    err := NewDraftsService(r).Transition(ctx, id, &transfer.WorkflowTransition{State: "shipped", Actor: "jane"})
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    assert.Equal(t, http.StatusBadRequest, recorder.Code)
+    assert.Contains(t, recorder.Body.String(), `"field":"state"`)
+    assert.False(t, r.called)
+  })
+
+  t.Run("refuses a blank actor", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{state: "writing"}
+    assert.Error(t, NewDraftsService(r).Transition(ctx, id, &transfer.WorkflowTransition{State: "in_review", Actor: " \t "}))
+    assert.False(t, r.called)
+  })
+
+  t.Run("refuses to transition without a workflow", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{state: "writing"}
+    s := NewDraftsService(r)
+    s.SetWorkflow(nil)
+    assert.Error(t, s.Transition(ctx, id, &transfer.WorkflowTransition{State: "in_review", Actor: "jane"}))
+    assert.False(t, r.called)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &archiveRepositoryMockAPIForDrafts{state: "writing", errors: unexpected}
+    assert.ErrorIs(t, NewDraftsService(r).Transition(ctx, id, &transfer.WorkflowTransition{State: "in_review", Actor: "jane"}), unexpected)
+  })
+
+  t.Run("wrong uuid", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{}
+    assert.Error(t, NewDraftsService(r).Transition(ctx, "e4d06ba7-f086-47dc-9f5e", &transfer.WorkflowTransition{State: "in_review", Actor: "jane"}))
+    assert.False(t, r.called)
+  })
+}
+
+func (mock *archiveRepositoryMockAPIForDrafts) History(_ context.Context, draftID string) ([]*model.WorkflowTransition, error) {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], draftID)
+  }
+
+  return mock.returns[0].([]*model.WorkflowTransition), mock.errors
+}
+
+func TestDraftsService_History(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    transitions := []*model.WorkflowTransition{{From: "writing", To: "in_review", Actor: "jane"}}
+    r := &archiveRepositoryMockAPIForDrafts{t: t, arguments: []any{ctx, id}, returns: []any{transitions}, state: "in_review"}
+
+    history, err := NewDraftsService(r).History(ctx, id)
+
+    assert.NoError(t, err)
+    assert.Equal(t, &model.WorkflowHistory{State: "in_review", Transitions: transitions}, history)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &archiveRepositoryMockAPIForDrafts{returns: []any{([]*model.WorkflowTransition)(nil)}, errors: unexpected}
+
+    history, err := NewDraftsService(r).History(ctx, id)
+
+    assert.ErrorIs(t, err, unexpected)
+    assert.Nil(t, history)
+  })
+
+  t.Run("wrong uuid", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForDrafts{}
+
+    history, err := NewDraftsService(r).History(ctx, "e4d06ba7-f086-47dc-9f5e")
+
+    assert.Error(t, err)
+    assert.Nil(t, history)
+    assert.False(t, r.called)
+  })
+}
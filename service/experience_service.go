@@ -160,6 +160,10 @@ func (s *ExperienceService) Update(ctx context.Context, id string, update *trans
     return err
   }
 
+  if 0 > update.Version {
+    return problem.NewValidation([3]string{"version", "min", "0"})
+  }
+
   update.JobTitle = strings.TrimSpace(update.JobTitle)
   update.Company = strings.TrimSpace(update.Company)
   update.Starts = strings.TrimSpace(update.Starts)
@@ -0,0 +1,707 @@
+package service
+
+import (
+  "bytes"
+  "context"
+  "crypto/rsa"
+  "encoding/json"
+  "errors"
+  "fmt"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "io"
+  "log/slog"
+  "net"
+  "net/http"
+  "net/netip"
+  "net/url"
+  "strconv"
+  "strings"
+  "sync"
+  "syscall"
+  "time"
+)
+
+const (
+  federationUsername = "archive"
+
+  activityJSON = "application/activity+json"
+)
+
+// Paths of the ActivityPub endpoints, relative to the base URL of the
+// site.
+const (
+  federationActorPath     = "/activitypub/actor"
+  federationInboxPath     = "/activitypub/inbox"
+  federationOutboxPath    = "/activitypub/outbox"
+  federationFollowersPath = "/activitypub/followers"
+  federationArticlesPath  = "/activitypub/articles/"
+)
+
+// maxActivitySize is the maximum size of a remote ActivityPub document.
+const maxActivitySize = 1 << 20
+
+type archiveRepositoryAPIForFederation interface {
+  List(ctx context.Context, filter *transfer.ArticleFilter, hidden, draftsOnly bool) (page *transfer.Page[*transfer.Article], err error)
+  GetByID(ctx context.Context, articleID string, isDraft bool) (article *model.Article, err error)
+}
+
+type followersRepositoryAPI interface {
+  Add(ctx context.Context, follower *model.Follower) error
+  Remove(ctx context.Context, actor string) error
+  List(ctx context.Context) (followers []*model.Follower, err error)
+  Count(ctx context.Context) (count int64, err error)
+}
+
+// federator shares published articles with the fediverse.
+type federator interface {
+  Federate(ctx context.Context, articleID, activityType string)
+}
+
+// delivery is an activity waiting to be delivered to an inbox.
+type delivery struct {
+  inbox   string
+  body    []byte
+  attempt int
+}
+
+// errPermanentDelivery is returned when an inbox refuses an activity,
+// so there is no point in trying to deliver it again.
+var errPermanentDelivery = errors.New("inbox refused the activity")
+
+// FederationService exposes the archive as an ActivityPub actor, so
+// that fediverse users can follow it and receive its new articles.
+//
+// Activities are delivered asynchronously by RunDelivery. Failed
+// deliveries are retried after each of the retries delays and dropped
+// after the last one. Pending deliveries are not persisted, so they
+// are lost if the server stops.
+type FederationService struct {
+  r         archiveRepositoryAPIForFederation
+  followers followersRepositoryAPI
+  key       *rsa.PrivateKey
+  markdown  MarkdownRenderer
+  baseURL   string
+  client    *http.Client
+  queue     chan *delivery
+  retries   []time.Duration
+}
+
+func NewFederationService(r archiveRepositoryAPIForFederation, followers followersRepositoryAPI, key *rsa.PrivateKey, markdown MarkdownRenderer) *FederationService {
+  return &FederationService{
+    r:         r,
+    followers: followers,
+    key:       key,
+    markdown:  markdown,
+    baseURL:   "https://fontseca.dev",
+    client:    newFederationClient(),
+    queue:     make(chan *delivery, 1024),
+    retries:   []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour, 12 * time.Hour},
+  }
+}
+
+// SetBaseURL sets the URL, like 'https://fontseca.dev', the actor and
+// the articles of the archive are relative to. Its host is the domain
+// of the actor's WebFinger address.
+func (s *FederationService) SetBaseURL(base string) {
+  s.baseURL = strings.TrimSuffix(base, "/")
+}
+
+// url returns the absolute URL of path.
+func (s *FederationService) url(path string) string {
+  return s.baseURL + path
+}
+
+// actorURL returns the ID of the ActivityPub actor of the archive.
+func (s *FederationService) actorURL() string {
+  return s.url(federationActorPath)
+}
+
+// keyID returns the ID of the key that signs the requests of the actor.
+func (s *FederationService) keyID() string {
+  return s.actorURL() + "#main-key"
+}
+
+// account returns the WebFinger address of the actor, like
+// 'acct:archive@fontseca.dev'.
+func (s *FederationService) account() string {
+  domain := s.baseURL
+  if u, err := url.Parse(s.baseURL); nil == err {
+    domain = u.Host
+  }
+
+  return "acct:" + federationUsername + "@" + domain
+}
+
+// errRefusedAddress is returned when a remote server resolves to an
+// address of our own network.
+var errRefusedAddress = errors.New("refused to connect to a non-public address")
+
+// newFederationClient creates the client that reaches remote servers.
+// Since their URLs are chosen by whoever posts to the inbox, it only
+// follows https URLs and refuses to connect to loopback, private,
+// link-local and unspecified addresses, so that it can't be used to
+// reach the internal network of the server.
+func newFederationClient() *http.Client {
+  dialer := &net.Dialer{Timeout: 5 * time.Second, Control: refuseNonPublicAddress}
+
+  transport := http.DefaultTransport.(*http.Transport).Clone()
+  transport.Proxy = nil
+  transport.DialContext = dialer.DialContext
+
+  return &http.Client{
+    Timeout:   10 * time.Second,
+    Transport: transport,
+    CheckRedirect: func(r *http.Request, via []*http.Request) error {
+      if "https" != r.URL.Scheme {
+        return fmt.Errorf("refused to follow a redirect to %s", r.URL.Scheme)
+      }
+
+      if 5 <= len(via) {
+        return errors.New("stopped after 5 redirects")
+      }
+
+      return nil
+    },
+  }
+}
+
+// refuseNonPublicAddress is the control of a dialer that refuses to
+// connect to addresses that are not public. It runs after the host has
+// been resolved, so a public name pointing to a private address is
+// refused too.
+func refuseNonPublicAddress(_, address string, _ syscall.RawConn) error {
+  host, _, err := net.SplitHostPort(address)
+  if nil != err {
+    return err
+  }
+
+  ip, err := netip.ParseAddr(host)
+  if nil != err {
+    return err
+  }
+
+  ip = ip.Unmap()
+
+  if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+    return fmt.Errorf("%w: %s", errRefusedAddress, ip)
+  }
+
+  return nil
+}
+
+// WebFinger resolves resource, which is either 'acct:archive@fontseca.dev'
+// or the ID of the actor, into a WebFinger resource pointing to the actor.
+func (s *FederationService) WebFinger(resource string) (*transfer.WebFinger, error) {
+  resource = strings.TrimSpace(resource)
+
+  switch resource {
+  default:
+    return nil, problem.NewNotFound(resource, "actor")
+  case s.account(), s.actorURL():
+  }
+
+  return &transfer.WebFinger{
+    Subject: s.account(),
+    Aliases: []string{s.actorURL()},
+    Links: []transfer.WebFingerLink{
+      {Rel: "self", Type: activityJSON, Href: s.actorURL()},
+      {Rel: "http://webfinger.net/rel/profile-page", Type: "text/html", Href: s.url("/archive")},
+    },
+  }, nil
+}
+
+// Actor retrieves the actor document of the archive.
+func (s *FederationService) Actor() (*transfer.Actor, error) {
+  publicKey, err := encodePublicKey(&s.key.PublicKey)
+  if nil != err {
+    slog.Error(err.Error())
+    return nil, problem.NewInternal()
+  }
+
+  return &transfer.Actor{
+    Context:           []string{transfer.ActivityStreams, "https://w3id.org/security/v1"},
+    ID:                s.actorURL(),
+    Type:              "Service",
+    PreferredUsername: federationUsername,
+    Name:              "fontseca.dev's archive",
+    Summary:           "Articles published in the archive of fontseca.dev.",
+    URL:               s.url("/archive"),
+    Inbox:             s.url(federationInboxPath),
+    Outbox:            s.url(federationOutboxPath),
+    Followers:         s.url(federationFollowersPath),
+    PublicKey: &transfer.ActorKey{
+      ID:           s.keyID(),
+      Owner:        s.actorURL(),
+      PublicKeyPem: publicKey,
+    },
+  }, nil
+}
+
+// Outbox retrieves the outbox of the archive. If page is false, only the
+// collection is retrieved; otherwise, a page of Create activities for the
+// published articles is retrieved, starting after cursor.
+func (s *FederationService) Outbox(ctx context.Context, page bool, cursor string) (*transfer.OrderedCollection, error) {
+  if !page {
+    return &transfer.OrderedCollection{
+      Context: transfer.ActivityStreams,
+      ID:      s.url(federationOutboxPath),
+      Type:    "OrderedCollection",
+      First:   s.url(federationOutboxPath) + "?page=true",
+    }, nil
+  }
+
+  articles, err := s.r.List(ctx, &transfer.ArticleFilter{Cursor: cursor, RPP: 20}, false, false)
+  if nil != err {
+    return nil, err
+  }
+
+  id := s.url(federationOutboxPath) + "?page=true"
+  if "" != cursor {
+    id += "&cursor=" + url.QueryEscape(cursor)
+  }
+
+  collection := &transfer.OrderedCollection{
+    Context:      transfer.ActivityStreams,
+    ID:           id,
+    Type:         "OrderedCollectionPage",
+    PartOf:       s.url(federationOutboxPath),
+    OrderedItems: make([]any, 0, len(articles.Items)),
+  }
+
+  if "" != articles.NextCursor {
+    collection.Next = s.url(federationOutboxPath) + "?page=true&cursor=" + url.QueryEscape(articles.NextCursor)
+  }
+
+  for _, article := range articles.Items {
+    topic := ""
+    if nil != article.Topic {
+      topic = article.Topic.ID
+    }
+
+    object := &transfer.ArticleObject{
+      ID:           s.url(federationArticlesPath) + article.UUID.String(),
+      Type:         "Article",
+      AttributedTo: s.actorURL(),
+      Name:         article.Title,
+      Summary:      article.Summary,
+      URL:          s.articleURL(topic, article.PublishedAt, article.Slug),
+      Published:    article.PublishedAt,
+      To:           []string{transfer.Public},
+      CC:           []string{s.url(federationFollowersPath)},
+    }
+
+    collection.OrderedItems = append(collection.OrderedItems, &transfer.Activity{
+      ID:        object.ID + "#create",
+      Type:      "Create",
+      Actor:     s.actorURL(),
+      Published: article.PublishedAt,
+      To:        object.To,
+      CC:        object.CC,
+      Object:    object,
+    })
+  }
+
+  return collection, nil
+}
+
+// Article retrieves the ActivityPub representation of a published
+// article, which is the object of the activities about it.
+func (s *FederationService) Article(ctx context.Context, id string) (*transfer.ArticleObject, error) {
+  if err := validateUUID(&id); nil != err {
+    return nil, err
+  }
+
+  article, err := s.r.GetByID(ctx, id, false)
+  if nil != err {
+    return nil, err
+  }
+
+  object := s.newArticleObject(article)
+  object.Context = transfer.ActivityStreams
+
+  return object, nil
+}
+
+// Followers retrieves the collection of followers of the archive. Only
+// the number of followers is disclosed.
+func (s *FederationService) Followers(ctx context.Context) (*transfer.OrderedCollection, error) {
+  count, err := s.followers.Count(ctx)
+  if nil != err {
+    return nil, err
+  }
+
+  return &transfer.OrderedCollection{
+    Context:    transfer.ActivityStreams,
+    ID:         s.url(federationFollowersPath),
+    Type:       "OrderedCollection",
+    TotalItems: &count,
+  }, nil
+}
+
+// Receive processes an activity posted to the inbox of the archive. The
+// request must be signed by the actor of the activity. Follow activities
+// are accepted right away and Undo activities of a Follow remove the
+// follower; any other activity is ignored.
+func (s *FederationService) Receive(ctx context.Context, r *http.Request, body []byte) error {
+  var activity struct {
+    ID     string          `json:"id"`
+    Type   string          `json:"type"`
+    Actor  string          `json:"actor"`
+    Object json.RawMessage `json:"object"`
+  }
+
+  if err := json.Unmarshal(body, &activity); nil != err || "" == activity.Actor {
+    return problem.NewUnparsableValue("activity", "body", string(body))
+  }
+
+  var actor *transfer.Actor
+
+  owner, err := verifyRequest(r, body, func(keyID string) (*rsa.PublicKey, string, error) {
+    var err error
+
+    if actor, err = s.fetchActor(ctx, keyID); nil != err {
+      return nil, "", err
+    }
+
+    if nil == actor.PublicKey || keyID != actor.PublicKey.ID {
+      return nil, "", errors.New("actor has no such key")
+    }
+
+    key, err := parsePublicKey(actor.PublicKey.PublicKeyPem)
+    if nil != err {
+      return nil, "", err
+    }
+
+    return key, actor.ID, nil
+  })
+
+  if nil == err && owner != activity.Actor {
+    err = errors.New("activity is not signed by its actor")
+  }
+
+  if nil != err {
+    slog.Error("could not verify activity",
+      slog.String("actor", activity.Actor),
+      slog.String("error", err.Error()),
+    )
+
+    var p problem.Problem
+    p.Type(problem.TypeActionRefused)
+    p.Status(http.StatusUnauthorized)
+    p.Title("Invalid signature.")
+    p.Detail("The activity could not be verified.")
+    return &p
+  }
+
+  switch activity.Type {
+  case "Follow":
+    if s.actorURL() != objectID(activity.Object) {
+      return nil
+    }
+
+    follower := &model.Follower{Actor: actor.ID, Inbox: actor.Inbox}
+
+    if nil != actor.Endpoints && "" != actor.Endpoints.SharedInbox {
+      follower.SharedInbox = &actor.Endpoints.SharedInbox
+    }
+
+    if err = s.followers.Add(ctx, follower); nil != err {
+      return err
+    }
+
+    s.send(actor.Inbox, &transfer.Activity{
+      Context: transfer.ActivityStreams,
+      ID:      s.actorURL() + "#accepts/" + strconv.FormatInt(time.Now().UnixNano(), 36),
+      Type:    "Accept",
+      Actor:   s.actorURL(),
+      Object:  json.RawMessage(body),
+    })
+  case "Undo":
+    var object struct {
+      Type   string          `json:"type"`
+      Object json.RawMessage `json:"object"`
+    }
+
+    if err = json.Unmarshal(activity.Object, &object); nil != err || "Follow" != object.Type {
+      return nil
+    }
+
+    if s.actorURL() != objectID(object.Object) {
+      return nil
+    }
+
+    return s.followers.Remove(ctx, activity.Actor)
+  }
+
+  return nil
+}
+
+// Federate delivers an activity of type activityType (either 'Create' or
+// 'Update') about the published article articleID to every follower.
+// Failures are logged because federation must never stop an article
+// from being published.
+func (s *FederationService) Federate(ctx context.Context, articleID, activityType string) {
+  article, err := s.r.GetByID(ctx, articleID, false)
+  if nil != err {
+    slog.Error(fmt.Sprintf("could not federate article %s: %v", articleID, err))
+    return
+  }
+
+  object := s.newArticleObject(article)
+  now := time.Now().UTC()
+
+  activity := &transfer.Activity{
+    Context:   transfer.ActivityStreams,
+    ID:        object.ID + "#" + strings.ToLower(activityType) + "-" + strconv.FormatInt(now.Unix(), 10),
+    Type:      activityType,
+    Actor:     s.actorURL(),
+    Published: &now,
+    To:        object.To,
+    CC:        object.CC,
+    Object:    object,
+  }
+
+  followers, err := s.followers.List(ctx)
+  if nil != err {
+    slog.Error(fmt.Sprintf("could not federate article %s: %v", articleID, err))
+    return
+  }
+
+  inboxes := make(map[string]bool, len(followers))
+
+  for _, follower := range followers {
+    inbox := follower.Inbox
+    if nil != follower.SharedInbox {
+      inbox = *follower.SharedInbox
+    }
+
+    if !inboxes[inbox] {
+      inboxes[inbox] = true
+      s.send(inbox, activity)
+    }
+  }
+}
+
+// RunDelivery delivers the queued activities with the given number of
+// workers until ctx is done.
+func (s *FederationService) RunDelivery(ctx context.Context, workers int) {
+  var wg sync.WaitGroup
+
+  for range workers {
+    wg.Add(1)
+
+    go func() {
+      defer wg.Done()
+
+      for {
+        select {
+        case <-ctx.Done():
+          return
+        case d := <-s.queue:
+          s.deliver(ctx, d)
+        }
+      }
+    }()
+  }
+
+  wg.Wait()
+}
+
+// send queues an activity to be delivered to inbox.
+func (s *FederationService) send(inbox string, activity *transfer.Activity) {
+  body, err := json.Marshal(activity)
+  if nil != err {
+    slog.Error(err.Error())
+    return
+  }
+
+  s.enqueue(&delivery{inbox: inbox, body: body})
+}
+
+func (s *FederationService) enqueue(d *delivery) {
+  select {
+  case s.queue <- d:
+  default:
+    slog.Error("delivery queue is full, dropping activity for " + d.inbox)
+  }
+}
+
+// deliver posts a queued activity to its inbox and schedules a retry if
+// it fails for a reason that might be temporary.
+func (s *FederationService) deliver(ctx context.Context, d *delivery) {
+  err := s.post(ctx, d.inbox, d.body)
+  if nil == err {
+    return
+  }
+
+  if errors.Is(err, errPermanentDelivery) || len(s.retries) <= d.attempt {
+    slog.Error(fmt.Sprintf("could not deliver activity to %s after %d attempts: %v", d.inbox, d.attempt+1, err))
+    return
+  }
+
+  delay := s.retries[d.attempt]
+  d.attempt++
+
+  time.AfterFunc(delay, func() {
+    if nil == ctx.Err() {
+      s.enqueue(d)
+    }
+  })
+}
+
+// post sends a signed activity to an inbox.
+func (s *FederationService) post(ctx context.Context, inbox string, body []byte) error {
+  ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+  defer cancel()
+
+  request, err := http.NewRequestWithContext(ctx, http.MethodPost, inbox, bytes.NewReader(body))
+  if nil != err {
+    return fmt.Errorf("%w: %v", errPermanentDelivery, err)
+  }
+
+  request.Header.Set("Content-Type", activityJSON)
+  request.Header.Set("Accept", activityJSON)
+
+  if err = signRequest(request, s.keyID(), s.key, body); nil != err {
+    return err
+  }
+
+  response, err := s.client.Do(request)
+  if nil != err {
+    return err
+  }
+
+  defer response.Body.Close()
+  _, _ = io.Copy(io.Discard, io.LimitReader(response.Body, maxActivitySize))
+
+  switch {
+  case 300 > response.StatusCode:
+    return nil
+  case http.StatusRequestTimeout == response.StatusCode,
+    http.StatusTooManyRequests == response.StatusCode,
+    500 <= response.StatusCode:
+    return fmt.Errorf("inbox responded with status %d", response.StatusCode)
+  default:
+    return fmt.Errorf("%w with status %d", errPermanentDelivery, response.StatusCode)
+  }
+}
+
+// fetchActor retrieves the actor document at id, ignoring its fragment.
+// The request is signed, so that servers that require authorized fetches
+// can be followed too. Only https actors are fetched.
+func (s *FederationService) fetchActor(ctx context.Context, id string) (*transfer.Actor, error) {
+  id, _, _ = strings.Cut(id, "#")
+
+  if u, err := url.Parse(id); nil != err || "https" != u.Scheme || "" == u.Host {
+    return nil, fmt.Errorf("refused to fetch actor %q: not an https URL", id)
+  }
+
+  ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+  defer cancel()
+
+  request, err := http.NewRequestWithContext(ctx, http.MethodGet, id, nil)
+  if nil != err {
+    return nil, err
+  }
+
+  request.Header.Set("Accept", activityJSON)
+
+  if err = signRequest(request, s.keyID(), s.key, nil); nil != err {
+    return nil, err
+  }
+
+  response, err := s.client.Do(request)
+  if nil != err {
+    return nil, err
+  }
+
+  defer response.Body.Close()
+
+  if http.StatusOK != response.StatusCode {
+    return nil, fmt.Errorf("could not fetch actor %s: status %d", id, response.StatusCode)
+  }
+
+  var actor transfer.Actor
+
+  if err = json.NewDecoder(io.LimitReader(response.Body, maxActivitySize)).Decode(&actor); nil != err {
+    return nil, fmt.Errorf("could not decode actor %s: %v", id, err)
+  }
+
+  if id != actor.ID || "" == actor.Inbox {
+    return nil, fmt.Errorf("malformed actor %s", id)
+  }
+
+  return &actor, nil
+}
+
+// articleURL builds the public URL of a published article. It doesn't
+// depend on the request being served, so the outbox and the delivered
+// activities always agree on it.
+func (s *FederationService) articleURL(topic string, publishedAt *time.Time, slug string) string {
+  if "" == topic || nil == publishedAt {
+    return s.url("/archive")
+  }
+
+  return fmt.Sprint(s.baseURL, "/archive/", topic, "/", publishedAt.Year(), "/", int(publishedAt.Month()), "/", slug)
+}
+
+// objectID gets the ID of an activity object that is either its ID or
+// the object itself.
+func objectID(object json.RawMessage) string {
+  var id string
+
+  if err := json.Unmarshal(object, &id); nil == err {
+    return id
+  }
+
+  var embedded struct {
+    ID string `json:"id"`
+  }
+
+  _ = json.Unmarshal(object, &embedded)
+  return embedded.ID
+}
+
+// newArticleObject converts a published article into its ActivityPub
+// representation. Its content is rendered the same way it is on the
+// site, reusing the stored rendering if it is up to date.
+func (s *FederationService) newArticleObject(article *model.Article) *transfer.ArticleObject {
+  topic := ""
+  if nil != article.Topic {
+    topic = article.Topic.ID
+  }
+
+  content := ""
+  if nil != article.Rendering && s.markdown.Version() == article.Rendering.Version {
+    content = article.Rendering.HTML
+  } else {
+    content = s.markdown.Render(article.Content)
+  }
+
+  object := &transfer.ArticleObject{
+    ID:           s.url(federationArticlesPath) + article.UUID.String(),
+    Type:         "Article",
+    AttributedTo: s.actorURL(),
+    Name:         article.Title,
+    Summary:      article.Summary,
+    Content:      content,
+    MediaType:    "text/html",
+    URL:          s.articleURL(topic, article.PublishedAt, article.Slug),
+    Published:    article.PublishedAt,
+    Updated:      article.ModifiedAt,
+    To:           []string{transfer.Public},
+    CC:           []string{s.url(federationFollowersPath)},
+  }
+
+  for _, tag := range article.Tags {
+    object.Tag = append(object.Tag, transfer.Hashtag{
+      Type: "Hashtag",
+      Href: s.url("/archive/tag/") + tag.ID,
+      Name: "#" + strings.ReplaceAll(tag.Name, " ", ""),
+    })
+  }
+
+  return object
+}
@@ -0,0 +1,503 @@
+package service
+
+import (
+  "bytes"
+  "context"
+  "crypto/rand"
+  "crypto/rsa"
+  "encoding/json"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "io"
+  "net/http"
+  "net/http/httptest"
+  "sync"
+  "testing"
+  "time"
+)
+
+// The actor of the archive and its inbox at the default base URL.
+const (
+  archiveActor = "https://fontseca.dev" + federationActorPath
+  archiveInbox = "https://fontseca.dev" + federationInboxPath
+)
+
+// fediverseStandIn is a remote fediverse server with one actor whose
+// inbox verifies and records the activities delivered to it.
+type fediverseStandIn struct {
+  server   *httptest.Server
+  key      *rsa.PrivateKey
+  failures int // how many deliveries fail before one succeeds
+  received chan *transfer.Activity
+
+  mu       sync.Mutex
+  attempts int
+}
+
+func newFediverseStandIn(t *testing.T, archiveKey *rsa.PublicKey) *fediverseStandIn {
+  key, err := rsa.GenerateKey(rand.Reader, 2048)
+  require.NoError(t, err)
+
+  standIn := &fediverseStandIn{key: key, received: make(chan *transfer.Activity, 8)}
+
+  mux := http.NewServeMux()
+
+  mux.HandleFunc("GET /users/alice", func(w http.ResponseWriter, r *http.Request) {
+    publicKey, _ := encodePublicKey(&key.PublicKey)
+    w.Header().Set("Content-Type", activityJSON)
+    _ = json.NewEncoder(w).Encode(&transfer.Actor{
+      ID:        standIn.actor(),
+      Type:      "Person",
+      Inbox:     standIn.actor() + "/inbox",
+      PublicKey: &transfer.ActorKey{ID: standIn.actor() + "#main-key", Owner: standIn.actor(), PublicKeyPem: publicKey},
+    })
+  })
+
+  mux.HandleFunc("POST /users/alice/inbox", func(w http.ResponseWriter, r *http.Request) {
+    body, _ := io.ReadAll(r.Body)
+
+    _, err := verifyRequest(r, body, func(keyID string) (*rsa.PublicKey, string, error) {
+      require.Equal(t, archiveActor+"#main-key", keyID)
+      return archiveKey, archiveActor, nil
+    })
+
+    if nil != err {
+      w.WriteHeader(http.StatusUnauthorized)
+      return
+    }
+
+    standIn.mu.Lock()
+    standIn.attempts++
+    fail := standIn.attempts <= standIn.failures
+    standIn.mu.Unlock()
+
+    if fail {
+      w.WriteHeader(http.StatusServiceUnavailable)
+      return
+    }
+
+    var activity transfer.Activity
+    require.NoError(t, json.Unmarshal(body, &activity))
+    standIn.received <- &activity
+    w.WriteHeader(http.StatusAccepted)
+  })
+
+  standIn.server = httptest.NewTLSServer(mux)
+  t.Cleanup(standIn.server.Close)
+
+  return standIn
+}
+
+func (f *fediverseStandIn) actor() string {
+  return f.server.URL + "/users/alice"
+}
+
+// post builds a request to the inbox of the archive signed by the
+// stand-in actor.
+func (f *fediverseStandIn) post(t *testing.T, activity any) (*http.Request, []byte) {
+  body, err := json.Marshal(activity)
+  require.NoError(t, err)
+
+  request := httptest.NewRequest(http.MethodPost, archiveInbox, bytes.NewReader(body))
+  require.NoError(t, signRequest(request, f.actor()+"#main-key", f.key, body))
+
+  return request, body
+}
+
+func (f *fediverseStandIn) wait(t *testing.T) *transfer.Activity {
+  select {
+  case activity := <-f.received:
+    return activity
+  case <-time.After(5 * time.Second):
+    t.Fatal("no activity was delivered")
+    return nil
+  }
+}
+
+type followersRepositoryMock struct {
+  followersRepositoryAPI
+  followers []*model.Follower
+  added     *model.Follower
+  removed   string
+}
+
+func (mock *followersRepositoryMock) Add(_ context.Context, follower *model.Follower) error {
+  mock.added = follower
+  return nil
+}
+
+func (mock *followersRepositoryMock) Remove(_ context.Context, actor string) error {
+  mock.removed = actor
+  return nil
+}
+
+func (mock *followersRepositoryMock) List(context.Context) ([]*model.Follower, error) {
+  return mock.followers, nil
+}
+
+type archiveRepositoryMockAPIForFederation struct {
+  archiveRepositoryAPIForFederation
+  article  *model.Article
+  articles []*transfer.Article
+}
+
+func (mock *archiveRepositoryMockAPIForFederation) GetByID(context.Context, string, bool) (*model.Article, error) {
+  return mock.article, nil
+}
+
+func (mock *archiveRepositoryMockAPIForFederation) List(context.Context, *transfer.ArticleFilter, bool, bool) (*transfer.Page[*transfer.Article], error) {
+  return &transfer.Page[*transfer.Article]{Items: mock.articles}, nil
+}
+
+func TestSignRequest(t *testing.T) {
+  key, err := rsa.GenerateKey(rand.Reader, 2048)
+  require.NoError(t, err)
+
+  resolve := func(string) (*rsa.PublicKey, string, error) { return &key.PublicKey, "owner", nil }
+  body := []byte(`{"type":"Follow"}`)
+
+  t.Run("success", func(t *testing.T) {
+    request := httptest.NewRequest(http.MethodPost, archiveInbox, bytes.NewReader(body))
+    require.NoError(t, signRequest(request, "key", key, body))
+
+    owner, err := verifyRequest(request, body, resolve)
+    assert.NoError(t, err)
+    assert.Equal(t, "owner", owner)
+  })
+
+  t.Run("tampered body", func(t *testing.T) {
+    request := httptest.NewRequest(http.MethodPost, archiveInbox, bytes.NewReader(body))
+    require.NoError(t, signRequest(request, "key", key, body))
+
+    _, err := verifyRequest(request, []byte(`{"type":"Undo"}`), resolve)
+    assert.ErrorContains(t, err, "digest")
+  })
+
+  t.Run("tampered target", func(t *testing.T) {
+    request := httptest.NewRequest(http.MethodPost, archiveInbox, bytes.NewReader(body))
+    require.NoError(t, signRequest(request, "key", key, body))
+    request.URL.Path = "/activitypub/outbox"
+
+    _, err := verifyRequest(request, body, resolve)
+    assert.ErrorContains(t, err, "does not match")
+  })
+
+  t.Run("stale date", func(t *testing.T) {
+    request := httptest.NewRequest(http.MethodPost, archiveInbox, bytes.NewReader(body))
+    request.Header.Set("Date", time.Now().Add(-24*time.Hour).UTC().Format(http.TimeFormat))
+    require.NoError(t, signRequest(request, "key", key, body))
+
+    _, err := verifyRequest(request, body, resolve)
+    assert.ErrorContains(t, err, "out of range")
+  })
+}
+
+func TestFederationService_WebFinger(t *testing.T) {
+  s := NewFederationService(nil, nil, nil, nil)
+
+  for _, resource := range []string{"acct:archive@fontseca.dev", archiveActor} {
+    webfinger, err := s.WebFinger(resource)
+    require.NoError(t, err)
+    assert.Equal(t, archiveActor, webfinger.Links[0].Href)
+  }
+
+  _, err := s.WebFinger("acct:someone@fontseca.dev")
+  assert.ErrorContains(t, err, "could not be found")
+
+  t.Run("custom base url", func(t *testing.T) {
+    s := NewFederationService(nil, nil, nil, nil)
+    s.SetBaseURL("https://staging.fontseca.dev/")
+
+    webfinger, err := s.WebFinger("acct:archive@staging.fontseca.dev")
+    require.NoError(t, err)
+    assert.Equal(t, "acct:archive@staging.fontseca.dev", webfinger.Subject)
+    assert.Equal(t, "https://staging.fontseca.dev/activitypub/actor", webfinger.Links[0].Href)
+
+    _, err = s.WebFinger("acct:archive@fontseca.dev")
+    assert.Error(t, err)
+  })
+}
+
+func TestFederationService_Receive(t *testing.T) {
+  key, err := rsa.GenerateKey(rand.Reader, 2048)
+  require.NoError(t, err)
+
+  standIn := newFediverseStandIn(t, &key.PublicKey)
+
+  follow := &transfer.Activity{
+    Context: transfer.ActivityStreams,
+    ID:      standIn.actor() + "#follows/1",
+    Type:    "Follow",
+    Actor:   standIn.actor(),
+    Object:  archiveActor,
+  }
+
+  t.Run("accepts follows", func(t *testing.T) {
+    followers := &followersRepositoryMock{}
+    s := NewFederationService(nil, followers, key, &markdownRendererMock{})
+    s.client = standIn.server.Client()
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+
+    go s.RunDelivery(ctx, 1)
+
+    request, body := standIn.post(t, follow)
+    require.NoError(t, s.Receive(ctx, request, body))
+
+    require.NotNil(t, followers.added)
+    assert.Equal(t, standIn.actor(), followers.added.Actor)
+    assert.Equal(t, standIn.actor()+"/inbox", followers.added.Inbox)
+
+    accept := standIn.wait(t)
+    assert.Equal(t, "Accept", accept.Type)
+    assert.Equal(t, archiveActor, accept.Actor)
+    assert.Equal(t, follow.ID, accept.Object.(map[string]any)["id"])
+  })
+
+  t.Run("undoes follows", func(t *testing.T) {
+    followers := &followersRepositoryMock{}
+    s := NewFederationService(nil, followers, key, &markdownRendererMock{})
+    s.client = standIn.server.Client()
+
+    request, body := standIn.post(t, &transfer.Activity{
+      ID:     standIn.actor() + "#follows/1/undo",
+      Type:   "Undo",
+      Actor:  standIn.actor(),
+      Object: follow,
+    })
+
+    require.NoError(t, s.Receive(context.Background(), request, body))
+    assert.Equal(t, standIn.actor(), followers.removed)
+  })
+
+  t.Run("unsigned activity", func(t *testing.T) {
+    followers := &followersRepositoryMock{}
+    s := NewFederationService(nil, followers, key, &markdownRendererMock{})
+    s.client = standIn.server.Client()
+
+    request, body := standIn.post(t, follow)
+    request.Header.Del("Signature")
+
+    assert.ErrorContains(t, s.Receive(context.Background(), request, body), "could not be verified")
+    assert.Nil(t, followers.added)
+  })
+
+  t.Run("signed by another actor", func(t *testing.T) {
+    followers := &followersRepositoryMock{}
+    s := NewFederationService(nil, followers, key, &markdownRendererMock{})
+    s.client = standIn.server.Client()
+
+    impersonation := *follow
+    impersonation.Actor = "https://example.com/users/bob"
+
+    request, body := standIn.post(t, &impersonation)
+
+    assert.ErrorContains(t, s.Receive(context.Background(), request, body), "could not be verified")
+    assert.Nil(t, followers.added)
+  })
+
+  t.Run("refuses non-https key IDs", func(t *testing.T) {
+    followers := &followersRepositoryMock{}
+    s := NewFederationService(nil, followers, key, &markdownRendererMock{})
+
+    insecure := *follow
+    insecure.Actor = "http://169.254.169.254/latest"
+
+    body, err := json.Marshal(&insecure)
+    require.NoError(t, err)
+
+    request := httptest.NewRequest(http.MethodPost, archiveInbox, bytes.NewReader(body))
+    require.NoError(t, signRequest(request, insecure.Actor+"#main-key", standIn.key, body))
+
+    err = s.Receive(context.Background(), request, body)
+    assert.ErrorContains(t, err, "could not be verified")
+    assert.NotContains(t, err.Error(), "169.254.169.254")
+    assert.Nil(t, followers.added)
+  })
+
+  t.Run("refuses non-public addresses", func(t *testing.T) {
+    followers := &followersRepositoryMock{}
+    s := NewFederationService(nil, followers, key, &markdownRendererMock{})
+
+    request, body := standIn.post(t, follow)
+
+    err := s.Receive(context.Background(), request, body)
+    assert.ErrorContains(t, err, "could not be verified")
+    assert.NotContains(t, err.Error(), "127.0.0.1")
+    assert.Nil(t, followers.added)
+  })
+}
+
+func TestRefuseNonPublicAddress(t *testing.T) {
+  for address, refused := range map[string]bool{
+    "127.0.0.1:443":         true,
+    "[::1]:443":             true,
+    "10.0.0.8:443":          true,
+    "192.168.1.1:443":       true,
+    "169.254.169.254:80":    true,
+    "[fe80::1]:443":         true,
+    "0.0.0.0:443":           true,
+    "[::ffff:127.0.0.1]:80": true,
+    "93.184.215.14:443":     false,
+  } {
+    err := refuseNonPublicAddress("tcp", address, nil)
+
+    if refused {
+      assert.ErrorIs(t, err, errRefusedAddress, address)
+    } else {
+      assert.NoError(t, err, address)
+    }
+  }
+}
+
+func TestFederationService_Outbox(t *testing.T) {
+  published := time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC)
+  article := &model.Article{UUID: uuid.New(), Slug: "consectetur-adipiscing-elit", PublishedAt: &published, Topic: &model.Topic{ID: "development"}}
+
+  listed := &transfer.Article{UUID: article.UUID, Slug: article.Slug, PublishedAt: &published, URL: "/archive/development/2026/10/consectetur-adipiscing-elit"}
+  listed.Topic = &struct {
+    ID   string `json:"id"`
+    Name string `json:"name"`
+    URL  string `json:"url"`
+  }{ID: "development"}
+
+  s := NewFederationService(&archiveRepositoryMockAPIForFederation{article: article, articles: []*transfer.Article{listed}}, nil, nil, &markdownRendererMock{})
+
+  outbox, err := s.Outbox(context.Background(), true, "")
+  require.NoError(t, err)
+  require.Len(t, outbox.OrderedItems, 1)
+
+  object, err := s.Article(context.Background(), article.UUID.String())
+  require.NoError(t, err)
+
+  assert.Equal(t, "https://fontseca.dev/archive/development/2026/10/consectetur-adipiscing-elit", object.URL)
+  assert.Equal(t, object.URL, outbox.OrderedItems[0].(*transfer.Activity).Object.(*transfer.ArticleObject).URL)
+
+  t.Run("custom base url", func(t *testing.T) {
+    s.SetBaseURL("https://staging.fontseca.dev")
+
+    outbox, err := s.Outbox(context.Background(), true, "")
+    require.NoError(t, err)
+    require.Len(t, outbox.OrderedItems, 1)
+
+    object := outbox.OrderedItems[0].(*transfer.Activity).Object.(*transfer.ArticleObject)
+    assert.Equal(t, "https://staging.fontseca.dev/archive/development/2026/10/consectetur-adipiscing-elit", object.URL)
+    assert.Equal(t, "https://staging.fontseca.dev/activitypub/actor", object.AttributedTo)
+  })
+}
+
+func TestFederationService_Article(t *testing.T) {
+  now := time.Now()
+  article := &model.Article{
+    UUID:        uuid.New(),
+    Slug:        "consectetur-adipiscing-elit",
+    PublishedAt: &now,
+    Content:     "Lorem ipsum.",
+  }
+
+  s := NewFederationService(&archiveRepositoryMockAPIForFederation{article: article}, nil, nil, &markdownRendererMock{version: 2})
+
+  t.Run("renders the content", func(t *testing.T) {
+    object, err := s.Article(context.Background(), article.UUID.String())
+    require.NoError(t, err)
+    assert.Equal(t, "<p>Lorem ipsum.</p>", object.Content)
+  })
+
+  t.Run("renders an outdated rendering again", func(t *testing.T) {
+    article.Rendering = &model.Rendering{HTML: "<p>Stored.</p>", Version: 1}
+    defer func() { article.Rendering = nil }()
+
+    object, err := s.Article(context.Background(), article.UUID.String())
+    require.NoError(t, err)
+    assert.Equal(t, "<p>Lorem ipsum.</p>", object.Content)
+  })
+
+  t.Run("reuses an up to date rendering", func(t *testing.T) {
+    article.Rendering = &model.Rendering{HTML: "<p>Stored.</p>", Version: 2}
+    defer func() { article.Rendering = nil }()
+
+    object, err := s.Article(context.Background(), article.UUID.String())
+    require.NoError(t, err)
+    assert.Equal(t, "<p>Stored.</p>", object.Content)
+  })
+}
+
+func TestFederationService_Federate(t *testing.T) {
+  key, err := rsa.GenerateKey(rand.Reader, 2048)
+  require.NoError(t, err)
+
+  now := time.Now()
+
+  article := &model.Article{
+    UUID:        uuid.New(),
+    Title:       "Consectetur adipiscing elit",
+    Slug:        "consectetur-adipiscing-elit",
+    PublishedAt: &now,
+    Topic:       &model.Topic{ID: "development"},
+    Tags:        []*model.Tag{{ID: "go", Name: "Go"}},
+    Content:     "Lorem ipsum.",
+  }
+
+  t.Run("delivers once per shared inbox", func(t *testing.T) {
+    standIn := newFediverseStandIn(t, &key.PublicKey)
+    shared := standIn.actor() + "/inbox"
+
+    followers := &followersRepositoryMock{followers: []*model.Follower{
+      {Actor: standIn.actor(), Inbox: standIn.actor() + "/inbox"},
+      {Actor: standIn.server.URL + "/users/bob", Inbox: standIn.server.URL + "/users/bob/inbox", SharedInbox: &shared},
+    }}
+
+    s := NewFederationService(&archiveRepositoryMockAPIForFederation{article: article}, followers, key, &markdownRendererMock{})
+    s.client = standIn.server.Client()
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+
+    go s.RunDelivery(ctx, 2)
+
+    s.Federate(ctx, article.UUID.String(), "Create")
+
+    create := standIn.wait(t)
+    assert.Equal(t, "Create", create.Type)
+    assert.Equal(t, []string{transfer.Public}, create.To)
+
+    object := create.Object.(map[string]any)
+    assert.Equal(t, "Article", object["type"])
+    assert.Equal(t, article.Title, object["name"])
+    assert.Equal(t, "<p>Lorem ipsum.</p>", object["content"])
+    assert.Contains(t, object["url"], "/archive/development/")
+
+    select {
+    case <-standIn.received:
+      t.Fatal("activity was delivered twice to the same inbox")
+    case <-time.After(100 * time.Millisecond):
+    }
+  })
+
+  t.Run("retries failed deliveries", func(t *testing.T) {
+    standIn := newFediverseStandIn(t, &key.PublicKey)
+    standIn.failures = 2
+
+    followers := &followersRepositoryMock{followers: []*model.Follower{
+      {Actor: standIn.actor(), Inbox: standIn.actor() + "/inbox"},
+    }}
+
+    s := NewFederationService(&archiveRepositoryMockAPIForFederation{article: article}, followers, key, &markdownRendererMock{})
+    s.client = standIn.server.Client()
+    s.retries = []time.Duration{10 * time.Millisecond, 10 * time.Millisecond}
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+
+    go s.RunDelivery(ctx, 1)
+
+    s.Federate(ctx, article.UUID.String(), "Update")
+
+    assert.Equal(t, "Update", standIn.wait(t).Type)
+    assert.Equal(t, 3, standIn.attempts)
+  })
+}
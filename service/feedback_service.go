@@ -0,0 +1,385 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "golang.org/x/net/html"
+  "log/slog"
+  "strconv"
+  "strings"
+)
+
+type feedbackRepositoryAPI interface {
+  SharedContent(ctx context.Context, link string) (draftID, content, passwordHash string, err error)
+  Content(ctx context.Context, draftID string) (content string, err error)
+  Add(ctx context.Context, draftID string, feedback *model.Feedback) (feedbackID string, err error)
+  List(ctx context.Context, draftID string) (feedback []*model.Feedback, err error)
+  Reanchor(ctx context.Context, feedback []*model.Feedback) error
+  Resolve(ctx context.Context, feedbackID string) error
+}
+
+// reanchorer moves the feedback on a draft or a patch to where it
+// belongs after the draft or the patch is revised.
+type reanchorer interface {
+  Reanchor(ctx context.Context, draftID string) error
+}
+
+// reanchor calls r, if any, logging its failure, since the revision
+// that required it has already been made.
+func reanchor(ctx context.Context, r reanchorer, draftUUID string) {
+  if nil == r {
+    return
+  }
+
+  if err := r.Reanchor(ctx, draftUUID); nil != err {
+    slog.Error("could not reanchor feedback",
+      slog.String("draft_uuid", draftUUID),
+      slog.String("error", err.Error()))
+  }
+}
+
+// feedbackMinSimilarity is the share of words a revised paragraph or
+// heading must have in common with the one some feedback was anchored
+// to for the feedback to be moved to it.
+const feedbackMinSimilarity = 0.5
+
+// feedbackAnchor is a paragraph or a heading of rendered content that
+// feedback can be anchored to.
+type feedbackAnchor struct {
+  id   string
+  text string
+}
+
+// FeedbackService is a high level provider for the feedback reviewers
+// leave on shared drafts and patches.
+//
+// Feedback is anchored to a paragraph or a heading of the rendered
+// content. Headings are identified by their IDs, and paragraphs by their
+// position, 'p-1' being the first one. When the content is revised, the
+// unresolved feedback is moved to the paragraph or heading that best
+// matches the one it was anchored to; if none does, it is orphaned.
+type FeedbackService struct {
+  r        feedbackRepositoryAPI
+  markdown MarkdownRenderer
+}
+
+func NewFeedbackService(r feedbackRepositoryAPI, markdown MarkdownRenderer) *FeedbackService {
+  return &FeedbackService{r: r, markdown: markdown}
+}
+
+// Leave stores the feedback a reviewer left on the draft or the patch
+// shared by link. If the link is protected by a password, token must be
+// the one DraftsService.Unlock retrieved for it. It returns the UUID of
+// the feedback.
+func (s *FeedbackService) Leave(ctx context.Context, link, token string, creation *transfer.FeedbackCreation) (feedbackUUID string, err error) {
+  if nil == creation {
+    err = errors.New("nil value for parameter: creation")
+    slog.Error(err.Error())
+    return "", err
+  }
+
+  creation.Anchor = strings.TrimSpace(creation.Anchor)
+  creation.Selection = collapseSpaces(creation.Selection)
+  creation.Reviewer = strings.TrimSpace(creation.Reviewer)
+  creation.Comment = strings.TrimSpace(creation.Comment)
+
+  switch {
+  case "" == creation.Anchor:
+    return "", problem.NewValidation([3]string{"anchor", "required", ""})
+  case 128 < len(creation.Anchor):
+    return "", problem.NewValidation([3]string{"anchor", "max", "128"})
+  case 1024 < len(creation.Selection):
+    return "", problem.NewValidation([3]string{"selection", "max", "1024"})
+  case "" == creation.Reviewer:
+    return "", problem.NewValidation([3]string{"reviewer", "required", ""})
+  case 64 < len(creation.Reviewer):
+    return "", problem.NewValidation([3]string{"reviewer", "max", "64"})
+  case "" == creation.Comment:
+    return "", problem.NewValidation([3]string{"comment", "required", ""})
+  case 2048 < len(creation.Comment):
+    return "", problem.NewValidation([3]string{"comment", "max", "2048"})
+  }
+
+  draftID, content, hash, err := s.r.SharedContent(ctx, link)
+  if nil != err {
+    return "", err
+  }
+
+  if err = checkShareableLinkToken(link, hash, token); nil != err {
+    return "", err
+  }
+
+  var anchor *feedbackAnchor
+
+  for _, a := range feedbackAnchors(s.markdown.Render(content)) {
+    if creation.Anchor == a.id {
+      anchor = a
+      break
+    }
+  }
+
+  if nil == anchor {
+    return "", problem.NewNotFound(creation.Anchor, "anchor")
+  }
+
+  feedback := &model.Feedback{
+    Anchor:     anchor.id,
+    AnchorText: anchor.text,
+    Reviewer:   creation.Reviewer,
+    Comment:    creation.Comment,
+  }
+
+  if "" != creation.Selection {
+    if !strings.Contains(anchor.text, creation.Selection) {
+      return "", problem.NewValidation([3]string{"selection", "within", anchor.id})
+    }
+
+    feedback.Selection = &creation.Selection
+  }
+
+  return s.r.Add(ctx, draftID, feedback)
+}
+
+// List retrieves the feedback on a draft or a patch, the unresolved
+// feedback first.
+func (s *FeedbackService) List(ctx context.Context, draftUUID string) (feedback []*model.Feedback, err error) {
+  if err = validateUUID(&draftUUID); nil != err {
+    return nil, err
+  }
+
+  return s.r.List(ctx, draftUUID)
+}
+
+// Resolve marks feedback as resolved, so that it is no longer moved
+// when its draft or patch is revised.
+func (s *FeedbackService) Resolve(ctx context.Context, feedbackUUID string) error {
+  if err := validateUUID(&feedbackUUID); nil != err {
+    return err
+  }
+
+  return s.r.Resolve(ctx, feedbackUUID)
+}
+
+// Reanchor moves the unresolved feedback on a draft or a patch to the
+// paragraphs and headings of its current content.
+func (s *FeedbackService) Reanchor(ctx context.Context, draftUUID string) error {
+  content, err := s.r.Content(ctx, draftUUID)
+  if nil != err {
+    return err
+  }
+
+  feedback, err := s.r.List(ctx, draftUUID)
+  if nil != err {
+    return err
+  }
+
+  moved := reanchorFeedback(feedback, feedbackAnchors(s.markdown.Render(content)))
+  if 0 == len(moved) {
+    return nil
+  }
+
+  return s.r.Reanchor(ctx, moved)
+}
+
+// reanchorFeedback finds the anchor of each unresolved feedback among
+// anchors and returns the feedback whose anchor changed. Feedback is
+// anchored, in order of preference, to:
+//
+//  1. the anchor with the same ID and the same text;
+//  2. the first anchor with the same text;
+//  3. an anchor that contains its selection, if it has one, preferably
+//     the one with the same ID;
+//  4. the anchor with the same ID, if its text is similar enough;
+//  5. the anchor whose text is the most similar, if it is similar enough.
+//
+// Otherwise, it is orphaned and keeps its last anchor.
+func reanchorFeedback(feedback []*model.Feedback, anchors []*feedbackAnchor) []*model.Feedback {
+  moved := make([]*model.Feedback, 0)
+
+  for _, f := range feedback {
+    if nil != f.ResolvedAt {
+      continue
+    }
+
+    anchor := findFeedbackAnchor(f, anchors)
+
+    switch {
+    case nil == anchor && !f.Orphaned:
+      f.Orphaned = true
+    case nil != anchor && (anchor.id != f.Anchor || anchor.text != f.AnchorText || f.Orphaned):
+      f.Anchor, f.AnchorText, f.Orphaned = anchor.id, anchor.text, false
+    default:
+      continue
+    }
+
+    moved = append(moved, f)
+  }
+
+  return moved
+}
+
+func findFeedbackAnchor(f *model.Feedback, anchors []*feedbackAnchor) *feedbackAnchor {
+  for _, a := range anchors {
+    if a.id == f.Anchor && a.text == f.AnchorText {
+      return a
+    }
+  }
+
+  for _, a := range anchors {
+    if a.text == f.AnchorText {
+      return a
+    }
+  }
+
+  if nil != f.Selection {
+    var containing *feedbackAnchor
+
+    for _, a := range anchors {
+      if !strings.Contains(a.text, *f.Selection) {
+        continue
+      }
+
+      if a.id == f.Anchor {
+        return a
+      }
+
+      if nil == containing {
+        containing = a
+      }
+    }
+
+    if nil != containing {
+      return containing
+    }
+  }
+
+  for _, a := range anchors {
+    if a.id == f.Anchor && feedbackMinSimilarity <= similarity(a.text, f.AnchorText) {
+      return a
+    }
+  }
+
+  var (
+    best      *feedbackAnchor
+    bestScore = feedbackMinSimilarity
+  )
+
+  for _, a := range anchors {
+    if score := similarity(a.text, f.AnchorText); bestScore <= score && (nil == best || bestScore < score) {
+      best, bestScore = a, score
+    }
+  }
+
+  return best
+}
+
+// similarity is the Jaccard index of the words of a and b, regardless of
+// their case: 1 when they have the same words, 0 when they share none.
+func similarity(a, b string) float64 {
+  words := func(s string) map[string]bool {
+    set := make(map[string]bool)
+    for _, w := range strings.Fields(strings.ToLower(s)) {
+      set[w] = true
+    }
+    return set
+  }
+
+  x, y := words(a), words(b)
+  if 0 == len(x) && 0 == len(y) {
+    return 1
+  }
+
+  shared := 0
+  for w := range x {
+    if y[w] {
+      shared++
+    }
+  }
+
+  return float64(shared) / float64(len(x)+len(y)-shared)
+}
+
+// feedbackAnchors retrieves the paragraphs and headings of rendered
+// content, in order. Paragraphs are identified by their position among
+// the <p> elements, which is how the shared page identifies them too, and
+// headings by their IDs; headings without an ID cannot be anchored to.
+func feedbackAnchors(rendered string) []*feedbackAnchor {
+  var (
+    anchors    = make([]*feedbackAnchor, 0)
+    z          = html.NewTokenizer(strings.NewReader(rendered))
+    current    *feedbackAnchor
+    element    string
+    text       strings.Builder
+    skip       int // depth inside the links to headings, whose text is not theirs
+    paragraphs int
+  )
+
+  for {
+    tt := z.Next()
+    if html.ErrorToken == tt {
+      return anchors
+    }
+
+    token := z.Token()
+
+    switch tt {
+    case html.StartTagToken:
+      switch token.Data {
+      case "p", "h1", "h2", "h3", "h4", "h5", "h6":
+        if "p" == token.Data {
+          paragraphs++
+        }
+
+        if nil != current {
+          continue
+        }
+
+        element = token.Data
+        text.Reset()
+
+        if "p" == token.Data {
+          current = &feedbackAnchor{id: "p-" + strconv.Itoa(paragraphs)}
+          continue
+        }
+
+        for _, attr := range token.Attr {
+          if "id" == attr.Key && "" != attr.Val {
+            current = &feedbackAnchor{id: attr.Val}
+          }
+        }
+      case "a":
+        if 0 < skip {
+          skip++
+        } else if nil != current {
+          for _, attr := range token.Attr {
+            if "class" == attr.Key && strings.Contains(attr.Val, "heading-anchor") {
+              skip = 1
+            }
+          }
+        }
+      }
+    case html.EndTagToken:
+      switch {
+      case "a" == token.Data && 0 < skip:
+        skip--
+      case nil != current && element == token.Data:
+        current.text = collapseSpaces(text.String())
+        anchors = append(anchors, current)
+        current = nil
+      }
+    case html.TextToken:
+      if nil != current && 0 == skip {
+        text.WriteString(token.Data)
+      }
+    }
+  }
+}
+
+// collapseSpaces trims s and collapses every run of whitespace in it
+// into a single space.
+func collapseSpaces(s string) string {
+  return strings.Join(strings.Fields(s), " ")
+}
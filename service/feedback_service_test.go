@@ -0,0 +1,298 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "testing"
+  "time"
+)
+
+type feedbackRepositoryMockAPI struct {
+  feedbackRepositoryAPI
+  t          *testing.T
+  returns    []any
+  arguments  []any
+  errors     error
+  called     bool
+  content    string
+  password   string // the hash of the password of the shareable link
+  reanchored []*model.Feedback
+}
+
+// htmlRendererMock renders content that is already HTML.
+type htmlRendererMock struct {
+  MarkdownRenderer
+}
+
+func (htmlRendererMock) Render(md string) string {
+  return md
+}
+
+const feedbackContent = `<p>Go is an open source programming language.</p>
+<h2 id="getting-started">Getting started<a class="heading-anchor" href="#getting-started">#</a></h2>
+<p>Install   the toolchain,
+then run <code>go version</code>.</p>
+<figure><img src="/gopher.png" alt=""/><figcaption><p>The gopher.</p></figcaption></figure>`
+
+func TestFeedbackAnchors(t *testing.T) {
+  anchors := feedbackAnchors(feedbackContent + `<h3>No ID</h3>`)
+
+  assert.Equal(t, []*feedbackAnchor{
+    {id: "p-1", text: "Go is an open source programming language."},
+    {id: "getting-started", text: "Getting started"},
+    {id: "p-2", text: "Install the toolchain, then run go version."},
+    {id: "p-3", text: "The gopher."},
+  }, anchors)
+}
+
+func TestReanchorFeedback(t *testing.T) {
+  var (
+    selection = "go version"
+    resolved  = time.Now()
+    anchors   = []*feedbackAnchor{
+      {id: "p-1", text: "A new introduction."},
+      {id: "p-2", text: "Go is an open source programming language."},
+      {id: "p-3", text: "Install the toolchain, then run go version to check it."},
+      {id: "p-4", text: "Something else entirely."},
+    }
+  )
+
+  feedback := []*model.Feedback{
+    {UUID: "unchanged", Anchor: "p-4", AnchorText: "Something else entirely."},
+    {UUID: "moved", Anchor: "p-1", AnchorText: "Go is an open source programming language."},
+    {UUID: "selection", Anchor: "p-2", AnchorText: "Install the toolchain, then run go version.", Selection: &selection},
+    {UUID: "similar", Anchor: "p-4", AnchorText: "Install the toolchain, then run it to check."},
+    {UUID: "orphaned", Anchor: "p-5", AnchorText: "A paragraph that was removed."},
+    {UUID: "resolved", Anchor: "p-5", AnchorText: "A paragraph that was removed.", ResolvedAt: &resolved},
+  }
+
+  moved := reanchorFeedback(feedback, anchors)
+
+  require.Len(t, moved, 4)
+
+  assert.Equal(t, "moved", moved[0].UUID)
+  assert.Equal(t, "p-2", moved[0].Anchor)
+
+  assert.Equal(t, "selection", moved[1].UUID)
+  assert.Equal(t, "p-3", moved[1].Anchor)
+  assert.Equal(t, anchors[2].text, moved[1].AnchorText)
+
+  assert.Equal(t, "similar", moved[2].UUID)
+  assert.Equal(t, "p-3", moved[2].Anchor)
+
+  assert.Equal(t, "orphaned", moved[3].UUID)
+  assert.Equal(t, "p-5", moved[3].Anchor)
+  assert.True(t, moved[3].Orphaned)
+
+  t.Run("finds the anchor of orphaned feedback again", func(t *testing.T) {
+    orphaned := &model.Feedback{Anchor: "p-5", AnchorText: "A paragraph that was removed.", Orphaned: true}
+    moved := reanchorFeedback([]*model.Feedback{orphaned}, []*feedbackAnchor{{id: "p-9", text: "A paragraph that was removed."}})
+
+    require.Len(t, moved, 1)
+    assert.Equal(t, "p-9", moved[0].Anchor)
+    assert.False(t, moved[0].Orphaned)
+  })
+
+  t.Run("keeps orphaned feedback as it is", func(t *testing.T) {
+    orphaned := &model.Feedback{Anchor: "p-5", AnchorText: "A paragraph that was removed.", Orphaned: true}
+    assert.Empty(t, reanchorFeedback([]*model.Feedback{orphaned}, anchors))
+  })
+}
+
+func (mock *feedbackRepositoryMockAPI) SharedContent(_ context.Context, link string) (string, string, string, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], link)
+  }
+
+  return mock.returns[0].(string), mock.content, mock.password, mock.errors
+}
+
+func (mock *feedbackRepositoryMockAPI) Add(_ context.Context, draftID string, feedback *model.Feedback) (string, error) {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.returns[0], draftID)
+    require.Equal(mock.t, mock.arguments[2], feedback)
+  }
+
+  return mock.returns[1].(string), mock.errors
+}
+
+func TestFeedbackService_Leave(t *testing.T) {
+  ctx := context.TODO()
+  link := "/archive/sharing/6d7f"
+  draftID := uuid.NewString()
+  feedbackID := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    selection := "the toolchain, then"
+    expected := &model.Feedback{
+      Anchor:     "p-2",
+      AnchorText: "Install the toolchain, then run go version.",
+      Selection:  &selection,
+      Reviewer:   "Jane",
+      Comment:    "Which version?",
+    }
+
+    r := &feedbackRepositoryMockAPI{t: t, arguments: []any{ctx, link, expected}, returns: []any{draftID, feedbackID}, content: feedbackContent}
+    creation := &transfer.FeedbackCreation{Anchor: "p-2", Selection: " the toolchain,\n then ", Reviewer: " Jane ", Comment: " Which version? "}
+
+    inserted, err := NewFeedbackService(r, htmlRendererMock{}).Leave(ctx, link, "", creation)
+
+    assert.NoError(t, err)
+    assert.Equal(t, feedbackID, inserted)
+  })
+
+  t.Run("unknown anchor", func(t *testing.T) {
+    r := &feedbackRepositoryMockAPI{returns: []any{draftID}, content: feedbackContent}
+    creation := &transfer.FeedbackCreation{Anchor: "p-9", Reviewer: "Jane", Comment: "Which version?"}
+
+    _, err := NewFeedbackService(r, htmlRendererMock{}).Leave(ctx, link, "", creation)
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    assert.Equal(t, http.StatusNotFound, recorder.Code)
+    assert.False(t, r.called)
+  })
+
+  t.Run("selection outside of the anchor", func(t *testing.T) {
+    r := &feedbackRepositoryMockAPI{returns: []any{draftID}, content: feedbackContent}
+    creation := &transfer.FeedbackCreation{Anchor: "p-1", Selection: "toolchain", Reviewer: "Jane", Comment: "Which version?"}
+
+    _, err := NewFeedbackService(r, htmlRendererMock{}).Leave(ctx, link, "", creation)
+
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+
+  t.Run("protected link without a token", func(t *testing.T) {
+    r := &feedbackRepositoryMockAPI{returns: []any{draftID}, content: feedbackContent, password: "$2a$04$hash"}
+    creation := &transfer.FeedbackCreation{Anchor: "p-1", Reviewer: "Jane", Comment: "Which version?"}
+
+    _, err := NewFeedbackService(r, htmlRendererMock{}).Leave(ctx, link, "", creation)
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+    assert.False(t, r.called)
+  })
+
+  t.Run("blank comment", func(t *testing.T) {
+    r := &feedbackRepositoryMockAPI{}
+    creation := &transfer.FeedbackCreation{Anchor: "p-1", Reviewer: "Jane", Comment: " \n\t "}
+
+    _, err := NewFeedbackService(r, htmlRendererMock{}).Leave(ctx, link, "", creation)
+
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &feedbackRepositoryMockAPI{returns: []any{""}, errors: unexpected}
+    creation := &transfer.FeedbackCreation{Anchor: "p-1", Reviewer: "Jane", Comment: "Which version?"}
+
+    _, err := NewFeedbackService(r, htmlRendererMock{}).Leave(ctx, link, "", creation)
+
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+func (mock *feedbackRepositoryMockAPI) Content(context.Context, string) (string, error) {
+  return mock.content, mock.errors
+}
+
+func (mock *feedbackRepositoryMockAPI) List(_ context.Context, draftID string) ([]*model.Feedback, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], draftID)
+  }
+
+  return mock.returns[0].([]*model.Feedback), mock.errors
+}
+
+func (mock *feedbackRepositoryMockAPI) Reanchor(_ context.Context, feedback []*model.Feedback) error {
+  mock.called = true
+  mock.reanchored = feedback
+  return nil
+}
+
+func TestFeedbackService_Reanchor(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    feedback := []*model.Feedback{
+      {UUID: "a", Anchor: "p-1", AnchorText: "Go is an open source programming language."},
+      {UUID: "b", Anchor: "p-3", AnchorText: "Go is an open source programming language."},
+    }
+
+    r := &feedbackRepositoryMockAPI{t: t, arguments: []any{ctx, id}, returns: []any{feedback}, content: feedbackContent}
+
+    require.NoError(t, NewFeedbackService(r, htmlRendererMock{}).Reanchor(ctx, id))
+    require.Len(t, r.reanchored, 1)
+    assert.Equal(t, "b", r.reanchored[0].UUID)
+    assert.Equal(t, "p-1", r.reanchored[0].Anchor)
+  })
+
+  t.Run("nothing to move", func(t *testing.T) {
+    r := &feedbackRepositoryMockAPI{returns: []any{[]*model.Feedback{}}, content: feedbackContent}
+
+    require.NoError(t, NewFeedbackService(r, htmlRendererMock{}).Reanchor(ctx, id))
+    assert.False(t, r.called)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &feedbackRepositoryMockAPI{errors: unexpected}
+
+    assert.ErrorIs(t, NewFeedbackService(r, htmlRendererMock{}).Reanchor(ctx, id), unexpected)
+    assert.False(t, r.called)
+  })
+}
+
+func (mock *feedbackRepositoryMockAPI) Resolve(_ context.Context, feedbackID string) error {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], feedbackID)
+  }
+
+  return mock.errors
+}
+
+func TestFeedbackService_Resolve(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    r := &feedbackRepositoryMockAPI{t: t, arguments: []any{ctx, id}}
+    assert.NoError(t, NewFeedbackService(r, htmlRendererMock{}).Resolve(ctx, id))
+    assert.True(t, r.called)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &feedbackRepositoryMockAPI{errors: unexpected}
+    assert.ErrorIs(t, NewFeedbackService(r, htmlRendererMock{}).Resolve(ctx, id), unexpected)
+  })
+
+  t.Run("wrong uuid", func(t *testing.T) {
+    r := &feedbackRepositoryMockAPI{}
+    assert.Error(t, NewFeedbackService(r, htmlRendererMock{}).Resolve(ctx, "e4d06ba7-f086-47dc-9f5e"))
+    assert.False(t, r.called)
+  })
+}
@@ -3,14 +3,23 @@ package service
 import (
   "bufio"
   "bytes"
+  "fontseca.dev/model"
   "fontseca.dev/problem"
+  "fontseca.dev/shortcode"
+  "fontseca.dev/transfer"
+  "github.com/gomarkdown/markdown/ast"
+  "github.com/gomarkdown/markdown/parser"
   "github.com/google/uuid"
   "io"
   "log/slog"
   "math"
   "net/http"
   "net/url"
+  "os"
+  "path/filepath"
   "regexp"
+  "slices"
+  "strconv"
   "strings"
   "time"
   "unicode"
@@ -94,6 +103,35 @@ func sanitizeURL(urls ...*string) error {
   return nil
 }
 
+// sanitizeWebURL works like sanitizeURL, but it also requires the URLs
+// to be absolute and to use either the 'http' or 'https' scheme, since
+// they are rendered as links and must not run scripts, as 'javascript:'
+// URLs would.
+func sanitizeWebURL(urls ...*string) error {
+  if err := sanitizeURL(urls...); nil != err {
+    return err
+  }
+
+  for _, u := range urls {
+    if nil == u || "" == *u {
+      continue
+    }
+
+    uri, _ := url.Parse(*u)
+    if ("http" != uri.Scheme && "https" != uri.Scheme) || "" == uri.Host {
+      var p problem.Problem
+      p.Type(problem.TypeUnparseableValue)
+      p.Title("Unprocessable URL format.")
+      p.Status(http.StatusUnprocessableEntity)
+      p.Detail("Only absolute URLs with the 'http' or 'https' scheme are allowed. Please try with a different URL.")
+      p.With("wrong_url", *u)
+      return &p
+    }
+  }
+
+  return nil
+}
+
 // validateUUID checks if a string is a valid UUID format, trimming whitespace
 // and standardizing it if valid. If invalid, it sets the UUID to an empty string
 // and returns an error describing the problem.
@@ -118,8 +156,166 @@ func validateUUID(id *string) error {
   return nil
 }
 
-// approximatePostWordsCount counts the approximate number of words in HTML or text content
-// while ignoring specific HTML elements like <figure> and nested <div> tags.
+// validateArticleFilter checks that the sort mode of filter is known and
+// that its ranges, if any, are not inverted.
+func validateArticleFilter(filter *transfer.ArticleFilter) error {
+  if nil == filter {
+    return nil
+  }
+
+  if "" != filter.Sort && !slices.Contains(transfer.ArticleSorts, filter.Sort) {
+    sorts := make([]string, len(transfer.ArticleSorts))
+
+    for i, sort := range transfer.ArticleSorts {
+      sorts[i] = string(sort)
+    }
+
+    return problem.NewValidation([3]string{"sort", "oneof", strings.Join(sorts, " ")})
+  }
+
+  if 0 > filter.MinReadTime || 0 > filter.MaxReadTime {
+    return problem.NewValidation([3]string{"read_time", "gte", "0"})
+  }
+
+  if 0 < filter.MaxReadTime && filter.MinReadTime > filter.MaxReadTime {
+    return problem.NewValidation([3]string{"max_read_time", "gte", strconv.Itoa(filter.MinReadTime)})
+  }
+
+  if nil != filter.After && nil != filter.Before && !filter.Before.After(*filter.After) {
+    return problem.NewValidation([3]string{"before", "gt", filter.After.Format(time.DateOnly)})
+  }
+
+  return nil
+}
+
+// parseFutureTime parses value, which can be either in the form YYYY-MM-DD
+// or an RFC 3339 timestamp, and checks that it is yet to come. The name of
+// the field is used to describe the problem, if any.
+func parseFutureTime(field, value string) (t time.Time, err error) {
+  value = strings.TrimSpace(value)
+
+  if "" == value {
+    return t, problem.NewMissingParameter(field)
+  }
+
+  t, err = time.Parse(time.RFC3339, value)
+  if nil != err {
+    t, err = time.Parse(time.DateOnly, value)
+  }
+
+  if nil != err {
+    switch {
+    default:
+      return t, problem.NewInternal()
+    case strings.Contains(err.Error(), "cannot parse"):
+      return t, problem.NewValidation([3]string{field, "format", "YYYY-MM-DD"})
+    case strings.Contains(err.Error(), "out of range"):
+      return t, problem.NewValueOutOfRange("date", field, value)
+    }
+  }
+
+  if now := time.Now(); !t.After(now) {
+    return t, problem.NewValidation([3]string{field, "gt", now.UTC().Format(time.RFC3339)})
+  }
+
+  return t, nil
+}
+
+// setWordCount sets the approximate number of words in the content of
+// an article, if any.
+func setWordCount(article *model.Article) {
+  if nil == article {
+    return
+  }
+
+  article.WordCount, _ = approximatePostWordsCount(strings.NewReader(article.Content))
+}
+
+// headingExtensions are the Markdown extensions content is parsed with to
+// assign IDs to headings. The pages render content with the same ones, or
+// the table of contents would not match the anchors.
+const headingExtensions = parser.CommonExtensions | parser.AutoHeadingIDs
+
+// setTableOfContents sets the tree of the headings in the content of an
+// article, if any. Headings deeper than the previous one are nested under
+// it, even if some levels are skipped.
+func setTableOfContents(article *model.Article) {
+  if nil == article {
+    return
+  }
+
+  document := parser.NewWithExtensions(headingExtensions).Parse([]byte(article.Content))
+
+  var (
+    toc   = make([]*model.Heading, 0)
+    stack = make([]*model.Heading, 0, 6)
+  )
+
+  ast.WalkFunc(document, func(node ast.Node, entering bool) ast.WalkStatus {
+    heading, ok := node.(*ast.Heading)
+    if !ok || !entering || heading.IsTitleblock || "" == heading.HeadingID {
+      return ast.GoToNext
+    }
+
+    entry := &model.Heading{
+      ID:       heading.HeadingID,
+      Title:    headingText(heading),
+      Level:    heading.Level,
+      Children: make([]*model.Heading, 0),
+    }
+
+    for 0 < len(stack) && stack[len(stack)-1].Level >= entry.Level {
+      stack = stack[:len(stack)-1]
+    }
+
+    if 0 == len(stack) {
+      toc = append(toc, entry)
+    } else {
+      parent := stack[len(stack)-1]
+      parent.Children = append(parent.Children, entry)
+    }
+
+    stack = append(stack, entry)
+
+    return ast.SkipChildren
+  })
+
+  article.TableOfContents = toc
+}
+
+// headingText is the plain text of a heading, without its formatting.
+func headingText(heading *ast.Heading) string {
+  return plainText(heading)
+}
+
+// plainText is the text of a node and its children, without formatting.
+func plainText(node ast.Node) string {
+  var b strings.Builder
+
+  ast.WalkFunc(node, func(node ast.Node, entering bool) ast.WalkStatus {
+    if !entering {
+      return ast.GoToNext
+    }
+
+    switch n := node.(type) {
+    case *ast.Text:
+      b.Write(n.Literal)
+    case *ast.Code:
+      b.Write(n.Literal)
+    case *ast.Softbreak, *ast.Hardbreak:
+      b.WriteByte(' ')
+    }
+
+    return ast.GoToNext
+  })
+
+  return strings.TrimSpace(b.String())
+}
+
+// approximatePostWordsCount counts the approximate number of words in HTML or text content.
+// Shortcodes count for the text they show, like the content of a callout or the caption of
+// a figure. Raw HTML elements like <figure> and nested <div> tags, which older content has
+// instead of shortcodes, are ignored.
 func approximatePostWordsCount(r io.Reader) (words int, err error) {
   data, err := io.ReadAll(r)
   if nil != err {
@@ -127,6 +323,8 @@ func approximatePostWordsCount(r io.Reader) (words int, err error) {
     return 0, err
   }
 
+  data = []byte(shortcode.Text(string(data)))
+
   var (
     insideFigure bool
     divDepth     int
@@ -237,3 +435,38 @@ func computePostReadingTimeInMinutes(r io.Reader) int {
   }
   return int(math.Ceil(duration.Minutes()))
 }
+
+// writeFileAtomically writes content to a temporary file first, and then
+// renames it to path, so that a partially written file is never served.
+func writeFileAtomically(path string, content []byte) error {
+  if err := os.MkdirAll(filepath.Dir(path), 0755); nil != err {
+    slog.Error(err.Error())
+    return err
+  }
+
+  tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+  if nil != err {
+    slog.Error(err.Error())
+    return err
+  }
+
+  defer os.Remove(tmp.Name())
+
+  if _, err = tmp.Write(content); nil != err {
+    tmp.Close()
+    slog.Error(err.Error())
+    return err
+  }
+
+  if err = tmp.Close(); nil != err {
+    slog.Error(err.Error())
+    return err
+  }
+
+  if err = os.Rename(tmp.Name(), path); nil != err {
+    slog.Error(err.Error())
+    return err
+  }
+
+  return nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
   "bytes"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
   "github.com/stretchr/testify/assert"
   "github.com/stretchr/testify/require"
   "os"
@@ -46,6 +48,36 @@ func Test_sanitizeURL(t *testing.T) {
   })
 }
 
+func Test_sanitizeWebURL(t *testing.T) {
+  t.Run("success", func(t *testing.T) {
+    var urls = []string{
+      "http://fontseca.dev/archive",
+      " https://dev.to/fontseca/article ",
+    }
+
+    for _, url := range urls {
+      err := sanitizeWebURL(&url)
+      assert.NoError(t, err, "URL was: %q", url)
+    }
+  })
+
+  t.Run("errors on non-web urls", func(t *testing.T) {
+    var urls = []string{
+      "javascript:alert(document.cookie)",
+      "JavaScript:alert(1)",
+      "data:text/html,<script>alert(1)</script>",
+      "ftp://fontseca.dev/file",
+      "/archive",
+      "https:///archive",
+    }
+
+    for _, url := range urls {
+      err := sanitizeWebURL(&url)
+      assert.Error(t, err, "URL was: %q", url)
+    }
+  })
+}
+
 func Test_validateUUID(t *testing.T) {
   t.Run("success", func(t *testing.T) {
     var expected = "d0c97bc8-ae21-4f12-8e5f-7c1d97c4538a"
@@ -81,6 +113,41 @@ type textAndWords struct {
   wordsCount int
 }
 
+func Test_validateArticleFilter(t *testing.T) {
+  var (
+    now       = time.Now()
+    yesterday = now.AddDate(0, 0, -1)
+  )
+
+  t.Run("success", func(t *testing.T) {
+    var filters = []*transfer.ArticleFilter{
+      nil,
+      {},
+      {Sort: transfer.SortMostViewed, MinReadTime: 5, MaxReadTime: 10},
+      {Sort: transfer.SortReadingTime, MinReadTime: 5},
+      {After: &yesterday, Before: &now},
+    }
+
+    for _, filter := range filters {
+      assert.NoError(t, validateArticleFilter(filter))
+    }
+  })
+
+  t.Run("fails", func(t *testing.T) {
+    var filters = []*transfer.ArticleFilter{
+      {Sort: "random"},
+      {MinReadTime: -1},
+      {MinReadTime: 10, MaxReadTime: 5},
+      {After: &now, Before: &yesterday},
+      {After: &now, Before: &now},
+    }
+
+    for _, filter := range filters {
+      assert.ErrorContains(t, validateArticleFilter(filter), "does not meet the required validation criteria", "filter was: %+v", filter)
+    }
+  })
+}
+
 func Test_approximatePostWordsCount(t *testing.T) {
   var texts = []textAndWords{
     {
@@ -181,6 +248,18 @@ func Test_approximatePostWordsCount(t *testing.T) {
       "a b c </div> </div> </div>",
       3,
     },
+    { // Counts the text of shortcodes.
+      `Lorem ipsum.
+
+       {{< callout warn >}}
+       Dolor sit amet.
+       {{< /callout >}}
+
+       {{< figure src="lorem.png" alt="Lorem" caption="Consectetur adipiscing." >}}
+
+       {{< youtube dQw4w9WgXcQ >}}`,
+      7,
+    },
   }
 
   for _, text := range texts {
@@ -405,3 +484,43 @@ dolor.`)
     require.NoError(t, handle.Close())
   })
 }
+
+func Test_setTableOfContents(t *testing.T) {
+  article := &model.Article{Content: `
+# Lorem **ipsum**
+
+Dolor sit amet.
+
+## Consectetur
+
+### ` + "`adipiscing`" + ` elit
+
+#### Sed do
+
+## Consectetur
+
+# Tempor
+`}
+
+  setTableOfContents(article)
+
+  toc := article.TableOfContents
+  require.Len(t, toc, 2)
+
+  assert.Equal(t, "lorem-ipsum", toc[0].ID)
+  assert.Equal(t, "Lorem ipsum", toc[0].Title)
+  assert.Equal(t, 1, toc[0].Level)
+  require.Len(t, toc[0].Children, 2)
+
+  assert.Equal(t, "consectetur", toc[0].Children[0].ID)
+  assert.Equal(t, "consectetur-1", toc[0].Children[1].ID)
+
+  require.Len(t, toc[0].Children[0].Children, 1)
+  assert.Equal(t, "adipiscing elit", toc[0].Children[0].Children[0].Title)
+  assert.Equal(t, "sed-do", toc[0].Children[0].Children[0].Children[0].ID)
+
+  assert.Equal(t, "tempor", toc[1].ID)
+  assert.Empty(t, toc[1].Children)
+
+  setTableOfContents(nil)
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+  "crypto"
+  "crypto/rand"
+  "crypto/rsa"
+  "crypto/sha256"
+  "crypto/x509"
+  "encoding/base64"
+  "encoding/pem"
+  "errors"
+  "fmt"
+  "net/http"
+  "slices"
+  "strings"
+  "time"
+)
+
+// signatureMaxSkew is how far the Date header of a signed request can be
+// from the current time before its signature is rejected.
+const signatureMaxSkew = 12 * time.Hour
+
+// ParsePrivateKey parses an RSA private key in PEM format, either PKCS #1
+// or PKCS #8.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+  block, _ := pem.Decode(data)
+  if nil == block {
+    return nil, errors.New("no PEM data found")
+  }
+
+  if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); nil == err {
+    return key, nil
+  }
+
+  key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+  if nil != err {
+    return nil, err
+  }
+
+  rsaKey, ok := key.(*rsa.PrivateKey)
+  if !ok {
+    return nil, errors.New("private key is not an RSA key")
+  }
+
+  return rsaKey, nil
+}
+
+// encodePublicKey encodes an RSA public key in PEM format (PKIX).
+func encodePublicKey(key *rsa.PublicKey) (string, error) {
+  data, err := x509.MarshalPKIXPublicKey(key)
+  if nil != err {
+    return "", err
+  }
+
+  return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: data})), nil
+}
+
+// parsePublicKey parses an RSA public key in PEM format, either PKIX or
+// PKCS #1.
+func parsePublicKey(data string) (*rsa.PublicKey, error) {
+  block, _ := pem.Decode([]byte(data))
+  if nil == block {
+    return nil, errors.New("no PEM data found")
+  }
+
+  if key, err := x509.ParsePKCS1PublicKey(block.Bytes); nil == err {
+    return key, nil
+  }
+
+  key, err := x509.ParsePKIXPublicKey(block.Bytes)
+  if nil != err {
+    return nil, err
+  }
+
+  rsaKey, ok := key.(*rsa.PublicKey)
+  if !ok {
+    return nil, errors.New("public key is not an RSA key")
+  }
+
+  return rsaKey, nil
+}
+
+// digest computes the value of the Digest header of a request body.
+func digest(body []byte) string {
+  sum := sha256.Sum256(body)
+  return "SHA-256=" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// signingString builds the string that is signed for the given headers
+// of a request.
+func signingString(r *http.Request, headers []string) (string, error) {
+  lines := make([]string, 0, len(headers))
+
+  for _, header := range headers {
+    var value string
+
+    switch header {
+    case "(request-target)":
+      value = strings.ToLower(r.Method) + " " + r.URL.RequestURI()
+    case "host":
+      value = r.Host
+      if "" == value {
+        value = r.URL.Host
+      }
+    default:
+      value = r.Header.Get(header)
+    }
+
+    if "" == value {
+      return "", fmt.Errorf("missing header %q", header)
+    }
+
+    lines = append(lines, header+": "+value)
+  }
+
+  return strings.Join(lines, "\n"), nil
+}
+
+// signRequest signs a request with the rsa-sha256 algorithm of the HTTP
+// Signatures draft used across the fediverse. If body is not nil, its
+// digest is signed too.
+func signRequest(r *http.Request, keyID string, key *rsa.PrivateKey, body []byte) error {
+  if "" == r.Header.Get("Date") {
+    r.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+  }
+
+  headers := []string{"(request-target)", "host", "date"}
+
+  if nil != body {
+    r.Header.Set("Digest", digest(body))
+    headers = append(headers, "digest")
+  }
+
+  str, err := signingString(r, headers)
+  if nil != err {
+    return err
+  }
+
+  sum := sha256.Sum256([]byte(str))
+
+  signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+  if nil != err {
+    return err
+  }
+
+  r.Header.Set("Signature", fmt.Sprintf(`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+    keyID,
+    strings.Join(headers, " "),
+    base64.StdEncoding.EncodeToString(signature),
+  ))
+
+  return nil
+}
+
+// parseSignature parses the parameters of a Signature header.
+func parseSignature(header string) map[string]string {
+  params := make(map[string]string)
+
+  for _, param := range strings.Split(header, ",") {
+    name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+    if !ok {
+      continue
+    }
+
+    params[name] = strings.Trim(value, `"`)
+  }
+
+  return params
+}
+
+// verifyRequest verifies the signature of a request signed with
+// signRequest. The public key is resolved from the keyId of the
+// signature with resolve, which also returns the ID of its owner.
+func verifyRequest(r *http.Request, body []byte, resolve func(keyID string) (key *rsa.PublicKey, owner string, err error)) (owner string, err error) {
+  params := parseSignature(r.Header.Get("Signature"))
+
+  keyID, signature := params["keyId"], params["signature"]
+  if "" == keyID || "" == signature {
+    return "", errors.New("missing or malformed signature")
+  }
+
+  if algorithm, ok := params["algorithm"]; ok && "rsa-sha256" != algorithm && "hs2019" != algorithm {
+    return "", fmt.Errorf("unsupported signature algorithm %q", algorithm)
+  }
+
+  headers := strings.Fields(strings.ToLower(params["headers"]))
+  if 0 == len(headers) {
+    headers = []string{"date"}
+  }
+
+  required := []string{"(request-target)", "host", "date"}
+  if nil != body {
+    required = append(required, "digest")
+  }
+
+  for _, header := range required {
+    if !slices.Contains(headers, header) {
+      return "", fmt.Errorf("header %q is not signed", header)
+    }
+  }
+
+  date, err := http.ParseTime(r.Header.Get("Date"))
+  if nil != err {
+    return "", errors.New("missing or malformed date")
+  }
+
+  if skew := time.Since(date); signatureMaxSkew < skew || -signatureMaxSkew > skew {
+    return "", errors.New("signature date is out of range")
+  }
+
+  if nil != body && digest(body) != r.Header.Get("Digest") {
+    return "", errors.New("digest does not match body")
+  }
+
+  str, err := signingString(r, headers)
+  if nil != err {
+    return "", err
+  }
+
+  decoded, err := base64.StdEncoding.DecodeString(signature)
+  if nil != err {
+    return "", errors.New("malformed signature")
+  }
+
+  key, owner, err := resolve(keyID)
+  if nil != err {
+    return "", err
+  }
+
+  sum := sha256.Sum256([]byte(str))
+
+  if err = rsa.VerifyPKCS1v15(key, crypto.SHA256, sum[:], decoded); nil != err {
+    return "", errors.New("signature does not match")
+  }
+
+  return owner, nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "log/slog"
+  "net/http"
+  "strconv"
+  "strings"
+  "time"
+)
+
+type idempotencyRepositoryAPI interface {
+  Reserve(ctx context.Context, key, method, fingerprint string, retention, lease time.Duration) (stored *model.IdempotentResponse, err error)
+  Save(ctx context.Context, response *model.IdempotentResponse) error
+  Release(ctx context.Context, key, method string) error
+}
+
+const (
+  // IdempotencyKeyRetention is how long the response to a request made
+  // with an idempotency key is replayed on its repeats.
+  IdempotencyKeyRetention = 24 * time.Hour
+
+  // IdempotencyKeyLease is how long the first execution of a request
+  // made with an idempotency key holds the key. If it has not completed
+  // by then, because the server stopped or its response could not be
+  // stored, the key is freed for a retry.
+  IdempotencyKeyLease = time.Minute
+
+  idempotencyKeyMaxLen = 255
+)
+
+// IdempotencyService makes the repeats of a request made with the same
+// idempotency key replay the response to its first execution, instead
+// of executing the request again.
+type IdempotencyService struct {
+  r idempotencyRepositoryAPI
+}
+
+func NewIdempotencyService(r idempotencyRepositoryAPI) *IdempotencyService {
+  return &IdempotencyService{r}
+}
+
+// Begin claims key for a request to method. If the request is a repeat,
+// the response to its first execution is returned and the request must
+// not be executed again; otherwise, it is nil and, once executed, the
+// request must be either completed or released.
+//
+// A key can't be reused for a different request to the same method, nor
+// repeated while its first execution is still in progress.
+func (s *IdempotencyService) Begin(ctx context.Context, key, method, fingerprint string) (stored *model.IdempotentResponse, err error) {
+  key = strings.TrimSpace(key)
+
+  switch {
+  case "" == key:
+    return nil, problem.NewValidation([3]string{"Idempotency-Key", "required", ""})
+  case idempotencyKeyMaxLen < len(key):
+    return nil, problem.NewValidation([3]string{"Idempotency-Key", "max", strconv.Itoa(idempotencyKeyMaxLen)})
+  }
+
+  stored, err = s.r.Reserve(ctx, key, method, fingerprint, IdempotencyKeyRetention, IdempotencyKeyLease)
+  if nil != err || nil == stored {
+    return nil, err
+  }
+
+  if fingerprint != stored.Fingerprint {
+    var p problem.Problem
+    p.Type(problem.TypeActionRefused)
+    p.Status(http.StatusUnprocessableEntity)
+    p.Title("Idempotency key reused.")
+    p.Detail("This idempotency key was already used for a different request to this method. Please use a new key.")
+    p.With("idempotency_key", key)
+    p.With("method", method)
+    return nil, &p
+  }
+
+  if 0 == stored.Status {
+    var p problem.Problem
+    p.Type(problem.TypeActionRefused)
+    p.Status(http.StatusConflict)
+    p.Title("Request in progress.")
+    p.Detail("The first request made with this idempotency key is still in progress. Please try again in a minute.")
+    p.With("idempotency_key", key)
+    p.With("method", method)
+    return nil, &p
+  }
+
+  return stored, nil
+}
+
+// Complete stores the response to the first execution of a request, to
+// be replayed on its repeats.
+func (s *IdempotencyService) Complete(ctx context.Context, response *model.IdempotentResponse) error {
+  if nil == response {
+    err := errors.New("nil value for parameter: response")
+    slog.Error(err.Error())
+    return err
+  }
+
+  response.Key = strings.TrimSpace(response.Key)
+
+  return s.r.Save(ctx, response)
+}
+
+// Release frees key when the first execution of a request to method
+// failed, so that the request can be retried with the same key.
+func (s *IdempotencyService) Release(ctx context.Context, key, method string) error {
+  return s.r.Release(ctx, strings.TrimSpace(key), method)
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "strings"
+  "testing"
+  "time"
+)
+
+type idempotencyRepositoryMockAPI struct {
+  idempotencyRepositoryAPI
+  t         *testing.T
+  returns   []any
+  arguments []any
+  errors    error
+  called    bool
+}
+
+func (mock *idempotencyRepositoryMockAPI) Reserve(_ context.Context, key, method, fingerprint string, retention, lease time.Duration) (*model.IdempotentResponse, error) {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], key)
+    require.Equal(mock.t, mock.arguments[2], method)
+    require.Equal(mock.t, mock.arguments[3], fingerprint)
+    require.Equal(mock.t, IdempotencyKeyRetention, retention)
+    require.Equal(mock.t, IdempotencyKeyLease, lease)
+  }
+
+  return mock.returns[0].(*model.IdempotentResponse), mock.errors
+}
+
+func TestIdempotencyService_Begin(t *testing.T) {
+  const (
+    key         = "0d7c6b1e-5d3f-4c1b-9a52-6f1f0f6a2b10"
+    method      = "archive.drafts.start"
+    fingerprint = "6f1f0f6a2b10"
+  )
+
+  ctx := context.TODO()
+
+  status := func(t *testing.T, err error) int {
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    return recorder.Code
+  }
+
+  t.Run("first execution", func(t *testing.T) {
+    r := &idempotencyRepositoryMockAPI{t: t, arguments: []any{ctx, key, method, fingerprint}, returns: []any{(*model.IdempotentResponse)(nil)}}
+
+    stored, err := NewIdempotencyService(r).Begin(ctx, " "+key+" ", method, fingerprint)
+
+    assert.NoError(t, err)
+    assert.Nil(t, stored)
+  })
+
+  t.Run("repeat", func(t *testing.T) {
+    expected := &model.IdempotentResponse{Key: key, Method: method, Fingerprint: fingerprint, Status: http.StatusOK, Body: []byte(`{}`)}
+    r := &idempotencyRepositoryMockAPI{returns: []any{expected}}
+
+    stored, err := NewIdempotencyService(r).Begin(ctx, key, method, fingerprint)
+
+    assert.NoError(t, err)
+    assert.Equal(t, expected, stored)
+  })
+
+  t.Run("key reused for a different request", func(t *testing.T) {
+    r := &idempotencyRepositoryMockAPI{returns: []any{&model.IdempotentResponse{Fingerprint: "b10", Status: http.StatusOK}}}
+
+    stored, err := NewIdempotencyService(r).Begin(ctx, key, method, fingerprint)
+
+    assert.Nil(t, stored)
+    assert.Equal(t, http.StatusUnprocessableEntity, status(t, err))
+  })
+
+  t.Run("first execution in progress", func(t *testing.T) {
+    r := &idempotencyRepositoryMockAPI{returns: []any{&model.IdempotentResponse{Fingerprint: fingerprint}}}
+
+    stored, err := NewIdempotencyService(r).Begin(ctx, key, method, fingerprint)
+
+    assert.Nil(t, stored)
+    assert.Equal(t, http.StatusConflict, status(t, err))
+  })
+
+  t.Run("blank key", func(t *testing.T) {
+    r := &idempotencyRepositoryMockAPI{}
+
+    _, err := NewIdempotencyService(r).Begin(ctx, " \t ", method, fingerprint)
+
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+
+  t.Run("too long a key", func(t *testing.T) {
+    r := &idempotencyRepositoryMockAPI{}
+
+    _, err := NewIdempotencyService(r).Begin(ctx, strings.Repeat("k", 256), method, fingerprint)
+
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &idempotencyRepositoryMockAPI{returns: []any{(*model.IdempotentResponse)(nil)}, errors: unexpected}
+
+    stored, err := NewIdempotencyService(r).Begin(ctx, key, method, fingerprint)
+
+    assert.Nil(t, stored)
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
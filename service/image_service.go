@@ -0,0 +1,253 @@
+package service
+
+import (
+  "bytes"
+  "context"
+  "errors"
+  "fmt"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "golang.org/x/image/draw"
+  "golang.org/x/sync/singleflight"
+  "image"
+  "image/jpeg"
+  "image/png"
+  "io"
+  "log/slog"
+  "os"
+  "path/filepath"
+  "slices"
+  "strconv"
+  "strings"
+)
+
+type mediaServiceAPIForImages interface {
+  Open(ctx context.Context, hash string) (media *model.Media, path string, err error)
+}
+
+// imageEncoders encode the derivatives of images, by format. There is no
+// WebP encoder in the standard library nor in golang.org/x/image, so WebP
+// images are only served as they were uploaded.
+var imageEncoders = map[string]func(w io.Writer, img image.Image) error{
+  "jpeg": func(w io.Writer, img image.Image) error {
+    return jpeg.Encode(w, img, &jpeg.Options{Quality: 82})
+  },
+  "png": png.Encode,
+}
+
+// imageFormats are the formats that can be requested for an image. WebP
+// can only be requested for WebP images at their original size.
+var imageFormats = []string{"jpeg", "png", "webp"}
+
+// ImageService serves the images of the media library resized to the
+// widths in transfer.ImageWidths. Derivatives are generated on their
+// first request and cached on disk in dir, next to their originals'
+// hashes, so they are only generated once. Concurrent requests for the
+// same derivative wait for a single generation of it.
+type ImageService struct {
+  media       mediaServiceAPIForImages
+  dir         string
+  derivations singleflight.Group
+}
+
+func NewImageService(media mediaServiceAPIForImages, dir string) *ImageService {
+  return &ImageService{media: media, dir: dir}
+}
+
+// Derive retrieves the path to an image of the media library, with the
+// given width and format, along with its MIME type.
+//
+// The width is rounded up to the nearest width of transfer.ImageWidths,
+// and images are never enlarged; a zero width keeps the original one. An
+// empty format keeps JPEG and PNG images as they are, and picks JPEG or
+// PNG for the rest, depending on whether they are transparent. WebP is
+// only served when the image was uploaded as such and is not resized;
+// requesting it otherwise is a validation error.
+func (s *ImageService) Derive(ctx context.Context, hash string, width int, format string) (path, mimeType string, err error) {
+  format = strings.ToLower(strings.TrimSpace(format))
+
+  switch {
+  case 0 > width:
+    return "", "", problem.NewValidation([3]string{"w", "gte", "0"})
+  case "" != format && !slices.Contains(imageFormats, format):
+    return "", "", problem.NewValidation([3]string{"fmt", "oneof", strings.Join(imageFormats, " ")})
+  }
+
+  media, original, err := s.media.Open(ctx, hash)
+  if nil != err {
+    return "", "", err
+  }
+
+  if !strings.HasPrefix(media.MIMEType, "image/") || nil == media.Width || nil == media.Height {
+    return "", "", problem.NewNotFound(hash, "image")
+  }
+
+  width = fitImageWidth(width, *media.Width)
+
+  if width == *media.Width && ("" == format || "image/"+format == media.MIMEType) {
+    return original, media.MIMEType, nil
+  }
+
+  if "webp" == format {
+    return "", "", problem.NewValidation([3]string{"fmt", "oneof", "jpeg png"})
+  }
+
+  // Images uploaded before their pixels were limited are too large to be
+  // decoded safely, so they are served as they are.
+  if MaxImagePixels < *media.Width**media.Height {
+    return original, media.MIMEType, nil
+  }
+
+  formats := derivativeFormats(media.MIMEType, format)
+
+  for _, f := range formats {
+    path = s.path(media.Hash, width, f)
+    if _, err := os.Stat(path); nil == err {
+      return path, "image/" + f, nil
+    }
+  }
+
+  key := media.Hash + "-" + strconv.Itoa(width) + "-" + format
+
+  derived, err, _ := s.derivations.Do(key, func() (any, error) {
+    path, mimeType, err := s.derive(media, original, width, formats)
+    return [2]string{path, mimeType}, err
+  })
+
+  if nil != err {
+    return "", "", err
+  }
+
+  return derived.([2]string)[0], derived.([2]string)[1], nil
+}
+
+// derive generates the derivative of an image with the given width, in
+// the first of formats, or in PNG if there are two and the image is
+// transparent.
+func (s *ImageService) derive(media *model.Media, original string, width int, formats []string) (path, mimeType string, err error) {
+  img, err := decodeImage(original)
+  if nil != err {
+    return "", "", err
+  }
+
+  f := formats[0]
+  if 1 < len(formats) && !isOpaque(img) {
+    f = "png"
+  }
+
+  path = s.path(media.Hash, width, f)
+
+  if _, err := os.Stat(path); nil == err {
+    return path, "image/" + f, nil
+  }
+
+  height := max(1, *media.Height*width / *media.Width)
+
+  var b bytes.Buffer
+  if err = imageEncoders[f](&b, resizeImage(img, width, height, "jpeg" == f)); nil != err {
+    return "", "", err
+  }
+
+  if err = writeFileAtomically(path, b.Bytes()); nil != err {
+    return "", "", err
+  }
+
+  return path, "image/" + f, nil
+}
+
+// Purge removes every derivative of the image with hash. Failures are
+// logged because the image itself is already gone.
+func (s *ImageService) Purge(hash string) {
+  derivatives, err := filepath.Glob(filepath.Join(s.dir, hash[:2], hash+"-*"))
+  if nil != err {
+    slog.Error(err.Error())
+    return
+  }
+
+  for _, path := range derivatives {
+    if err = os.Remove(path); nil != err && !errors.Is(err, os.ErrNotExist) {
+      slog.Error("could not remove image derivative",
+        slog.String("path", path),
+        slog.String("error", err.Error()),
+      )
+    }
+  }
+}
+
+// path is the path to a derivative of the image with hash.
+func (s *ImageService) path(hash string, width int, format string) string {
+  return filepath.Join(s.dir, hash[:2], hash+"-"+strconv.Itoa(width)+"."+format)
+}
+
+// fitImageWidth rounds width up to the nearest width of
+// transfer.ImageWidths, without exceeding the original width.
+func fitImageWidth(width, original int) int {
+  if 0 == width {
+    return original
+  }
+
+  for _, w := range transfer.ImageWidths {
+    if w >= width {
+      width = w
+      break
+    }
+  }
+
+  return min(width, original, transfer.ImageWidths[len(transfer.ImageWidths)-1])
+}
+
+// derivativeFormats are the formats a derivative can be encoded in, given
+// the MIME type of its image and the requested format. If there are two,
+// JPEG is used unless the image is transparent.
+func derivativeFormats(mimeType, format string) []string {
+  switch {
+  case "jpeg" == format || "png" == format:
+    return []string{format}
+  case "" == format && "image/jpeg" == mimeType:
+    return []string{"jpeg"}
+  case "" == format && "image/png" == mimeType:
+    return []string{"png"}
+  default:
+    return []string{"jpeg", "png"}
+  }
+}
+
+func decodeImage(path string) (img image.Image, err error) {
+  file, err := os.Open(path)
+  if nil != err {
+    return nil, err
+  }
+
+  defer file.Close()
+
+  img, _, err = image.Decode(file)
+  if nil != err {
+    return nil, fmt.Errorf("could not decode image %s: %w", filepath.Base(path), err)
+  }
+
+  return img, nil
+}
+
+func isOpaque(img image.Image) bool {
+  o, ok := img.(interface{ Opaque() bool })
+  return ok && o.Opaque()
+}
+
+// resizeImage scales img to width×height. Transparent pixels are painted
+// white if the image is going to lose its alpha channel.
+func resizeImage(img image.Image, width, height int, flatten bool) image.Image {
+  var (
+    dst = image.NewRGBA(image.Rect(0, 0, width, height))
+    op  = draw.Src
+  )
+
+  if flatten {
+    draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+    op = draw.Over
+  }
+
+  draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), op, nil)
+
+  return dst
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+  "context"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "image"
+  "os"
+  "path/filepath"
+  "strings"
+  "sync"
+  "testing"
+)
+
+type mediaServiceMockAPIForImages struct {
+  mediaServiceAPIForImages
+  media *model.Media
+  path  string
+}
+
+func (mock *mediaServiceMockAPIForImages) Open(_ context.Context, hash string) (*model.Media, string, error) {
+  if nil == mock.media || mock.media.Hash != hash {
+    return nil, "", problem.NewNotFound(hash, "media")
+  }
+
+  return mock.media, mock.path, nil
+}
+
+func imageMedia(t *testing.T, width, height int) *mediaServiceMockAPIForImages {
+  hash := strings.Repeat("ab", 32)
+  path := filepath.Join(t.TempDir(), hash)
+  require.NoError(t, os.WriteFile(path, pngImage(t, width, height), 0o644))
+
+  return &mediaServiceMockAPIForImages{
+    media: &model.Media{Hash: hash, MIMEType: "image/png", Width: &width, Height: &height},
+    path:  path,
+  }
+}
+
+func TestImageService_Derive(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    m := imageMedia(t, 1000, 500)
+    s := NewImageService(m, t.TempDir())
+
+    path, mimeType, err := s.Derive(ctx, m.media.Hash, 500, "")
+
+    require.NoError(t, err)
+    assert.Equal(t, "image/png", mimeType)
+    assert.Equal(t, s.path(m.media.Hash, 640, "png"), path)
+
+    file, err := os.Open(path)
+    require.NoError(t, err)
+    defer file.Close()
+
+    config, _, err := image.DecodeConfig(file)
+    require.NoError(t, err)
+    assert.Equal(t, 640, config.Width)
+    assert.Equal(t, 320, config.Height)
+  })
+
+  t.Run("converts transparent images to PNG", func(t *testing.T) {
+    m := imageMedia(t, 1000, 500)
+    m.media.MIMEType = "image/gif"
+    s := NewImageService(m, t.TempDir())
+
+    _, mimeType, err := s.Derive(ctx, m.media.Hash, 320, "")
+
+    require.NoError(t, err)
+    assert.Equal(t, "image/png", mimeType)
+  })
+
+  t.Run("serves WebP images as they are", func(t *testing.T) {
+    m := imageMedia(t, 300, 200)
+    m.media.MIMEType = "image/webp"
+
+    path, mimeType, err := NewImageService(m, t.TempDir()).Derive(ctx, m.media.Hash, 0, "webp")
+
+    require.NoError(t, err)
+    assert.Equal(t, "image/webp", mimeType)
+    assert.Equal(t, m.path, path)
+  })
+
+  t.Run("refuses WebP for resized images", func(t *testing.T) {
+    m := imageMedia(t, 1000, 500)
+    m.media.MIMEType = "image/webp"
+    s := NewImageService(m, t.TempDir())
+
+    _, _, err := s.Derive(ctx, m.media.Hash, 320, "webp")
+
+    var p *problem.Problem
+    assert.ErrorAs(t, err, &p)
+    assert.NoDirExists(t, filepath.Join(s.dir, m.media.Hash[:2]))
+  })
+
+  t.Run("does not enlarge images", func(t *testing.T) {
+    m := imageMedia(t, 300, 200)
+    s := NewImageService(m, t.TempDir())
+
+    path, mimeType, err := s.Derive(ctx, m.media.Hash, 1920, "")
+
+    require.NoError(t, err)
+    assert.Equal(t, "image/png", mimeType)
+    assert.Equal(t, m.path, path)
+  })
+
+  t.Run("derives an image once for concurrent requests", func(t *testing.T) {
+    m := imageMedia(t, 1000, 500)
+    s := NewImageService(m, t.TempDir())
+
+    var (
+      wg    sync.WaitGroup
+      paths = make([]string, 8)
+    )
+
+    for i := range paths {
+      wg.Add(1)
+
+      go func() {
+        defer wg.Done()
+        path, _, err := s.Derive(ctx, m.media.Hash, 320, "")
+        assert.NoError(t, err)
+        paths[i] = path
+      }()
+    }
+
+    wg.Wait()
+
+    for _, path := range paths {
+      assert.Equal(t, s.path(m.media.Hash, 320, "png"), path)
+    }
+  })
+
+  t.Run("serves too large images as they are", func(t *testing.T) {
+    m := imageMedia(t, 10, 10)
+    *m.media.Width, *m.media.Height = 20000, 20000
+
+    path, mimeType, err := NewImageService(m, t.TempDir()).Derive(ctx, m.media.Hash, 320, "")
+
+    require.NoError(t, err)
+    assert.Equal(t, "image/png", mimeType)
+    assert.Equal(t, m.path, path)
+  })
+
+  t.Run("wrong width", func(t *testing.T) {
+    m := imageMedia(t, 300, 200)
+    _, _, err := NewImageService(m, t.TempDir()).Derive(ctx, m.media.Hash, -1, "")
+
+    var p *problem.Problem
+    assert.ErrorAs(t, err, &p)
+  })
+
+  t.Run("wrong format", func(t *testing.T) {
+    m := imageMedia(t, 300, 200)
+    _, _, err := NewImageService(m, t.TempDir()).Derive(ctx, m.media.Hash, 320, "gif")
+
+    var p *problem.Problem
+    assert.ErrorAs(t, err, &p)
+  })
+
+  t.Run("not an image", func(t *testing.T) {
+    m := imageMedia(t, 300, 200)
+    m.media.MIMEType, m.media.Width, m.media.Height = "application/pdf", nil, nil
+
+    _, _, err := NewImageService(m, t.TempDir()).Derive(ctx, m.media.Hash, 320, "")
+
+    var p *problem.Problem
+    assert.ErrorAs(t, err, &p)
+  })
+}
+
+func TestImageService_Purge(t *testing.T) {
+  ctx := context.TODO()
+  m := imageMedia(t, 1000, 500)
+  s := NewImageService(m, t.TempDir())
+
+  small, _, err := s.Derive(ctx, m.media.Hash, 320, "")
+  require.NoError(t, err)
+
+  large, _, err := s.Derive(ctx, m.media.Hash, 640, "jpeg")
+  require.NoError(t, err)
+
+  other := filepath.Join(s.dir, m.media.Hash[:2], strings.Repeat("ab", 31)+"cd-320.png")
+  require.NoError(t, os.WriteFile(other, []byte("lorem"), 0o644))
+
+  s.Purge(m.media.Hash)
+
+  assert.NoFileExists(t, small)
+  assert.NoFileExists(t, large)
+  assert.FileExists(t, other)
+}
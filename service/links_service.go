@@ -0,0 +1,371 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/transfer"
+  "golang.org/x/net/html"
+  "io"
+  "log/slog"
+  "net/http"
+  "net/url"
+  "strings"
+  "sync"
+  "time"
+)
+
+type linksRepositoryAPI interface {
+  Sources(ctx context.Context) (sources []*model.LinkSource, err error)
+  Save(ctx context.Context, links []*model.Link) error
+  ListBroken(ctx context.Context) (links []*model.Link, err error)
+}
+
+const linkCheckerUserAgent = "fontseca.dev link checker (+https://fontseca.dev)"
+
+// linkAttributes are the attributes of the HTML elements whose URLs are
+// checked, by element.
+var linkAttributes = map[string]string{
+  "a":      "href",
+  "img":    "src",
+  "iframe": "src",
+  "video":  "src",
+  "source": "src",
+}
+
+// linkResult is the outcome of probing a link.
+type linkResult struct {
+  status int
+  err    error
+}
+
+// broken tells whether a link is dead. A link whose server asks to slow
+// down is not dead, so it is not reported.
+func (r *linkResult) broken() bool {
+  return nil != r.err || (400 <= r.status && http.StatusTooManyRequests != r.status)
+}
+
+// temporary tells whether probing a link again might give a different
+// result.
+func (r *linkResult) temporary() bool {
+  return nil != r.err ||
+    http.StatusRequestTimeout == r.status ||
+    http.StatusTooManyRequests == r.status ||
+    500 <= r.status
+}
+
+// LinksService checks the outbound links of the published articles and
+// of the projects: the links of their rendered content and the URLs of
+// their fields, like the homepage of a project. Only absolute HTTP and
+// HTTPS URLs are checked.
+//
+// Links are probed by at most workers at once, and by at most perHost
+// at once for the same host. Links that fail for a reason that might be
+// temporary are probed again after each of the retries delays.
+type LinksService struct {
+  r        linksRepositoryAPI
+  markdown MarkdownRenderer
+  client   *http.Client
+  retries  []time.Duration
+  workers  int
+  perHost  int
+}
+
+func NewLinksService(r linksRepositoryAPI, markdown MarkdownRenderer) *LinksService {
+  return &LinksService{
+    r:        r,
+    markdown: markdown,
+    client:   &http.Client{Timeout: 15 * time.Second},
+    retries:  []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute},
+    workers:  8,
+    perHost:  2,
+  }
+}
+
+// Check probes every link and stores the results, replacing the ones of
+// the previous check. It returns the number of links checked and how
+// many of them are broken.
+func (s *LinksService) Check(ctx context.Context) (checked, broken int, err error) {
+  sources, err := s.r.Sources(ctx)
+  if nil != err {
+    return 0, 0, err
+  }
+
+  var (
+    links   = make([]*model.Link, 0)
+    urls    = make([]*url.URL, 0)
+    results = make(map[string]*linkResult)
+    hosts   = make(map[string]chan struct{})
+  )
+
+  for _, source := range sources {
+    for _, link := range s.extractLinks(source) {
+      links = append(links, &model.Link{
+        SourceType:  source.Type,
+        SourceID:    source.ID,
+        SourceTitle: source.Title,
+        URL:         link.String(),
+      })
+
+      if _, ok := results[link.String()]; !ok {
+        results[link.String()] = nil
+        urls = append(urls, link)
+      }
+
+      if _, ok := hosts[link.Host]; !ok {
+        hosts[link.Host] = make(chan struct{}, s.perHost)
+      }
+    }
+  }
+
+  var (
+    mu      sync.Mutex
+    wg      sync.WaitGroup
+    workers = make(chan struct{}, s.workers)
+  )
+
+  for _, link := range urls {
+    select {
+    case <-ctx.Done():
+      wg.Wait()
+      return 0, 0, ctx.Err()
+    case workers <- struct{}{}:
+    }
+
+    wg.Add(1)
+
+    go func() {
+      defer wg.Done()
+      defer func() { <-workers }()
+
+      result := s.probe(ctx, link.String(), hosts[link.Host])
+
+      mu.Lock()
+      results[link.String()] = result
+      mu.Unlock()
+    }()
+  }
+
+  wg.Wait()
+
+  if nil != ctx.Err() {
+    return 0, 0, ctx.Err()
+  }
+
+  now := time.Now().UTC()
+
+  for _, link := range links {
+    result := results[link.URL]
+
+    link.Broken = result.broken()
+    link.CheckedAt = now
+
+    if 0 != result.status {
+      link.Status = &result.status
+    }
+
+    if nil != result.err {
+      reason := linkErrorReason(result.err)
+      link.Error = &reason
+    }
+
+    if link.Broken {
+      broken++
+    }
+  }
+
+  if err = s.r.Save(ctx, links); nil != err {
+    return 0, 0, err
+  }
+
+  return len(links), broken, nil
+}
+
+// extractLinks retrieves the distinct links of an article or a project,
+// without their fragments.
+func (s *LinksService) extractLinks(source *model.LinkSource) []*url.URL {
+  var rendered string
+  if nil != source.HTML {
+    rendered = *source.HTML
+  } else {
+    rendered = s.markdown.Render(source.Content)
+  }
+
+  var (
+    links = make([]*url.URL, 0)
+    seen  = make(map[string]bool)
+    add   = func(raw string) {
+      u, err := url.Parse(strings.TrimSpace(raw))
+      if nil != err || ("http" != u.Scheme && "https" != u.Scheme) || "" == u.Host {
+        return
+      }
+
+      u.Fragment, u.RawFragment = "", ""
+
+      if !seen[u.String()] {
+        seen[u.String()] = true
+        links = append(links, u)
+      }
+    }
+  )
+
+  z := html.NewTokenizer(strings.NewReader(rendered))
+
+  for {
+    tt := z.Next()
+    if html.ErrorToken == tt {
+      break
+    }
+
+    if html.StartTagToken != tt && html.SelfClosingTagToken != tt {
+      continue
+    }
+
+    token := z.Token()
+    key, ok := linkAttributes[token.Data]
+    if !ok {
+      continue
+    }
+
+    for _, attr := range token.Attr {
+      if key == attr.Key {
+        add(attr.Val)
+      }
+    }
+  }
+
+  for _, u := range source.URLs {
+    add(u)
+  }
+
+  return links
+}
+
+// probe probes a link until it succeeds, fails for good or runs out of
+// retries, never taking more than one of the slots of host at once.
+func (s *LinksService) probe(ctx context.Context, link string, host chan struct{}) *linkResult {
+  for attempt := 0; ; attempt++ {
+    select {
+    case <-ctx.Done():
+      return &linkResult{err: ctx.Err()}
+    case host <- struct{}{}:
+    }
+
+    result := s.fetch(ctx, link)
+    <-host
+
+    if !result.temporary() || len(s.retries) <= attempt {
+      return result
+    }
+
+    select {
+    case <-ctx.Done():
+      return &linkResult{err: ctx.Err()}
+    case <-time.After(s.retries[attempt]):
+    }
+  }
+}
+
+// fetch requests a link with a HEAD request, falling back to GET when
+// the server fails to answer it, since some servers do not support HEAD.
+func (s *LinksService) fetch(ctx context.Context, link string) *linkResult {
+  result := s.request(ctx, http.MethodHead, link)
+
+  if nil == result.err && 400 <= result.status && http.StatusTooManyRequests != result.status {
+    result = s.request(ctx, http.MethodGet, link)
+  }
+
+  return result
+}
+
+func (s *LinksService) request(ctx context.Context, method, link string) *linkResult {
+  request, err := http.NewRequestWithContext(ctx, method, link, nil)
+  if nil != err {
+    return &linkResult{err: err}
+  }
+
+  request.Header.Set("User-Agent", linkCheckerUserAgent)
+
+  response, err := s.client.Do(request)
+  if nil != err {
+    return &linkResult{err: err}
+  }
+
+  defer response.Body.Close()
+  _, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+  return &linkResult{status: response.StatusCode}
+}
+
+// linkErrorReason describes why a link could not be reached, without
+// repeating its URL.
+func linkErrorReason(err error) string {
+  var urlErr *url.Error
+  if errors.As(err, &urlErr) {
+    err = urlErr.Err
+  }
+
+  reason := err.Error()
+  if 512 < len(reason) {
+    reason = reason[:512]
+  }
+
+  return reason
+}
+
+// Report retrieves the broken links found in the last check, grouped by
+// article or project.
+func (s *LinksService) Report(ctx context.Context) (reports []*transfer.LinkReport, err error) {
+  links, err := s.r.ListBroken(ctx)
+  if nil != err {
+    return nil, err
+  }
+
+  reports = make([]*transfer.LinkReport, 0)
+
+  var report *transfer.LinkReport
+
+  for _, link := range links {
+    if nil == report || link.SourceType != report.Type || link.SourceID != report.ID {
+      report = &transfer.LinkReport{
+        Type:  link.SourceType,
+        ID:    link.SourceID,
+        Title: link.SourceTitle,
+        Links: make([]*transfer.BrokenLink, 0),
+      }
+
+      reports = append(reports, report)
+    }
+
+    report.Links = append(report.Links, &transfer.BrokenLink{
+      URL:       link.URL,
+      Status:    link.Status,
+      Error:     link.Error,
+      CheckedAt: link.CheckedAt,
+    })
+  }
+
+  return reports, nil
+}
+
+// RunChecker calls Check right away and then every interval until ctx is
+// done.
+func (s *LinksService) RunChecker(ctx context.Context, interval time.Duration) {
+  ticker := time.NewTicker(interval)
+  defer ticker.Stop()
+
+  for {
+    checked, broken, err := s.Check(ctx)
+    if nil != err && nil == ctx.Err() {
+      slog.Error("could not check links", slog.String("error", err.Error()))
+    } else if nil == err {
+      slog.Info("checked links", slog.Int("count", checked), slog.Int("broken", broken))
+    }
+
+    select {
+    case <-ctx.Done():
+      return
+    case <-ticker.C:
+    }
+  }
+}
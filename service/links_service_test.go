@@ -0,0 +1,175 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "net/http"
+  "net/http/httptest"
+  "sync"
+  "sync/atomic"
+  "testing"
+  "time"
+)
+
+type linksRepositoryMockAPI struct {
+  linksRepositoryAPI
+  sources []*model.LinkSource
+  links   []*model.Link
+  errors  error
+}
+
+func (mock *linksRepositoryMockAPI) Sources(context.Context) ([]*model.LinkSource, error) {
+  return mock.sources, mock.errors
+}
+
+func (mock *linksRepositoryMockAPI) Save(_ context.Context, links []*model.Link) error {
+  if nil != mock.errors {
+    return mock.errors
+  }
+
+  mock.links = links
+  return nil
+}
+
+func (mock *linksRepositoryMockAPI) ListBroken(context.Context) ([]*model.Link, error) {
+  return mock.links, mock.errors
+}
+
+func newTestLinksService(r linksRepositoryAPI) *LinksService {
+  s := NewLinksService(r, &markdownRendererMock{})
+  s.retries = []time.Duration{time.Millisecond, time.Millisecond}
+  return s
+}
+
+func TestLinksService_Check(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    var flaky atomic.Int32
+
+    mux := http.NewServeMux()
+    mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+    mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusGone) })
+    mux.HandleFunc("/down", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) })
+    mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+      if http.MethodHead == r.Method {
+        w.WriteHeader(http.StatusMethodNotAllowed)
+      }
+    })
+    mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+      if 2 > flaky.Add(1) {
+        w.WriteHeader(http.StatusServiceUnavailable)
+      }
+    })
+
+    server := httptest.NewServer(mux)
+    defer server.Close()
+
+    html := `<p><a href="` + server.URL + `/ok#lorem">Lorem</a> <a href="` + server.URL + `/gone">ipsum</a> <a href="/archive">dolor</a></p>`
+    r := &linksRepositoryMockAPI{sources: []*model.LinkSource{
+      {Type: "article", ID: "1", Title: "Lorem", HTML: &html, URLs: []string{"about:blank"}},
+      {Type: "project", ID: "lorem-ipsum", Title: "Ipsum", Content: `<img src="` + server.URL + `/get-only">`, URLs: []string{server.URL + "/ok", server.URL + "/down", server.URL + "/flaky", "about:blank", "mailto:me@example.com"}},
+    }}
+
+    checked, broken, err := newTestLinksService(r).Check(ctx)
+
+    require.NoError(t, err)
+    assert.Equal(t, 6, checked)
+    assert.Equal(t, 2, broken)
+    require.Len(t, r.links, 6)
+
+    results := make(map[string]*model.Link)
+    for _, link := range r.links {
+      results[link.SourceID+" "+link.URL[len(server.URL):]] = link
+    }
+
+    assert.False(t, results["1 /ok"].Broken)
+    assert.True(t, results["1 /gone"].Broken)
+    require.NotNil(t, results["1 /gone"].Status)
+    assert.Equal(t, http.StatusGone, *results["1 /gone"].Status)
+    assert.False(t, results["lorem-ipsum /get-only"].Broken)
+    assert.False(t, results["lorem-ipsum /ok"].Broken)
+    assert.True(t, results["lorem-ipsum /down"].Broken)
+    assert.False(t, results["lorem-ipsum /flaky"].Broken)
+    assert.Equal(t, "Ipsum", results["lorem-ipsum /ok"].SourceTitle)
+  })
+
+  t.Run("unreachable link", func(t *testing.T) {
+    server := httptest.NewServer(http.NotFoundHandler())
+    server.Close()
+
+    r := &linksRepositoryMockAPI{sources: []*model.LinkSource{{Type: "project", ID: "lorem-ipsum", Title: "Lorem", URLs: []string{server.URL}}}}
+
+    _, broken, err := newTestLinksService(r).Check(ctx)
+
+    require.NoError(t, err)
+    assert.Equal(t, 1, broken)
+    require.Len(t, r.links, 1)
+    assert.Nil(t, r.links[0].Status)
+    require.NotNil(t, r.links[0].Error)
+    assert.NotContains(t, *r.links[0].Error, server.URL)
+  })
+
+  t.Run("limits the requests per host", func(t *testing.T) {
+    var (
+      mu             sync.Mutex
+      inFlight, peak int
+    )
+
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+      mu.Lock()
+      inFlight++
+      peak = max(peak, inFlight)
+      mu.Unlock()
+
+      time.Sleep(20 * time.Millisecond)
+
+      mu.Lock()
+      inFlight--
+      mu.Unlock()
+    }))
+
+    defer server.Close()
+
+    var urls []string
+    for _, path := range []string{"/lorem", "/ipsum", "/dolor", "/sit", "/amet", "/consectetur"} {
+      urls = append(urls, server.URL+path)
+    }
+
+    r := &linksRepositoryMockAPI{sources: []*model.LinkSource{{Type: "project", ID: "lorem-ipsum", Title: "Lorem", URLs: urls}}}
+
+    _, broken, err := newTestLinksService(r).Check(ctx)
+
+    require.NoError(t, err)
+    assert.Zero(t, broken)
+    assert.Equal(t, 2, peak)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    _, _, err := newTestLinksService(&linksRepositoryMockAPI{errors: unexpected}).Check(ctx)
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+func TestLinksService_Report(t *testing.T) {
+  status := http.StatusNotFound
+  r := &linksRepositoryMockAPI{links: []*model.Link{
+    {SourceType: "article", SourceID: "1", SourceTitle: "Lorem", URL: "https://example.com/lorem", Status: &status, Broken: true},
+    {SourceType: "article", SourceID: "1", SourceTitle: "Lorem", URL: "https://example.com/ipsum", Status: &status, Broken: true},
+    {SourceType: "project", SourceID: "lorem-ipsum", SourceTitle: "Ipsum", URL: "https://example.com/lorem", Status: &status, Broken: true},
+  }}
+
+  reports, err := newTestLinksService(r).Report(context.TODO())
+
+  require.NoError(t, err)
+  require.Len(t, reports, 2)
+  assert.Equal(t, "article", reports[0].Type)
+  assert.Len(t, reports[0].Links, 2)
+  assert.Equal(t, "lorem-ipsum", reports[1].ID)
+  assert.Equal(t, "Ipsum", reports[1].Title)
+  assert.Len(t, reports[1].Links, 1)
+}
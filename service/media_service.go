@@ -0,0 +1,245 @@
+package service
+
+import (
+  "bytes"
+  "context"
+  "crypto/sha256"
+  "encoding/hex"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  _ "golang.org/x/image/webp"
+  "image"
+  _ "image/gif"
+  _ "image/jpeg"
+  _ "image/png"
+  "log/slog"
+  "net/http"
+  "os"
+  "path/filepath"
+  "regexp"
+  "slices"
+  "strings"
+)
+
+// MaxMediaSize is the maximum size of a file of the media library.
+const MaxMediaSize = 32 << 20
+
+// MaxImagePixels is the maximum number of pixels of an image of the media
+// library, since images are decoded whole to be resized.
+const MaxImagePixels = 50_000_000
+
+// mediaTypes are the MIME types of the files the media library accepts,
+// as detected from their content. SVG images are left out because they
+// can run scripts.
+var mediaTypes = []string{
+  "image/jpeg",
+  "image/png",
+  "image/gif",
+  "image/webp",
+  "application/pdf",
+  "video/mp4",
+  "video/webm",
+}
+
+var mediaHash = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+type mediaRepositoryAPI interface {
+  Add(ctx context.Context, media *model.Media) error
+  List(ctx context.Context) (files []*model.Media, err error)
+  Get(ctx context.Context, hash string) (media *model.Media, err error)
+  References(ctx context.Context, hash string) (references []string, err error)
+  Remove(ctx context.Context, hash string) error
+}
+
+// MediaService is a high level provider for the media library. Files
+// are stored in dir, content-addressed: every file is named after the
+// SHA-256 hash of its content, under a directory named after the first
+// two characters of the hash.
+type MediaService struct {
+  r           mediaRepositoryAPI
+  dir         string
+  derivatives purger
+}
+
+func NewMediaService(r mediaRepositoryAPI, dir string) *MediaService {
+  return &MediaService{r: r, dir: dir}
+}
+
+// purger removes the files derived from a file of the media library.
+type purger interface {
+  Purge(hash string)
+}
+
+// SetDerivatives sets the purger that removes the derivatives of every
+// removed file, like its resized images. A nil purger disables it.
+func (s *MediaService) SetDerivatives(p purger) {
+  s.derivatives = p
+}
+
+// path is the path to the file of the media library with hash.
+func (s *MediaService) path(hash string) string {
+  return filepath.Join(s.dir, hash[:2], hash)
+}
+
+// Upload stores a new file in the media library. Uploading a file that
+// is already there returns the existing one.
+func (s *MediaService) Upload(ctx context.Context, upload *transfer.MediaUpload) (media *model.Media, err error) {
+  if nil == upload {
+    err = errors.New("nil value for parameter: upload")
+    slog.Error(err.Error())
+    return nil, err
+  }
+
+  upload.Name = strings.TrimSpace(filepath.Base(upload.Name))
+  upload.AltText = strings.TrimSpace(upload.AltText)
+  sanitizeTextWordIntersections(&upload.AltText)
+
+  mimeType, _, _ := strings.Cut(http.DetectContentType(upload.Content), ";")
+
+  switch {
+  case 0 == len(upload.Content):
+    return nil, problem.NewMissingParameter("file")
+  case MaxMediaSize < len(upload.Content):
+    return nil, problem.NewValidation([3]string{"file", "max", "32MiB"})
+  case 256 < len(upload.Name):
+    return nil, problem.NewValidation([3]string{"filename", "max", "256"})
+  case 512 < len(upload.AltText):
+    return nil, problem.NewValidation([3]string{"alt_text", "max", "512"})
+  case !slices.Contains(mediaTypes, mimeType):
+    return nil, problem.NewValidation([3]string{"file", "oneof", strings.Join(mediaTypes, " ")})
+  }
+
+  sum := sha256.Sum256(upload.Content)
+  hash := hex.EncodeToString(sum[:])
+
+  if "" == upload.Name || "." == upload.Name {
+    upload.Name = hash
+  }
+
+  if existing, err := s.r.Get(ctx, hash); nil == err {
+    return existing, nil
+  }
+
+  media = &model.Media{
+    Hash:     hash,
+    Name:     upload.Name,
+    MIMEType: mimeType,
+    Size:     int64(len(upload.Content)),
+  }
+
+  if "" != upload.AltText {
+    media.AltText = &upload.AltText
+  }
+
+  if strings.HasPrefix(mimeType, "image/") {
+    config, _, err := image.DecodeConfig(bytes.NewReader(upload.Content))
+    if nil != err {
+      return nil, problem.NewUnparsableValue("image", "file", upload.Name)
+    }
+
+    if MaxImagePixels < config.Width*config.Height {
+      return nil, problem.NewValidation([3]string{"file", "max", "50MP"})
+    }
+
+    media.Width, media.Height = &config.Width, &config.Height
+  }
+
+  if err = s.write(hash, upload.Content); nil != err {
+    return nil, err
+  }
+
+  if err = s.r.Add(ctx, media); nil != err {
+    return nil, err
+  }
+
+  return s.r.Get(ctx, hash)
+}
+
+// write writes the content of a file to disk, unless it is already
+// there.
+func (s *MediaService) write(hash string, content []byte) error {
+  path := s.path(hash)
+
+  if _, err := os.Stat(path); nil == err {
+    return nil
+  }
+
+  return writeFileAtomically(path, content)
+}
+
+// List retrieves all the files of the media library.
+func (s *MediaService) List(ctx context.Context) (files []*model.Media, err error) {
+  return s.r.List(ctx)
+}
+
+// Get retrieves a file of the media library by its hash.
+func (s *MediaService) Get(ctx context.Context, hash string) (media *model.Media, err error) {
+  if err = validateMediaHash(&hash); nil != err {
+    return nil, err
+  }
+
+  return s.r.Get(ctx, hash)
+}
+
+// Open retrieves a file of the media library along with the path to its
+// content on disk.
+func (s *MediaService) Open(ctx context.Context, hash string) (media *model.Media, path string, err error) {
+  media, err = s.Get(ctx, hash)
+  if nil != err {
+    return nil, "", err
+  }
+
+  return media, s.path(media.Hash), nil
+}
+
+// Remove removes a file from the media library, unless an article, a
+// project or the profile still refers to it.
+func (s *MediaService) Remove(ctx context.Context, hash string) error {
+  if err := validateMediaHash(&hash); nil != err {
+    return err
+  }
+
+  references, err := s.r.References(ctx, hash)
+  if nil != err {
+    return err
+  }
+
+  if 0 < len(references) {
+    var p problem.Problem
+    p.Type(problem.TypeActionRefused)
+    p.Status(http.StatusConflict)
+    p.Title("Could not remove media.")
+    p.Detail("Cannot remove this file because it is still in use. Remove every reference to it and try again.")
+    p.With("hash", hash)
+    p.With("references", references)
+    return &p
+  }
+
+  if err = s.r.Remove(ctx, hash); nil != err {
+    return err
+  }
+
+  if err = os.Remove(s.path(hash)); nil != err && !errors.Is(err, os.ErrNotExist) {
+    slog.Error(err.Error())
+  }
+
+  if nil != s.derivatives {
+    s.derivatives.Purge(hash)
+  }
+
+  return nil
+}
+
+// validateMediaHash checks that hash is a SHA-256 hash in hexadecimal,
+// lowering its case.
+func validateMediaHash(hash *string) error {
+  *hash = strings.ToLower(strings.TrimSpace(*hash))
+
+  if !mediaHash.MatchString(*hash) {
+    return problem.NewUnparsableValue("SHA-256 hash", "hash", *hash)
+  }
+
+  return nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+  "bytes"
+  "context"
+  "encoding/binary"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "hash/crc32"
+  "image"
+  "image/png"
+  "net/http"
+  "net/http/httptest"
+  "os"
+  "path/filepath"
+  "strings"
+  "testing"
+)
+
+type mediaRepositoryMockAPI struct {
+  mediaRepositoryAPI
+  files      map[string]*model.Media
+  references []string
+  errors     error
+}
+
+func (mock *mediaRepositoryMockAPI) Add(_ context.Context, media *model.Media) error {
+  if nil != mock.errors {
+    return mock.errors
+  }
+
+  mock.files[media.Hash] = media
+  return nil
+}
+
+func (mock *mediaRepositoryMockAPI) Get(_ context.Context, hash string) (*model.Media, error) {
+  if media, ok := mock.files[hash]; ok {
+    return media, nil
+  }
+
+  return nil, problem.NewNotFound(hash, "media")
+}
+
+func (mock *mediaRepositoryMockAPI) References(_ context.Context, _ string) ([]string, error) {
+  return mock.references, mock.errors
+}
+
+func (mock *mediaRepositoryMockAPI) Remove(_ context.Context, hash string) error {
+  if nil != mock.errors {
+    return mock.errors
+  }
+
+  delete(mock.files, hash)
+  return nil
+}
+
+func pngImage(t *testing.T, width, height int) []byte {
+  var b bytes.Buffer
+  require.NoError(t, png.Encode(&b, image.NewRGBA(image.Rect(0, 0, width, height))))
+  return b.Bytes()
+}
+
+// pngHeader is a PNG that only has the header of a width×height image,
+// which is enough for its dimensions to be read.
+func pngHeader(width, height int) []byte {
+  ihdr := make([]byte, 17)
+  copy(ihdr, "IHDR")
+  binary.BigEndian.PutUint32(ihdr[4:], uint32(width))
+  binary.BigEndian.PutUint32(ihdr[8:], uint32(height))
+  ihdr[12], ihdr[13] = 8, 6 // 8-bit RGBA
+
+  b := []byte("\x89PNG\r\n\x1a\n")
+  b = binary.BigEndian.AppendUint32(b, 13)
+  b = append(b, ihdr...)
+  return binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestMediaService_Upload(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    r := &mediaRepositoryMockAPI{files: make(map[string]*model.Media)}
+    s := NewMediaService(r, t.TempDir())
+    content := pngImage(t, 32, 16)
+
+    media, err := s.Upload(ctx, &transfer.MediaUpload{Name: "../lorem.png", AltText: " Lorem \n ipsum ", Content: content})
+
+    require.NoError(t, err)
+    assert.Len(t, media.Hash, 64)
+    assert.Equal(t, "lorem.png", media.Name)
+    assert.Equal(t, "image/png", media.MIMEType)
+    assert.Equal(t, int64(len(content)), media.Size)
+    require.NotNil(t, media.Width)
+    require.NotNil(t, media.Height)
+    assert.Equal(t, 32, *media.Width)
+    assert.Equal(t, 16, *media.Height)
+    require.NotNil(t, media.AltText)
+    assert.Equal(t, "Lorem ipsum", *media.AltText)
+
+    stored, err := os.ReadFile(s.path(media.Hash))
+    require.NoError(t, err)
+    assert.Equal(t, content, stored)
+    assert.Equal(t, media.Hash[:2], filepath.Base(filepath.Dir(s.path(media.Hash))))
+  })
+
+  t.Run("returns an already uploaded file", func(t *testing.T) {
+    r := &mediaRepositoryMockAPI{files: make(map[string]*model.Media)}
+    s := NewMediaService(r, t.TempDir())
+    content := pngImage(t, 8, 8)
+
+    first, err := s.Upload(ctx, &transfer.MediaUpload{Name: "lorem.png", Content: content})
+    require.NoError(t, err)
+
+    second, err := s.Upload(ctx, &transfer.MediaUpload{Name: "ipsum.png", Content: content})
+    require.NoError(t, err)
+
+    assert.Same(t, first, second)
+    assert.Len(t, r.files, 1)
+  })
+
+  t.Run("unsupported type", func(t *testing.T) {
+    r := &mediaRepositoryMockAPI{files: make(map[string]*model.Media)}
+    s := NewMediaService(r, t.TempDir())
+
+    _, err := s.Upload(ctx, &transfer.MediaUpload{Name: "lorem.svg", Content: []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`)})
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+    assert.Empty(t, r.files)
+  })
+
+  t.Run("too many pixels", func(t *testing.T) {
+    r := &mediaRepositoryMockAPI{files: make(map[string]*model.Media)}
+    s := NewMediaService(r, t.TempDir())
+
+    _, err := s.Upload(ctx, &transfer.MediaUpload{Name: "lorem.png", Content: pngHeader(20000, 20000)})
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+    assert.Empty(t, r.files)
+  })
+
+  t.Run("empty file", func(t *testing.T) {
+    _, err := NewMediaService(&mediaRepositoryMockAPI{}, t.TempDir()).Upload(ctx, &transfer.MediaUpload{Name: "lorem.png"})
+    assert.ErrorContains(t, err, "file")
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &mediaRepositoryMockAPI{files: make(map[string]*model.Media), errors: unexpected}
+
+    _, err := NewMediaService(r, t.TempDir()).Upload(ctx, &transfer.MediaUpload{Name: "lorem.png", Content: pngImage(t, 8, 8)})
+
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+type purgerMock struct {
+  purged []string
+}
+
+func (mock *purgerMock) Purge(hash string) {
+  mock.purged = append(mock.purged, hash)
+}
+
+func TestMediaService_Remove(t *testing.T) {
+  ctx := context.TODO()
+  hash := strings.Repeat("ab", 32)
+
+  t.Run("success", func(t *testing.T) {
+    r := &mediaRepositoryMockAPI{files: map[string]*model.Media{hash: {Hash: hash}}}
+    s := NewMediaService(r, t.TempDir())
+    require.NoError(t, s.write(hash, []byte("lorem")))
+
+    require.NoError(t, s.Remove(ctx, strings.ToUpper(hash)))
+
+    assert.Empty(t, r.files)
+    assert.NoFileExists(t, s.path(hash))
+  })
+
+  t.Run("purges its derivatives", func(t *testing.T) {
+    r := &mediaRepositoryMockAPI{files: map[string]*model.Media{hash: {Hash: hash}}}
+    derivatives := &purgerMock{}
+    s := NewMediaService(r, t.TempDir())
+    s.SetDerivatives(derivatives)
+    require.NoError(t, s.write(hash, []byte("lorem")))
+
+    require.NoError(t, s.Remove(ctx, hash))
+
+    assert.Equal(t, []string{hash}, derivatives.purged)
+  })
+
+  t.Run("refuses to remove a file in use", func(t *testing.T) {
+    r := &mediaRepositoryMockAPI{files: map[string]*model.Media{hash: {Hash: hash}}, references: []string{"me", "project:lorem-ipsum"}}
+    derivatives := &purgerMock{}
+    s := NewMediaService(r, t.TempDir())
+    s.SetDerivatives(derivatives)
+
+    err := s.Remove(ctx, hash)
+
+    var p *problem.Problem
+    require.ErrorAs(t, err, &p)
+
+    recorder := httptest.NewRecorder()
+    p.Emit(recorder)
+    assert.Equal(t, http.StatusConflict, recorder.Code)
+    assert.Len(t, r.files, 1)
+    assert.Empty(t, derivatives.purged)
+  })
+
+  t.Run("wrong hash", func(t *testing.T) {
+    assert.Error(t, NewMediaService(&mediaRepositoryMockAPI{}, t.TempDir()).Remove(ctx, "lorem"))
+  })
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+  "context"
+  "fmt"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "html"
+  "net/url"
+  "strconv"
+  "strings"
+  "time"
+)
+
+const (
+  // oEmbedWidth is the width of embed cards when it is not limited by
+  // the consumer.
+  oEmbedWidth = 600
+
+  // oEmbedTextHeight is the approximate height of the text of an embed
+  // card, below its image.
+  oEmbedTextHeight = 160
+)
+
+type archiveRepositoryAPIForOEmbed interface {
+  Lookup(ctx context.Context, request *transfer.ArticleRequest) (id string, err error)
+  GetByID(ctx context.Context, articleID string, isDraft bool) (article *model.Article, err error)
+}
+
+type projectsRepositoryAPIForOEmbed interface {
+  GetBySlug(ctx context.Context, slug string) (project *model.Project, err error)
+}
+
+// OEmbedService is an oEmbed provider that resolves the URLs of articles
+// and projects into rich embed cards.
+type OEmbedService struct {
+  archive  archiveRepositoryAPIForOEmbed
+  projects projectsRepositoryAPIForOEmbed
+}
+
+func NewOEmbedService(archive archiveRepositoryAPIForOEmbed, projects projectsRepositoryAPIForOEmbed) *OEmbedService {
+  return &OEmbedService{archive: archive, projects: projects}
+}
+
+// embedCard holds the content of an embed card.
+type embedCard struct {
+  url       string
+  title     string
+  summary   string
+  author    string
+  image     string
+  thumbnail string // an image of 1200×630 pixels
+}
+
+// Resolve resolves the URL of an article, in the form
+// 'https://fontseca.dev/archive/:topic/:year/:month/:slug', or of a
+// project, in the form 'https://fontseca.dev/work/:slug', into an embed
+// card that fits in the requested dimensions.
+func (s *OEmbedService) Resolve(ctx context.Context, request *transfer.OEmbedRequest) (*transfer.OEmbed, error) {
+  if nil == request || "" == strings.TrimSpace(request.URL) {
+    return nil, problem.NewMissingParameter("url")
+  }
+
+  if 0 > request.MaxWidth {
+    return nil, problem.NewValidation([3]string{"maxwidth", "gte", "0"})
+  }
+
+  if 0 > request.MaxHeight {
+    return nil, problem.NewValidation([3]string{"maxheight", "gte", "0"})
+  }
+
+  u, err := url.Parse(strings.TrimSpace(request.URL))
+  if nil != err || ("http" != u.Scheme && "https" != u.Scheme) {
+    return nil, problem.NewUnparsableValue("url", "url", request.URL)
+  }
+
+  if "fontseca.dev" != u.Hostname() && "www.fontseca.dev" != u.Hostname() {
+    return nil, problem.NewNotFound(request.URL, "embeddable resource")
+  }
+
+  var card *embedCard
+
+  segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+
+  switch {
+  default:
+    return nil, problem.NewNotFound(request.URL, "embeddable resource")
+  case 5 == len(segments) && "archive" == segments[0]:
+    card, err = s.article(ctx, segments[1], segments[2], segments[3], segments[4])
+  case 2 == len(segments) && "work" == segments[0]:
+    card, err = s.project(ctx, segments[1])
+  }
+
+  if nil != err {
+    return nil, err
+  }
+
+  return newOEmbed(card, request.MaxWidth, request.MaxHeight), nil
+}
+
+func (s *OEmbedService) article(ctx context.Context, topic, year, month, slug string) (*embedCard, error) {
+  y, yErr := strconv.Atoi(year)
+  m, mErr := strconv.Atoi(month)
+
+  if nil != yErr || nil != mErr {
+    return nil, problem.NewNotFound(slug, "article")
+  }
+
+  id, err := s.archive.Lookup(ctx, &transfer.ArticleRequest{
+    Topic:       topic,
+    Publication: &transfer.Publication{Month: time.Month(m), Year: y},
+    Slug:        slug,
+  })
+
+  if nil != err {
+    return nil, problem.NewNotFound(slug, "article")
+  }
+
+  article, err := s.archive.GetByID(ctx, id, false)
+  if nil != err {
+    return nil, err
+  }
+
+  card := &embedCard{
+    url:       fmt.Sprint("https://fontseca.dev/archive/", topic, "/", y, "/", m, "/", slug),
+    title:     article.Title,
+    summary:   article.Summary,
+    author:    article.Author,
+    image:     article.CoverURL,
+    thumbnail: "https://fontseca.dev/og/archive/" + article.UUID.String(),
+  }
+
+  if "" == card.image || "about:blank" == card.image {
+    card.image = card.thumbnail
+  }
+
+  return card, nil
+}
+
+func (s *OEmbedService) project(ctx context.Context, slug string) (*embedCard, error) {
+  project, err := s.projects.GetBySlug(ctx, slug)
+  if nil != err {
+    return nil, err
+  }
+
+  card := &embedCard{
+    url:       "https://fontseca.dev/work/" + project.Slug,
+    title:     project.Name,
+    summary:   project.Summary,
+    image:     project.FirstImageURL,
+    thumbnail: "https://fontseca.dev/og/work/" + project.Slug,
+  }
+
+  if nil != project.Company {
+    card.author = *project.Company
+  }
+
+  if "" == card.image || "about:blank" == card.image {
+    card.image = card.thumbnail
+  }
+
+  return card, nil
+}
+
+// newOEmbed builds a rich oEmbed response for a card. The image of the
+// card is left out if it does not fit in maxHeight, and so is the
+// thumbnail if it does not fit in maxWidth and maxHeight.
+func newOEmbed(card *embedCard, maxWidth, maxHeight int) *transfer.OEmbed {
+  width := oEmbedWidth
+  if 0 < maxWidth && maxWidth < width {
+    width = maxWidth
+  }
+
+  imageHeight := width * OGImageHeight / OGImageWidth
+  height := imageHeight + oEmbedTextHeight
+
+  if 0 < maxHeight && maxHeight < height {
+    card.image = ""
+    height = min(oEmbedTextHeight, maxHeight)
+  }
+
+  embed := &transfer.OEmbed{
+    Version:      "1.0",
+    Type:         "rich",
+    Title:        card.title,
+    AuthorName:   card.author,
+    ProviderName: "fontseca.dev",
+    ProviderURL:  "https://fontseca.dev/",
+    CacheAge:     86400,
+    Width:        width,
+    Height:       height,
+  }
+
+  if "" != card.author {
+    embed.AuthorURL = "https://fontseca.dev/"
+  }
+
+  if (0 == maxWidth || OGImageWidth <= maxWidth) && (0 == maxHeight || OGImageHeight <= maxHeight) {
+    embed.ThumbnailURL = card.thumbnail
+    embed.ThumbnailWidth = OGImageWidth
+    embed.ThumbnailHeight = OGImageHeight
+  }
+
+  var b strings.Builder
+
+  b.WriteString(fmt.Sprintf(`<blockquote class="fontseca-embed" style="margin:0;max-width:%dpx;border:1px solid #000;font-family:sans-serif;color:#000;background:#fff">`, width))
+  b.WriteString(fmt.Sprintf(`<a href="%s" target="_blank" rel="noopener" style="color:inherit;text-decoration:none">`, html.EscapeString(card.url)))
+
+  if "" != card.image {
+    b.WriteString(fmt.Sprintf(`<img src="%s" alt="" width="%d" height="%d" style="display:block;width:100%%;height:auto;object-fit:cover">`, html.EscapeString(card.image), width, imageHeight))
+  }
+
+  b.WriteString(fmt.Sprintf(`<strong style="display:block;padding:12px 16px 0;font-size:20px">%s</strong></a>`, html.EscapeString(card.title)))
+
+  if "" != card.summary {
+    b.WriteString(fmt.Sprintf(`<p style="margin:8px 16px;font-size:15px">%s</p>`, html.EscapeString(card.summary)))
+  }
+
+  footer := "fontseca.dev"
+  if "" != card.author {
+    footer = card.author + " · fontseca.dev"
+  }
+
+  b.WriteString(fmt.Sprintf(`<footer style="padding:0 16px 12px;font-size:13px">%s</footer></blockquote>`, html.EscapeString(footer)))
+
+  embed.HTML = b.String()
+
+  return embed
+}
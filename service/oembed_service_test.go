@@ -0,0 +1,130 @@
+package service
+
+import (
+  "context"
+  "database/sql"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "testing"
+  "time"
+)
+
+type archiveRepositoryMockAPIForOEmbed struct {
+  archiveRepositoryAPIForOEmbed
+  t         *testing.T
+  returns   []any
+  arguments []any
+  errors    error
+}
+
+func (mock *archiveRepositoryMockAPIForOEmbed) Lookup(_ context.Context, request *transfer.ArticleRequest) (string, error) {
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[0], request)
+  }
+
+  return mock.returns[0].(string), mock.errors
+}
+
+func (mock *archiveRepositoryMockAPIForOEmbed) GetByID(context.Context, string, bool) (*model.Article, error) {
+  return mock.returns[1].(*model.Article), nil
+}
+
+type projectsRepositoryMockAPIForOEmbed struct {
+  projectsRepositoryAPIForOEmbed
+  project *model.Project
+  errors  error
+}
+
+func (mock *projectsRepositoryMockAPIForOEmbed) GetBySlug(context.Context, string) (*model.Project, error) {
+  return mock.project, mock.errors
+}
+
+func TestOEmbedService_Resolve(t *testing.T) {
+  ctx := context.TODO()
+
+  article := &model.Article{
+    UUID:    uuid.New(),
+    Title:   "Lorem <ipsum>",
+    Summary: "Dolor sit amet.",
+    Author:  "Shelby Fontseca",
+  }
+
+  lookup := &transfer.ArticleRequest{
+    Topic:       "development",
+    Publication: &transfer.Publication{Month: time.March, Year: 2024},
+    Slug:        "lorem-ipsum",
+  }
+
+  articleURL := "https://fontseca.dev/archive/development/2024/3/lorem-ipsum"
+
+  t.Run("resolves articles", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForOEmbed{t: t, arguments: []any{lookup}, returns: []any{article.UUID.String(), article}}
+
+    embed, err := NewOEmbedService(r, nil).Resolve(ctx, &transfer.OEmbedRequest{URL: articleURL})
+    require.NoError(t, err)
+
+    assert.Equal(t, "1.0", embed.Version)
+    assert.Equal(t, "rich", embed.Type)
+    assert.Equal(t, article.Title, embed.Title)
+    assert.Equal(t, article.Author, embed.AuthorName)
+    assert.Equal(t, 600, embed.Width)
+    assert.Equal(t, "https://fontseca.dev/og/archive/"+article.UUID.String(), embed.ThumbnailURL)
+    assert.Contains(t, embed.HTML, `href="`+articleURL+`"`)
+    assert.Contains(t, embed.HTML, "Lorem &lt;ipsum&gt;")
+    assert.Contains(t, embed.HTML, `<img src="https://fontseca.dev/og/archive/`)
+  })
+
+  t.Run("resolves projects", func(t *testing.T) {
+    company := "Acme"
+    project := &model.Project{Name: "Lorem", Slug: "lorem", Summary: "Ipsum.", FirstImageURL: "https://example.com/lorem.png", Company: &company}
+
+    embed, err := NewOEmbedService(nil, &projectsRepositoryMockAPIForOEmbed{project: project}).Resolve(ctx, &transfer.OEmbedRequest{URL: "https://www.fontseca.dev/work/lorem/"})
+    require.NoError(t, err)
+
+    assert.Equal(t, project.Name, embed.Title)
+    assert.Equal(t, company, embed.AuthorName)
+    assert.Contains(t, embed.HTML, `<img src="https://example.com/lorem.png"`)
+  })
+
+  t.Run("fits in the maximum dimensions", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForOEmbed{returns: []any{article.UUID.String(), article}}
+
+    embed, err := NewOEmbedService(r, nil).Resolve(ctx, &transfer.OEmbedRequest{URL: articleURL, MaxWidth: 400})
+    require.NoError(t, err)
+    assert.Equal(t, 400, embed.Width)
+    assert.Equal(t, 400*OGImageHeight/OGImageWidth+oEmbedTextHeight, embed.Height)
+    assert.Empty(t, embed.ThumbnailURL)
+
+    embed, err = NewOEmbedService(r, nil).Resolve(ctx, &transfer.OEmbedRequest{URL: articleURL, MaxHeight: 100})
+    require.NoError(t, err)
+    assert.Equal(t, 100, embed.Height)
+    assert.NotContains(t, embed.HTML, "<img")
+  })
+
+  t.Run("not embeddable", func(t *testing.T) {
+    s := NewOEmbedService(nil, nil)
+
+    for _, u := range []string{"https://example.com/work/lorem", "https://fontseca.dev/me", "https://fontseca.dev/archive/development"} {
+      _, err := s.Resolve(ctx, &transfer.OEmbedRequest{URL: u})
+      assert.ErrorContains(t, err, "could not be found")
+    }
+  })
+
+  t.Run("article not found", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForOEmbed{returns: []any{""}, errors: sql.ErrNoRows}
+
+    _, err := NewOEmbedService(r, nil).Resolve(ctx, &transfer.OEmbedRequest{URL: articleURL})
+    assert.ErrorContains(t, err, "could not be found")
+  })
+
+  t.Run("unparsable url", func(t *testing.T) {
+    _, err := NewOEmbedService(nil, nil).Resolve(ctx, &transfer.OEmbedRequest{URL: "ftp://fontseca.dev/work/lorem"})
+
+    var p *problem.Problem
+    assert.ErrorAs(t, err, &p)
+  })
+}
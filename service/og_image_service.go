@@ -0,0 +1,302 @@
+package service
+
+import (
+  "bytes"
+  "context"
+  "crypto/sha256"
+  "encoding/hex"
+  "fmt"
+  "fontseca.dev/model"
+  "golang.org/x/image/font"
+  "golang.org/x/image/font/gofont/gobold"
+  "golang.org/x/image/font/gofont/goregular"
+  "golang.org/x/image/font/opentype"
+  "golang.org/x/image/math/fixed"
+  "image"
+  "image/color"
+  "image/draw"
+  "image/png"
+  "log/slog"
+  "os"
+  "path/filepath"
+  "strconv"
+  "strings"
+  "sync"
+)
+
+const (
+  // OGImageWidth and OGImageHeight are the dimensions of the generated
+  // preview cards, as recommended by most social networks.
+  OGImageWidth  = 1200
+  OGImageHeight = 630
+
+  // ogImageVersion changes whenever the design of the cards changes, so
+  // that cards cached with an older design are generated again.
+  ogImageVersion = "1"
+
+  ogImageMargin = 80
+)
+
+type articlesServiceAPIForOGImages interface {
+  GetByID(ctx context.Context, articleUUID string) (article *model.Article, err error)
+}
+
+type projectsServiceAPIForOGImages interface {
+  GetBySlug(ctx context.Context, slug string) (project *model.Project, err error)
+}
+
+// ogCard holds the text of a preview card.
+type ogCard struct {
+  Kicker string // a short label above the title, like the topic
+  Title  string
+  Footer string
+}
+
+// OGImageService generates the Open Graph preview images of articles and
+// projects. Images are cached on disk in dir, named after the hash of
+// their content, so they are only rendered again when the content changes.
+type OGImageService struct {
+  articles articlesServiceAPIForOGImages
+  projects projectsServiceAPIForOGImages
+  dir      string
+  mu       sync.Mutex
+}
+
+func NewOGImageService(articles articlesServiceAPIForOGImages, projects projectsServiceAPIForOGImages, dir string) *OGImageService {
+  return &OGImageService{
+    articles: articles,
+    projects: projects,
+    dir:      dir,
+  }
+}
+
+// Article retrieves the path to the preview image of a published article.
+func (s *OGImageService) Article(ctx context.Context, id string) (path string, err error) {
+  article, err := s.articles.GetByID(ctx, id)
+  if nil != err {
+    return "", err
+  }
+
+  card := &ogCard{Kicker: "ARCHIVE", Title: article.Title}
+
+  if nil != article.Topic {
+    card.Kicker = strings.ToUpper(article.Topic.Name)
+  }
+
+  footer := make([]string, 0, 2)
+
+  if "" != article.Author {
+    footer = append(footer, article.Author)
+  }
+
+  if 0 < article.ReadTime {
+    footer = append(footer, strconv.Itoa(article.ReadTime)+" min read")
+  }
+
+  card.Footer = strings.Join(footer, " · ")
+
+  return s.render(card)
+}
+
+// Project retrieves the path to the preview image of a project.
+func (s *OGImageService) Project(ctx context.Context, slug string) (path string, err error) {
+  project, err := s.projects.GetBySlug(ctx, slug)
+  if nil != err {
+    return "", err
+  }
+
+  card := &ogCard{Kicker: "WORK", Title: project.Name}
+
+  footer := make([]string, 0, 3)
+
+  if nil != project.Company {
+    footer = append(footer, *project.Company)
+  }
+
+  if nil != project.Language {
+    footer = append(footer, *project.Language)
+  }
+
+  if 0 < project.ReadTime {
+    footer = append(footer, strconv.Itoa(project.ReadTime)+" min read")
+  }
+
+  card.Footer = strings.Join(footer, " · ")
+
+  return s.render(card)
+}
+
+// render renders a card into the cache directory, unless it was already
+// rendered, and returns the path to it.
+func (s *OGImageService) render(card *ogCard) (path string, err error) {
+  sum := sha256.Sum256([]byte(ogImageVersion + "\x00" + card.Kicker + "\x00" + card.Title + "\x00" + card.Footer))
+  path = filepath.Join(s.dir, hex.EncodeToString(sum[:16])+".png")
+
+  if _, err = os.Stat(path); nil == err {
+    return path, nil
+  }
+
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  if _, err = os.Stat(path); nil == err {
+    return path, nil
+  }
+
+  var buf bytes.Buffer
+
+  if err = png.Encode(&buf, drawCard(card)); nil != err {
+    slog.Error(err.Error())
+    return "", err
+  }
+
+  if err = os.MkdirAll(s.dir, 0755); nil != err {
+    slog.Error(err.Error())
+    return "", err
+  }
+
+  // The image is written to a temporary file first, so that a concurrent
+  // reader never gets a partially written one.
+  tmp, err := os.CreateTemp(s.dir, "og-*.tmp")
+  if nil != err {
+    slog.Error(err.Error())
+    return "", err
+  }
+
+  defer os.Remove(tmp.Name())
+
+  if _, err = tmp.Write(buf.Bytes()); nil != err {
+    tmp.Close()
+    slog.Error(err.Error())
+    return "", err
+  }
+
+  if err = tmp.Close(); nil != err {
+    slog.Error(err.Error())
+    return "", err
+  }
+
+  if err = os.Rename(tmp.Name(), path); nil != err {
+    slog.Error(err.Error())
+    return "", err
+  }
+
+  return path, nil
+}
+
+var (
+  ogFontsOnce  sync.Once
+  ogRegular    *opentype.Font
+  ogBold       *opentype.Font
+  ogFontsError error
+)
+
+// ogFace returns a face of one of the bundled Go fonts at size.
+func ogFace(bold bool, size float64) font.Face {
+  ogFontsOnce.Do(func() {
+    if ogRegular, ogFontsError = opentype.Parse(goregular.TTF); nil != ogFontsError {
+      return
+    }
+    ogBold, ogFontsError = opentype.Parse(gobold.TTF)
+  })
+
+  if nil != ogFontsError {
+    panic(fmt.Sprintf("could not parse bundled fonts: %v", ogFontsError))
+  }
+
+  f := ogRegular
+  if bold {
+    f = ogBold
+  }
+
+  face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+  if nil != err {
+    panic(fmt.Sprintf("could not create font face: %v", err))
+  }
+
+  return face
+}
+
+// wrapText breaks text into at most maxLines lines that fit in width.
+// If the text does not fit, the last line ends with an ellipsis.
+func wrapText(face font.Face, text string, width fixed.Int26_6, maxLines int) []string {
+  words := strings.Fields(text)
+  lines := make([]string, 0, maxLines)
+  line := ""
+
+  for i, word := range words {
+    candidate := word
+    if "" != line {
+      candidate = line + " " + word
+    }
+
+    if font.MeasureString(face, candidate) <= width || "" == line {
+      line = candidate
+      continue
+    }
+
+    if len(lines) == maxLines-1 {
+      rest := strings.Join(append([]string{line}, words[i:]...), " ")
+      for "" != rest && font.MeasureString(face, rest+"…") > width {
+        _, size := lastRune(rest)
+        rest = strings.TrimRight(rest[:len(rest)-size], " ")
+      }
+      return append(lines, rest+"…")
+    }
+
+    lines = append(lines, line)
+    line = word
+  }
+
+  if "" != line {
+    lines = append(lines, line)
+  }
+
+  return lines
+}
+
+func lastRune(s string) (r rune, size int) {
+  for i, c := range s {
+    r, size = c, len(s)-i
+  }
+  return r, size
+}
+
+// drawCard draws a preview card: the kicker and the title on a white
+// background, and the footer on a black band with the name of the site.
+func drawCard(card *ogCard) image.Image {
+  img := image.NewRGBA(image.Rect(0, 0, OGImageWidth, OGImageHeight))
+  black, white, gray := image.NewUniform(color.Black), image.NewUniform(color.White), image.NewUniform(color.Gray{Y: 0x70})
+
+  draw.Draw(img, img.Bounds(), white, image.Point{}, draw.Src)
+
+  band := image.Rect(0, OGImageHeight-120, OGImageWidth, OGImageHeight)
+  draw.Draw(img, band, black, image.Point{}, draw.Src)
+
+  text := func(face font.Face, src image.Image, x, y int, s string) {
+    d := font.Drawer{Dst: img, Src: src, Face: face, Dot: fixed.P(x, y)}
+    d.DrawString(s)
+  }
+
+  kicker := ogFace(true, 30)
+  defer kicker.Close()
+  text(kicker, gray, ogImageMargin, ogImageMargin+30, card.Kicker)
+
+  title := ogFace(true, 68)
+  defer title.Close()
+
+  width := fixed.I(OGImageWidth - 2*ogImageMargin)
+  for i, line := range wrapText(title, card.Title, width, 4) {
+    text(title, black, ogImageMargin, ogImageMargin+130+i*84, line)
+  }
+
+  footer := ogFace(false, 30)
+  defer footer.Close()
+  text(footer, white, ogImageMargin, OGImageHeight-50, card.Footer)
+
+  site := ogFace(true, 30)
+  defer site.Close()
+  text(site, white, OGImageWidth-ogImageMargin-font.MeasureString(site, "fontseca.dev").Round(), OGImageHeight-50, "fontseca.dev")
+
+  return img
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "golang.org/x/image/font"
+  "golang.org/x/image/math/fixed"
+  "image/png"
+  "os"
+  "strings"
+  "testing"
+)
+
+type articlesServiceMockForOGImages struct {
+  articlesServiceAPIForOGImages
+  article *model.Article
+  errors  error
+}
+
+func (mock *articlesServiceMockForOGImages) GetByID(context.Context, string) (*model.Article, error) {
+  return mock.article, mock.errors
+}
+
+func TestOGImageService_Article(t *testing.T) {
+  ctx := context.TODO()
+
+  article := &model.Article{
+    Title:    "Lorem ipsum dolor sit amet, consectetur adipiscing elit",
+    Author:   "Shelby Fontseca",
+    ReadTime: 7,
+    Topic:    &model.Topic{ID: "development", Name: "Development"},
+  }
+
+  t.Run("success", func(t *testing.T) {
+    s := NewOGImageService(&articlesServiceMockForOGImages{article: article}, nil, t.TempDir())
+
+    path, err := s.Article(ctx, "")
+    require.NoError(t, err)
+
+    file, err := os.Open(path)
+    require.NoError(t, err)
+    defer file.Close()
+
+    img, err := png.Decode(file)
+    require.NoError(t, err)
+    assert.Equal(t, OGImageWidth, img.Bounds().Dx())
+    assert.Equal(t, OGImageHeight, img.Bounds().Dy())
+  })
+
+  t.Run("reuses cached images until the content changes", func(t *testing.T) {
+    r := &articlesServiceMockForOGImages{article: article}
+    s := NewOGImageService(r, nil, t.TempDir())
+
+    first, err := s.Article(ctx, "")
+    require.NoError(t, err)
+
+    second, err := s.Article(ctx, "")
+    require.NoError(t, err)
+    assert.Equal(t, first, second)
+
+    changed := *article
+    changed.ReadTime = 8
+    r.article = &changed
+
+    third, err := s.Article(ctx, "")
+    require.NoError(t, err)
+    assert.NotEqual(t, first, third)
+  })
+
+  t.Run("gets a service failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    s := NewOGImageService(&articlesServiceMockForOGImages{errors: unexpected}, nil, t.TempDir())
+
+    _, err := s.Article(ctx, "")
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+func TestWrapText(t *testing.T) {
+  face := ogFace(true, 68)
+  defer face.Close()
+
+  width := fixed.I(OGImageWidth - 2*ogImageMargin)
+
+  t.Run("fits in one line", func(t *testing.T) {
+    assert.Equal(t, []string{"Hello, world"}, wrapText(face, "Hello,   world", width, 3))
+  })
+
+  t.Run("truncates long text", func(t *testing.T) {
+    lines := wrapText(face, strings.Repeat("lorem ipsum ", 40), width, 3)
+    require.Len(t, lines, 3)
+    assert.True(t, strings.HasSuffix(lines[2], "…"))
+
+    for _, line := range lines {
+      assert.LessOrEqual(t, font.MeasureString(face, line), width)
+    }
+  })
+}
@@ -13,18 +13,47 @@ import (
 type archiveRepositoryAPIForPatches interface {
   ListPatches(ctx context.Context) (patches []*model.ArticlePatch, err error)
   Revise(ctx context.Context, patchID string, revision *transfer.ArticleRevision) error
-  Share(ctx context.Context, patchID string) (link string, err error)
+  Share(ctx context.Context, patchID string, creation *transfer.ShareableLinkCreation) (link *model.ShareableLink, err error)
   Discard(ctx context.Context, patchID string) error
   Release(ctx context.Context, patchID string) error
+  WorkflowState(ctx context.Context, patchID string) (state string, err error)
 }
 
 // PatchesService is a high level provider for article patches.
 type PatchesService struct {
-  r archiveRepositoryAPIForPatches
+  r         archiveRepositoryAPIForPatches
+  federator federator
+  renderer  renderer
+  workflow  *Workflow
+  feedback  reanchorer
 }
 
 func NewPatchesService(r archiveRepositoryAPIForPatches) *PatchesService {
-  return &PatchesService{r}
+  return &PatchesService{r: r, workflow: DefaultWorkflow()}
+}
+
+// SetFederator sets the federator that shares every released patch
+// with the fediverse. A nil federator disables it.
+func (s *PatchesService) SetFederator(f federator) {
+  s.federator = f
+}
+
+// SetRenderer sets the renderer that renders every article when one of
+// its patches is released. A nil renderer disables it.
+func (s *PatchesService) SetRenderer(r renderer) {
+  s.renderer = r
+}
+
+// SetFeedback sets the reanchorer that moves the feedback on every patch
+// when it is revised. A nil reanchorer disables it.
+func (s *PatchesService) SetFeedback(f reanchorer) {
+  s.feedback = f
+}
+
+// SetWorkflow sets the editorial workflow patches go through before they
+// are released. A nil workflow disables it.
+func (s *PatchesService) SetWorkflow(w *Workflow) {
+  s.workflow = w
 }
 
 // List retrieves all the ongoing article patches.
@@ -52,11 +81,19 @@ func (s *PatchesService) Revise(ctx context.Context, id string, revision *transf
     sanitizeTextWordIntersections(&revision.Title)
   }
 
+  if err := sanitizeWebURL(&revision.CanonicalURL); nil != err {
+    return err
+  }
+
   switch {
   case 0 != len(revision.Title) && 256 < len(revision.Title):
     return problem.NewValidation([3]string{"title", "max", "256"})
   case 0 != len(revision.Content) && 3145728 < len(revision.Content):
     return problem.NewValidation([3]string{"content", "max", "3145728"})
+  case 2048 < len(revision.CanonicalURL):
+    return problem.NewValidation([3]string{"canonical_url", "max", "2048"})
+  case 0 > revision.Version:
+    return problem.NewValidation([3]string{"version", "min", "0"})
   }
 
   if "" != revision.Title || "" != revision.Content {
@@ -79,26 +116,37 @@ func (s *PatchesService) Revise(ctx context.Context, id string, revision *transf
     revision.ReadTime = computePostReadingTimeInMinutes(r)
   }
 
-  return s.r.Revise(ctx, id, revision)
+  if err := s.r.Revise(ctx, id, revision); nil != err {
+    return err
+  }
+
+  reanchor(ctx, s.feedback, id)
+
+  return nil
 }
 
 // Share creates a shareable link for an article patch. Only users
 // with that link can see the progress and provide feedback.
 //
 // A shareable link does not make an article public. This link will
-// eventually expire after a certain amount of time.
-func (s *PatchesService) Share(ctx context.Context, id string) (link string, err error) {
-  if err = validateUUID(&id); nil != err {
-    return "about:blank", err
+// eventually expire after a certain amount of time. A patch can have
+// one link per label, each with its own expiry and password.
+func (s *PatchesService) Share(ctx context.Context, id string, creation *transfer.ShareableLinkCreation) (link *model.ShareableLink, err error) {
+  if nil == creation {
+    err = errors.New("nil value for parameter: creation")
+    slog.Error(err.Error())
+    return nil, err
   }
 
-  link, err = s.r.Share(ctx, id)
+  if err = validateUUID(&id); nil != err {
+    return nil, err
+  }
 
-  if nil != err {
-    return "about:blank", err
+  if err = prepareShareableLink(creation); nil != err {
+    return nil, err
   }
 
-  return link, nil
+  return s.r.Share(ctx, id, creation)
 }
 
 // Discard completely drops an article patch but keeps the original
@@ -113,10 +161,26 @@ func (s *PatchesService) Discard(ctx context.Context, id string) error {
 
 // Release merges a patch into the original article and published the
 // update immediately after merging.
+//
+// If there is a workflow, the patch must be approved first.
 func (s *PatchesService) Release(ctx context.Context, id string) error {
   if err := validateUUID(&id); nil != err {
     return err
   }
 
-  return s.r.Release(ctx, id)
+  if err := checkApproved(ctx, s.workflow, s.r, id, "Could not release patch."); nil != err {
+    return err
+  }
+
+  if err := s.r.Release(ctx, id); nil != err {
+    return err
+  }
+
+  render(ctx, s.renderer, id)
+
+  if nil != s.federator {
+    s.federator.Federate(ctx, id, "Update")
+  }
+
+  return nil
 }
@@ -19,6 +19,15 @@ type archiveRepositoryMockAPIForPatches struct {
   arguments []any
   errors    error
   called    bool
+  state     string // of the workflow; an empty one is approved
+}
+
+func (mock *archiveRepositoryMockAPIForPatches) WorkflowState(context.Context, string) (string, error) {
+  if "" == mock.state {
+    return WorkflowApprovedState, nil
+  }
+
+  return mock.state, nil
 }
 
 func (mock *archiveRepositoryMockAPIForPatches) ListPatches(context.Context) ([]*model.ArticlePatch, error) {
@@ -83,6 +92,15 @@ func TestPatchesService_Revise(t *testing.T) {
     assert.NoError(t, NewPatchesService(r).Revise(ctx, id, dirty))
   })
 
+  t.Run("reanchors the feedback", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForPatches{}
+    f := &reanchorerMock{}
+    s := NewPatchesService(r)
+    s.SetFeedback(f)
+    assert.NoError(t, s.Revise(ctx, id, &transfer.ArticleRevision{Title: "Title"}))
+    assert.Equal(t, []string{id}, f.reanchored)
+  })
+
   t.Run("success: changing title", func(t *testing.T) {
     revision := &transfer.ArticleRevision{
       Title:    "Consectetur-Adipiscing!!... Quis nostrud: ELIT??? +-'\"",
@@ -123,6 +141,18 @@ func TestPatchesService_Revise(t *testing.T) {
     assert.Error(t, NewPatchesService(r).Revise(ctx, "x", &transfer.ArticleRevision{}))
   })
 
+  t.Run("negative version", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForPatches{}
+    assert.Error(t, NewPatchesService(r).Revise(ctx, id, &transfer.ArticleRevision{Version: -1}))
+    assert.False(t, r.called)
+  })
+
+  t.Run("script canonical url", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForPatches{}
+    assert.Error(t, NewPatchesService(r).Revise(ctx, id, &transfer.ArticleRevision{CanonicalURL: "data:text/html,<script>alert(1)</script>"}))
+    assert.False(t, r.called)
+  })
+
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
 
@@ -131,14 +161,15 @@ func TestPatchesService_Revise(t *testing.T) {
   })
 }
 
-func (mock *archiveRepositoryMockAPIForPatches) Share(_ context.Context, patchID string) (string, error) {
+func (mock *archiveRepositoryMockAPIForPatches) Share(_ context.Context, patchID string, creation *transfer.ShareableLinkCreation) (*model.ShareableLink, error) {
   mock.called = true
 
   if nil != mock.t {
     require.Equal(mock.t, mock.arguments[1], patchID)
+    require.Equal(mock.t, mock.arguments[2], creation)
   }
 
-  return mock.returns[0].(string), mock.errors
+  return mock.returns[0].(*model.ShareableLink), mock.errors
 }
 
 func TestPatchesService_Share(t *testing.T) {
@@ -146,32 +177,39 @@ func TestPatchesService_Share(t *testing.T) {
   id := uuid.NewString()
 
   t.Run("success", func(t *testing.T) {
-    expectedLink := "link-to-resource"
+    expectedLink := &model.ShareableLink{Label: "default", Link: "link-to-resource"}
+    expectedCreation := &transfer.ShareableLinkCreation{Label: ShareableLinkDefaultLabel, ExpiresIn: ShareableLinkDefaultExpiry}
 
-    r := &archiveRepositoryMockAPIForPatches{t: t, arguments: []any{ctx, id}, returns: []any{expectedLink}}
-    link, err := NewPatchesService(r).Share(ctx, id)
+    r := &archiveRepositoryMockAPIForPatches{t: t, arguments: []any{ctx, id, expectedCreation}, returns: []any{expectedLink}}
+    link, err := NewPatchesService(r).Share(ctx, id, &transfer.ShareableLinkCreation{Label: " "})
 
     assert.Equal(t, expectedLink, link)
     assert.NoError(t, err)
   })
 
   t.Run("wrong patch uuid", func(t *testing.T) {
-    id = "e4d06ba7-f086-47dc-9f5e"
+    r := &archiveRepositoryMockAPIForPatches{}
+
+    link, err := NewPatchesService(r).Share(ctx, "e4d06ba7-f086-47dc-9f5e", &transfer.ShareableLinkCreation{})
+    require.False(t, r.called)
+    assert.Error(t, err)
+    assert.Nil(t, link)
+  })
 
+  t.Run("too long an expiry", func(t *testing.T) {
     r := &archiveRepositoryMockAPIForPatches{}
 
-    link, err := NewPatchesService(r).Share(ctx, id)
+    _, err := NewPatchesService(r).Share(ctx, id, &transfer.ShareableLinkCreation{ExpiresIn: 91})
     require.False(t, r.called)
     assert.Error(t, err)
-    assert.Equal(t, "about:blank", link)
   })
 
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
 
-    r := &archiveRepositoryMockAPIForPatches{returns: []any{""}, errors: unexpected}
-    link, err := NewPatchesService(r).Share(ctx, uuid.NewString())
-    assert.Equal(t, "about:blank", link)
+    r := &archiveRepositoryMockAPIForPatches{returns: []any{(*model.ShareableLink)(nil)}, errors: unexpected}
+    link, err := NewPatchesService(r).Share(ctx, uuid.NewString(), &transfer.ShareableLinkCreation{})
+    assert.Nil(t, link)
     assert.ErrorIs(t, err, unexpected)
   })
 }
@@ -232,6 +270,25 @@ func TestPatchesService_Release(t *testing.T) {
     assert.NoError(t, NewPatchesService(r).Release(ctx, id))
   })
 
+  t.Run("federates the update", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForPatches{t: t, arguments: []any{ctx, id}}
+    f := &federatorMock{}
+    s := NewPatchesService(r)
+    s.SetFederator(f)
+    assert.NoError(t, s.Release(ctx, id))
+    assert.Equal(t, []string{id + " Update"}, f.federated)
+  })
+
+  t.Run("refuses a patch that is not approved", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForPatches{state: "changes_requested"}
+    f := &federatorMock{}
+    s := NewPatchesService(r)
+    s.SetFederator(f)
+    assert.Error(t, s.Release(ctx, id))
+    assert.False(t, r.called)
+    assert.Empty(t, f.federated)
+  })
+
   t.Run("gets a repository failure", func(t *testing.T) {
     unexpected := errors.New("unexpected error")
 
@@ -262,6 +262,8 @@ func (s *ProjectsService) Update(ctx context.Context, id string, update *transfe
     return problem.NewValidation([3]string{"collection_url", "max", "2048"})
   case 0 != len(update.PlaygroundURL) && 2048 < len(update.PlaygroundURL):
     return problem.NewValidation([3]string{"playground_url", "max", "2048"})
+  case 0 > update.Version:
+    return problem.NewValidation([3]string{"version", "min", "0"})
   }
 
   err := sanitizeURL(
@@ -0,0 +1,146 @@
+package service
+
+import (
+  "context"
+  "fontseca.dev/model"
+  "log/slog"
+)
+
+type archiveRepositoryAPIForRendering interface {
+  GetContent(ctx context.Context, articleID string) (content string, err error)
+  SetRendering(ctx context.Context, articleID string, rendering *model.Rendering) error
+  ListStaleRenderings(ctx context.Context, version, limit int) (ids []string, err error)
+}
+
+// MarkdownRenderer renders the Markdown content of articles into HTML.
+// Version must change whenever the HTML that Render returns does.
+type MarkdownRenderer interface {
+  Render(md string) string
+  Version() int
+}
+
+// renderer renders articles once, when their content changes, and
+// completes them with their renderings when they are retrieved.
+type renderer interface {
+  Render(ctx context.Context, articleID string) error
+  Apply(article *model.Article)
+}
+
+// renderingBatchSize is the number of articles Backfill renders at once.
+const renderingBatchSize = 50
+
+// RenderingService renders the content of articles when they are
+// published, revised or released, and stores the HTML, the table of
+// contents and the word count, so that they are not computed again on
+// every request.
+type RenderingService struct {
+  r        archiveRepositoryAPIForRendering
+  markdown MarkdownRenderer
+}
+
+func NewRenderingService(r archiveRepositoryAPIForRendering, markdown MarkdownRenderer) *RenderingService {
+  return &RenderingService{r: r, markdown: markdown}
+}
+
+// Render renders the content of an article and stores its rendering.
+func (s *RenderingService) Render(ctx context.Context, articleUUID string) error {
+  if err := validateUUID(&articleUUID); nil != err {
+    return err
+  }
+
+  content, err := s.r.GetContent(ctx, articleUUID)
+  if nil != err {
+    return err
+  }
+
+  article := &model.Article{Content: content}
+  setWordCount(article)
+  setTableOfContents(article)
+
+  rendering := &model.Rendering{
+    HTML:            s.markdown.Render(content),
+    TableOfContents: article.TableOfContents,
+    WordCount:       article.WordCount,
+    Version:         s.markdown.Version(),
+  }
+
+  return s.r.SetRendering(ctx, articleUUID, rendering)
+}
+
+// Apply sets the word count and the table of contents of an article from
+// its rendering, if it was made by the current version of the renderer.
+// Otherwise, the rendering is dropped and they are computed.
+func (s *RenderingService) Apply(article *model.Article) {
+  if nil == article {
+    return
+  }
+
+  if nil != article.Rendering && s.markdown.Version() == article.Rendering.Version {
+    article.WordCount = article.Rendering.WordCount
+    article.TableOfContents = article.Rendering.TableOfContents
+    return
+  }
+
+  article.Rendering = nil
+  setWordCount(article)
+  setTableOfContents(article)
+}
+
+// Backfill renders, in batches, every article that has not been rendered
+// yet or was rendered by another version of the renderer, so changing
+// the version invalidates every stored rendering at once. It stops at
+// the first article that cannot be rendered.
+func (s *RenderingService) Backfill(ctx context.Context) (rendered int, err error) {
+  for {
+    if err = ctx.Err(); nil != err {
+      return rendered, err
+    }
+
+    ids, err := s.r.ListStaleRenderings(ctx, s.markdown.Version(), renderingBatchSize)
+    if nil != err {
+      return rendered, err
+    }
+
+    if 0 == len(ids) {
+      return rendered, nil
+    }
+
+    for _, id := range ids {
+      if err = s.Render(ctx, id); nil != err {
+        return rendered, err
+      }
+
+      rendered++
+    }
+  }
+}
+
+// render renders an article with r, if any. The article has been saved
+// by then, so a rendering that fails is only logged and left to Backfill.
+func render(ctx context.Context, r renderer, articleUUID string) {
+  if nil == r {
+    return
+  }
+
+  if err := r.Render(ctx, articleUUID); nil != err {
+    slog.Error("could not render article",
+      slog.String("article_uuid", articleUUID),
+      slog.String("error", err.Error()))
+  }
+}
+
+// applyRendering completes an article with r, if any, or computes its
+// word count and table of contents otherwise.
+func applyRendering(r renderer, article *model.Article) {
+  if nil != r {
+    r.Apply(article)
+    return
+  }
+
+  if nil != article {
+    article.Rendering = nil
+  }
+
+  setWordCount(article)
+  setTableOfContents(article)
+}
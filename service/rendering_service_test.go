@@ -0,0 +1,162 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "testing"
+)
+
+type archiveRepositoryMockAPIForRendering struct {
+  archiveRepositoryAPIForRendering
+  contents   map[string]string
+  renderings map[string]*model.Rendering
+  errors     error
+}
+
+func (mock *archiveRepositoryMockAPIForRendering) GetContent(_ context.Context, articleID string) (content string, err error) {
+  return mock.contents[articleID], mock.errors
+}
+
+func (mock *archiveRepositoryMockAPIForRendering) SetRendering(_ context.Context, articleID string, rendering *model.Rendering) error {
+  if nil != mock.errors {
+    return mock.errors
+  }
+
+  mock.renderings[articleID] = rendering
+  return nil
+}
+
+func (mock *archiveRepositoryMockAPIForRendering) ListStaleRenderings(_ context.Context, version, limit int) (ids []string, err error) {
+  ids = make([]string, 0)
+
+  for id := range mock.contents {
+    if rendering, ok := mock.renderings[id]; (!ok || version != rendering.Version) && len(ids) < limit {
+      ids = append(ids, id)
+    }
+  }
+
+  return ids, mock.errors
+}
+
+type markdownRendererMock struct {
+  version int
+}
+
+func (mock *markdownRendererMock) Render(md string) string {
+  return "<p>" + md + "</p>"
+}
+
+func (mock *markdownRendererMock) Version() int {
+  return mock.version
+}
+
+func TestRenderingService_Render(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.New().String()
+
+  t.Run("success", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForRendering{
+      contents:   map[string]string{id: "# Lorem\n\nIpsum dolor sit amet."},
+      renderings: make(map[string]*model.Rendering),
+    }
+
+    require.NoError(t, NewRenderingService(r, &markdownRendererMock{version: 2}).Render(ctx, id))
+
+    rendering := r.renderings[id]
+    require.NotNil(t, rendering)
+    assert.Equal(t, "<p># Lorem\n\nIpsum dolor sit amet.</p>", rendering.HTML)
+    assert.Equal(t, 5, rendering.WordCount)
+    assert.Equal(t, 2, rendering.Version)
+    require.Len(t, rendering.TableOfContents, 1)
+    assert.Equal(t, "lorem", rendering.TableOfContents[0].ID)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &archiveRepositoryMockAPIForRendering{errors: unexpected}
+    assert.ErrorIs(t, NewRenderingService(r, &markdownRendererMock{}).Render(ctx, id), unexpected)
+  })
+
+  t.Run("wrong uuid", func(t *testing.T) {
+    r := &archiveRepositoryMockAPIForRendering{}
+    assert.Error(t, NewRenderingService(r, &markdownRendererMock{}).Render(ctx, "e4d06ba7-f086-47dc-9f5e"))
+  })
+}
+
+func TestRenderingService_Apply(t *testing.T) {
+  s := NewRenderingService(&archiveRepositoryMockAPIForRendering{}, &markdownRendererMock{version: 2})
+
+  t.Run("uses an up-to-date rendering", func(t *testing.T) {
+    toc := []*model.Heading{{ID: "lorem", Title: "Lorem", Level: 1}}
+    article := &model.Article{
+      Content:   "Lorem ipsum.",
+      Rendering: &model.Rendering{HTML: "<p>Lorem ipsum.</p>", TableOfContents: toc, WordCount: 42, Version: 2},
+    }
+
+    s.Apply(article)
+
+    assert.NotNil(t, article.Rendering)
+    assert.Equal(t, 42, article.WordCount)
+    assert.Equal(t, toc, article.TableOfContents)
+  })
+
+  t.Run("drops a stale rendering", func(t *testing.T) {
+    article := &model.Article{
+      Content:   "Lorem ipsum.",
+      Rendering: &model.Rendering{HTML: "<p>Lorem.</p>", WordCount: 42, Version: 1},
+    }
+
+    s.Apply(article)
+
+    assert.Nil(t, article.Rendering)
+    assert.Equal(t, 2, article.WordCount)
+    assert.Empty(t, article.TableOfContents)
+  })
+
+  t.Run("nil article", func(t *testing.T) {
+    assert.NotPanics(t, func() { s.Apply(nil) })
+  })
+}
+
+func TestRenderingService_Backfill(t *testing.T) {
+  ctx := context.TODO()
+
+  t.Run("success", func(t *testing.T) {
+    var (
+      stale    = uuid.New().String()
+      missing  = uuid.New().String()
+      upToDate = uuid.New().String()
+      current  = &model.Rendering{HTML: "<p>Lorem.</p>", Version: 2}
+    )
+
+    r := &archiveRepositoryMockAPIForRendering{
+      contents: map[string]string{stale: "Lorem.", missing: "Ipsum.", upToDate: "Lorem."},
+      renderings: map[string]*model.Rendering{
+        stale:    {HTML: "<p>Lorem</p>", Version: 1},
+        upToDate: current,
+      },
+    }
+
+    rendered, err := NewRenderingService(r, &markdownRendererMock{version: 2}).Backfill(ctx)
+
+    require.NoError(t, err)
+    assert.Equal(t, 2, rendered)
+    assert.Equal(t, 2, r.renderings[stale].Version)
+    assert.Equal(t, "<p>Ipsum.</p>", r.renderings[missing].HTML)
+    assert.Same(t, current, r.renderings[upToDate])
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &archiveRepositoryMockAPIForRendering{errors: unexpected}
+
+    rendered, err := NewRenderingService(r, &markdownRendererMock{}).Backfill(ctx)
+
+    assert.ErrorIs(t, err, unexpected)
+    assert.Zero(t, rendered)
+  })
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+  "crypto/sha256"
+  "crypto/subtle"
+  "encoding/hex"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "golang.org/x/crypto/bcrypt"
+  "log/slog"
+  "net/http"
+  "strconv"
+  "strings"
+)
+
+const (
+  // ShareableLinkDefaultLabel is the label of the links shared without
+  // one.
+  ShareableLinkDefaultLabel = "default"
+
+  // ShareableLinkDefaultExpiry is the number of days a link lasts when
+  // shared without an expiry.
+  ShareableLinkDefaultExpiry = 7
+
+  // ShareableLinkMaxExpiry is the maximum number of days a link can be
+  // shared, or extended, for at once.
+  ShareableLinkMaxExpiry = 90
+
+  shareableLinkMinPassword = 8
+)
+
+// prepareShareableLink validates creation and fills in its defaults.
+// The password, if any, is replaced by its hash, so that it is never
+// stored as it is.
+func prepareShareableLink(creation *transfer.ShareableLinkCreation) error {
+  creation.Label = strings.TrimSpace(creation.Label)
+  if "" == creation.Label {
+    creation.Label = ShareableLinkDefaultLabel
+  }
+
+  if 0 == creation.ExpiresIn {
+    creation.ExpiresIn = ShareableLinkDefaultExpiry
+  }
+
+  switch {
+  case 64 < len(creation.Label):
+    return problem.NewValidation([3]string{"label", "max", "64"})
+  case 0 > creation.ExpiresIn:
+    return problem.NewValidation([3]string{"expires_in", "min", "1"})
+  case ShareableLinkMaxExpiry < creation.ExpiresIn:
+    return problem.NewValidation([3]string{"expires_in", "max", strconv.Itoa(ShareableLinkMaxExpiry)})
+  case "" == creation.Password:
+    return nil
+  case shareableLinkMinPassword > len(creation.Password):
+    return problem.NewValidation([3]string{"password", "min", strconv.Itoa(shareableLinkMinPassword)})
+  case 72 < len(creation.Password):
+    return problem.NewValidation([3]string{"password", "max", "72"})
+  }
+
+  hash, err := bcrypt.GenerateFromPassword([]byte(creation.Password), bcrypt.DefaultCost)
+  if nil != err {
+    slog.Error(err.Error())
+    return err
+  }
+
+  creation.Password = string(hash)
+
+  return nil
+}
+
+// validateExpiryExtension validates the number of days a link is
+// extended by.
+func validateExpiryExtension(days int) error {
+  switch {
+  case 1 > days:
+    return problem.NewValidation([3]string{"days", "min", "1"})
+  case ShareableLinkMaxExpiry < days:
+    return problem.NewValidation([3]string{"days", "max", strconv.Itoa(ShareableLinkMaxExpiry)})
+  }
+
+  return nil
+}
+
+// shareableLinkToken is the token that proves that whoever has it gave
+// the password of a link. It is derived from the hash of the password,
+// which is salted, so it changes whenever the link is shared again with
+// a password and the readers have to give the new one.
+func shareableLinkToken(passwordHash string) string {
+  sum := sha256.Sum256([]byte("shareable link " + passwordHash))
+  return hex.EncodeToString(sum[:])
+}
+
+// checkShareableLinkToken refuses to go on unless the link is not
+// protected or token is its token.
+func checkShareableLinkToken(link, passwordHash, token string) error {
+  if "" == passwordHash || 1 == subtle.ConstantTimeCompare([]byte(token), []byte(shareableLinkToken(passwordHash))) {
+    return nil
+  }
+
+  p := &problem.Problem{}
+  p.Status(http.StatusUnauthorized)
+  p.Title("Password required.")
+  p.Detail("This shareable link is protected by a password.")
+  p.With("shareable_link", link)
+  return p
+}
+
+// unlockShareableLink checks password against the hash of the password
+// of a link and, if it matches, retrieves the token of the link.
+func unlockShareableLink(link, passwordHash, password string) (token string, err error) {
+  if "" == passwordHash {
+    return "", nil
+  }
+
+  if err = bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)); nil != err {
+    p := &problem.Problem{}
+    p.Status(http.StatusUnauthorized)
+    p.Title("Wrong password.")
+    p.Detail("The password of this shareable link is wrong.")
+    p.With("shareable_link", link)
+    return "", p
+  }
+
+  return shareableLinkToken(passwordHash), nil
+}
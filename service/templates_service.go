@@ -0,0 +1,183 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "fontseca.dev/problem"
+  "fontseca.dev/transfer"
+  "log/slog"
+  "slices"
+  "strings"
+  "time"
+)
+
+type templatesRepositoryAPI interface {
+  Create(ctx context.Context, creation *transfer.TemplateCreation) (id string, err error)
+  List(ctx context.Context) (templates []*model.Template, err error)
+  Get(ctx context.Context, id string) (template *model.Template, err error)
+  Update(ctx context.Context, id string, update *transfer.TemplateUpdate) error
+  Remove(ctx context.Context, id string) error
+}
+
+// TemplatesService is a high level provider for the templates article
+// drafts can be started with.
+type TemplatesService struct {
+  r templatesRepositoryAPI
+}
+
+func NewTemplatesService(r templatesRepositoryAPI) *TemplatesService {
+  return &TemplatesService{r}
+}
+
+// Create adds a new template. It returns the UUID of the template that
+// was created.
+func (s *TemplatesService) Create(ctx context.Context, creation *transfer.TemplateCreation) (id string, err error) {
+  if nil == creation {
+    err = errors.New("nil value for parameter: creation")
+    slog.Error(err.Error())
+    return "", err
+  }
+
+  creation.Name = strings.TrimSpace(creation.Name)
+  creation.TitlePattern = strings.TrimSpace(creation.TitlePattern)
+  creation.Summary = strings.TrimSpace(creation.Summary)
+  creation.Content = strings.TrimSpace(creation.Content)
+  creation.Topic = strings.TrimSpace(creation.Topic)
+  creation.Tags = sanitizeTemplateTags(creation.Tags)
+
+  sanitizeTextWordIntersections(&creation.Name)
+
+  if "" == creation.Name {
+    return "", problem.NewValidation([3]string{"name", "required", ""})
+  }
+
+  if err = validateTemplate(creation.Name, creation.TitlePattern, creation.Summary, creation.Content); nil != err {
+    return "", err
+  }
+
+  return s.r.Create(ctx, creation)
+}
+
+// List retrieves all the templates.
+func (s *TemplatesService) List(ctx context.Context) (templates []*model.Template, err error) {
+  return s.r.List(ctx)
+}
+
+// Get retrieves a template by its UUID.
+func (s *TemplatesService) Get(ctx context.Context, id string) (template *model.Template, err error) {
+  if err = validateUUID(&id); nil != err {
+    return nil, err
+  }
+
+  return s.r.Get(ctx, id)
+}
+
+// Update updates an existing template. Empty fields are kept as they
+// are; the default tags are replaced only if update.Tags is not nil.
+func (s *TemplatesService) Update(ctx context.Context, id string, update *transfer.TemplateUpdate) error {
+  if nil == update {
+    err := errors.New("nil value for parameter: update")
+    slog.Error(err.Error())
+    return err
+  }
+
+  if err := validateUUID(&id); nil != err {
+    return err
+  }
+
+  update.Name = strings.TrimSpace(update.Name)
+  update.TitlePattern = strings.TrimSpace(update.TitlePattern)
+  update.Summary = strings.TrimSpace(update.Summary)
+  update.Content = strings.TrimSpace(update.Content)
+  update.Topic = strings.TrimSpace(update.Topic)
+
+  if nil != update.Tags {
+    update.Tags = sanitizeTemplateTags(update.Tags)
+  }
+
+  sanitizeTextWordIntersections(&update.Name)
+
+  if err := validateTemplate(update.Name, update.TitlePattern, update.Summary, update.Content); nil != err {
+    return err
+  }
+
+  return s.r.Update(ctx, id, update)
+}
+
+// Remove removes a template. The drafts started with it are kept as
+// they are.
+func (s *TemplatesService) Remove(ctx context.Context, id string) error {
+  if err := validateUUID(&id); nil != err {
+    return err
+  }
+
+  return s.r.Remove(ctx, id)
+}
+
+func validateTemplate(name, titlePattern, summary, content string) error {
+  switch {
+  case 64 < len(name):
+    return problem.NewValidation([3]string{"name", "max", "64"})
+  case 256 < len(titlePattern):
+    return problem.NewValidation([3]string{"title_pattern", "max", "256"})
+  case 512 < len(summary):
+    return problem.NewValidation([3]string{"summary", "max", "512"})
+  case 3145728 < len(content):
+    return problem.NewValidation([3]string{"content", "max", "3145728"})
+  }
+
+  return nil
+}
+
+// sanitizeTemplateTags trims the tag IDs of a template and removes the
+// blank and repeated ones, keeping their order.
+func sanitizeTemplateTags(tags []string) []string {
+  sanitized := make([]string, 0, len(tags))
+
+  for _, tag := range tags {
+    tag = strings.TrimSpace(tag)
+    if "" != tag && !slices.Contains(sanitized, tag) {
+      sanitized = append(sanitized, tag)
+    }
+  }
+
+  return sanitized
+}
+
+// expandTitlePattern replaces the placeholders of the title pattern of a
+// template: '{title}' with title and '{date}' with the date of now.
+func expandTitlePattern(pattern, title string, now time.Time) string {
+  if "" == pattern {
+    return title
+  }
+
+  return strings.NewReplacer(
+    "{title}", title,
+    "{date}", now.Format(time.DateOnly),
+  ).Replace(pattern)
+}
+
+// templater provides the templates article drafts are started with.
+type templater interface {
+  Get(ctx context.Context, id string) (template *model.Template, err error)
+}
+
+// seedFromTemplate fills creation with the skeleton of template. The
+// summary and content of creation, if any, take precedence over those of
+// the template.
+func seedFromTemplate(creation *transfer.ArticleCreation, template *model.Template, now time.Time) {
+  creation.Title = strings.TrimSpace(expandTitlePattern(template.TitlePattern, strings.TrimSpace(creation.Title), now))
+
+  if "" == strings.TrimSpace(creation.Summary) && nil != template.Summary {
+    creation.Summary = *template.Summary
+  }
+
+  if "" == strings.TrimSpace(creation.Content) && nil != template.Content {
+    creation.Content = *template.Content
+  }
+
+  if nil != template.TopicID {
+    creation.Topic = *template.TopicID
+  }
+}
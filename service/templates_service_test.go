@@ -0,0 +1,173 @@
+package service
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/transfer"
+  "github.com/google/uuid"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "strings"
+  "testing"
+  "time"
+)
+
+type templatesRepositoryMockAPI struct {
+  templatesRepositoryAPI
+  t         *testing.T
+  arguments []any
+  returns   []any
+  errors    error
+  called    bool
+}
+
+func (mock *templatesRepositoryMockAPI) Create(_ context.Context, creation *transfer.TemplateCreation) (string, error) {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], creation)
+  }
+
+  return mock.returns[0].(string), mock.errors
+}
+
+func TestTemplatesService_Create(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+  creation := &transfer.TemplateCreation{
+    Name:         "Post-mortem",
+    TitlePattern: "Post-mortem: {title}",
+    Summary:      "What happened, why and what we will do about it.",
+    Content:      "## Timeline",
+    Topic:        "reliability",
+    Tags:         []string{"incident", "postgres"},
+  }
+
+  t.Run("success", func(t *testing.T) {
+    dirty := &transfer.TemplateCreation{
+      Name:         " \n\t " + creation.Name + " \n\t ",
+      TitlePattern: " " + creation.TitlePattern + " ",
+      Summary:      creation.Summary + " \n",
+      Content:      " \n" + creation.Content,
+      Topic:        " " + creation.Topic,
+      Tags:         []string{" incident ", "", "postgres", "incident"},
+    }
+
+    r := &templatesRepositoryMockAPI{t: t, arguments: []any{ctx, creation}, returns: []any{id}}
+    insertedID, err := NewTemplatesService(r).Create(ctx, dirty)
+
+    assert.NoError(t, err)
+    assert.Equal(t, id, insertedID)
+  })
+
+  t.Run("blank name", func(t *testing.T) {
+    r := &templatesRepositoryMockAPI{}
+    _, err := NewTemplatesService(r).Create(ctx, &transfer.TemplateCreation{Name: " \n\t "})
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+
+  t.Run("too long a title pattern", func(t *testing.T) {
+    r := &templatesRepositoryMockAPI{}
+    _, err := NewTemplatesService(r).Create(ctx, &transfer.TemplateCreation{Name: "Book notes", TitlePattern: strings.Repeat("x", 257)})
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &templatesRepositoryMockAPI{returns: []any{""}, errors: unexpected}
+    _, err := NewTemplatesService(r).Create(ctx, &transfer.TemplateCreation{Name: "Book notes"})
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+func (mock *templatesRepositoryMockAPI) Update(_ context.Context, id string, update *transfer.TemplateUpdate) error {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], id)
+    require.Equal(mock.t, mock.arguments[2], update)
+  }
+
+  return mock.errors
+}
+
+func TestTemplatesService_Update(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    update := &transfer.TemplateUpdate{Name: "Book notes", Tags: []string{"books"}}
+    r := &templatesRepositoryMockAPI{t: t, arguments: []any{ctx, id, update}}
+
+    err := NewTemplatesService(r).Update(ctx, " "+id+" ", &transfer.TemplateUpdate{Name: " Book notes ", Tags: []string{"books", " books"}})
+
+    assert.NoError(t, err)
+  })
+
+  t.Run("keeps the tags", func(t *testing.T) {
+    update := &transfer.TemplateUpdate{Summary: "Notes."}
+    r := &templatesRepositoryMockAPI{t: t, arguments: []any{ctx, id, update}}
+
+    err := NewTemplatesService(r).Update(ctx, id, &transfer.TemplateUpdate{Summary: "Notes. "})
+
+    assert.NoError(t, err)
+    assert.Nil(t, update.Tags)
+  })
+
+  t.Run("clears the tags", func(t *testing.T) {
+    update := &transfer.TemplateUpdate{Tags: []string{}}
+    r := &templatesRepositoryMockAPI{t: t, arguments: []any{ctx, id, update}}
+
+    assert.NoError(t, NewTemplatesService(r).Update(ctx, id, &transfer.TemplateUpdate{Tags: []string{" "}}))
+  })
+
+  t.Run("wrong template uuid", func(t *testing.T) {
+    r := &templatesRepositoryMockAPI{}
+    err := NewTemplatesService(r).Update(ctx, "e4d06ba7-f086-47dc-9f5e", &transfer.TemplateUpdate{})
+    assert.Error(t, err)
+    assert.False(t, r.called)
+  })
+
+  t.Run("gets a repository failure", func(t *testing.T) {
+    unexpected := errors.New("unexpected error")
+    r := &templatesRepositoryMockAPI{errors: unexpected}
+    err := NewTemplatesService(r).Update(ctx, id, &transfer.TemplateUpdate{})
+    assert.ErrorIs(t, err, unexpected)
+  })
+}
+
+func (mock *templatesRepositoryMockAPI) Remove(_ context.Context, id string) error {
+  mock.called = true
+
+  if nil != mock.t {
+    require.Equal(mock.t, mock.arguments[1], id)
+  }
+
+  return mock.errors
+}
+
+func TestTemplatesService_Remove(t *testing.T) {
+  ctx := context.TODO()
+  id := uuid.NewString()
+
+  t.Run("success", func(t *testing.T) {
+    r := &templatesRepositoryMockAPI{t: t, arguments: []any{ctx, id}}
+    assert.NoError(t, NewTemplatesService(r).Remove(ctx, id))
+  })
+
+  t.Run("wrong template uuid", func(t *testing.T) {
+    r := &templatesRepositoryMockAPI{}
+    assert.Error(t, NewTemplatesService(r).Remove(ctx, "e4d06ba7-f086-47dc-9f5e"))
+    assert.False(t, r.called)
+  })
+}
+
+func TestExpandTitlePattern(t *testing.T) {
+  now := time.Date(2026, time.October, 18, 9, 30, 0, 0, time.UTC)
+
+  assert.Equal(t, "Post-mortem: Outage", expandTitlePattern("Post-mortem: {title}", "Outage", now))
+  assert.Equal(t, "Release notes 2026-10-18", expandTitlePattern("Release notes {date}", "Outage", now))
+  assert.Equal(t, "Outage", expandTitlePattern("", "Outage", now))
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+  "context"
+  "fmt"
+  "fontseca.dev/problem"
+  "net/http"
+  "regexp"
+  "slices"
+  "sort"
+  "strings"
+)
+
+const (
+  // WorkflowInitialState is the state of every new draft and patch.
+  WorkflowInitialState = "writing"
+
+  // WorkflowApprovedState is the only state from which a draft can be
+  // published and a patch can be released.
+  WorkflowApprovedState = "approved"
+)
+
+var workflowStateRegexp = regexp.MustCompile(`^[a-z][a-z_]{0,31}$`)
+
+// Workflow is the set of states a draft or a patch goes through before
+// it is published or released, and of the transitions allowed between
+// them.
+type Workflow struct {
+  transitions map[string][]string
+}
+
+// DefaultWorkflow is the workflow used when none is configured: a draft
+// is written, reviewed and either approved or sent back for changes.
+func DefaultWorkflow() *Workflow {
+  return &Workflow{
+    transitions: map[string][]string{
+      WorkflowInitialState:  {"in_review"},
+      "in_review":           {"changes_requested", WorkflowApprovedState},
+      "changes_requested":   {"in_review"},
+      WorkflowApprovedState: {"changes_requested"},
+    },
+  }
+}
+
+// ParseWorkflow parses a workflow from a comma-separated list of
+// transitions in the form 'from:to', like 'writing:in_review,in_review:approved'.
+//
+// States are lowercase words separated by underscores. The approved
+// state must be reachable from the initial one, otherwise nothing could
+// ever be published.
+func ParseWorkflow(spec string) (*Workflow, error) {
+  w := &Workflow{transitions: make(map[string][]string)}
+
+  for _, transition := range strings.Split(spec, ",") {
+    transition = strings.TrimSpace(transition)
+    if "" == transition {
+      continue
+    }
+
+    from, to, ok := strings.Cut(transition, ":")
+    from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+
+    switch {
+    case !ok:
+      return nil, fmt.Errorf("transition %q is not in the form 'from:to'", transition)
+    case !workflowStateRegexp.MatchString(from):
+      return nil, fmt.Errorf("invalid workflow state %q", from)
+    case !workflowStateRegexp.MatchString(to):
+      return nil, fmt.Errorf("invalid workflow state %q", to)
+    case from == to:
+      return nil, fmt.Errorf("transition %q does not change the state", transition)
+    }
+
+    if !slices.Contains(w.transitions[from], to) {
+      w.transitions[from] = append(w.transitions[from], to)
+    }
+  }
+
+  if !w.reachable(WorkflowInitialState, WorkflowApprovedState) {
+    return nil, fmt.Errorf("state %q is not reachable from %q", WorkflowApprovedState, WorkflowInitialState)
+  }
+
+  return w, nil
+}
+
+// reachable tells whether the state to can be reached from the state
+// from through any number of transitions.
+func (w *Workflow) reachable(from, to string) bool {
+  var (
+    visited = map[string]bool{from: true}
+    pending = []string{from}
+  )
+
+  for 0 < len(pending) {
+    state := pending[0]
+    pending = pending[1:]
+
+    if to == state {
+      return true
+    }
+
+    for _, next := range w.transitions[state] {
+      if !visited[next] {
+        visited[next] = true
+        pending = append(pending, next)
+      }
+    }
+  }
+
+  return false
+}
+
+// allows tells whether a draft or a patch can move from one state to
+// another.
+func (w *Workflow) allows(from, to string) bool {
+  return slices.Contains(w.transitions[from], to)
+}
+
+// States retrieves every state of the workflow, sorted by name.
+func (w *Workflow) States() []string {
+  seen := map[string]bool{WorkflowInitialState: true}
+
+  for from, targets := range w.transitions {
+    seen[from] = true
+    for _, to := range targets {
+      seen[to] = true
+    }
+  }
+
+  states := make([]string, 0, len(seen))
+  for state := range seen {
+    states = append(states, state)
+  }
+
+  sort.Strings(states)
+
+  return states
+}
+
+type workflowStateGetter interface {
+  WorkflowState(ctx context.Context, id string) (state string, err error)
+}
+
+// checkApproved refuses to go on unless the draft or the patch id is in
+// the approved state of workflow. A nil workflow approves everything.
+func checkApproved(ctx context.Context, workflow *Workflow, r workflowStateGetter, id, title string) error {
+  if nil == workflow {
+    return nil
+  }
+
+  state, err := r.WorkflowState(ctx, id)
+  if nil != err {
+    return err
+  }
+
+  if WorkflowApprovedState != state {
+    var p problem.Problem
+    p.Type(problem.TypeActionRefused)
+    p.Status(http.StatusConflict)
+    p.Title(title)
+    p.Detail(fmt.Sprintf("It must be '%s' first, but it is '%s'.", WorkflowApprovedState, state))
+    p.With("draft_uuid", id)
+    p.With("state", state)
+    return &p
+  }
+
+  return nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "testing"
+)
+
+func TestParseWorkflow(t *testing.T) {
+  t.Run("success", func(t *testing.T) {
+    w, err := ParseWorkflow(" writing:approved , approved:writing,writing:approved,")
+    require.NoError(t, err)
+    assert.True(t, w.allows("writing", "approved"))
+    assert.True(t, w.allows("approved", "writing"))
+    assert.False(t, w.allows("writing", "in_review"))
+    assert.Equal(t, []string{"approved", "writing"}, w.States())
+  })
+
+  t.Run("approved is unreachable", func(t *testing.T) {
+    _, err := ParseWorkflow("writing:in_review,approved:writing")
+    assert.Error(t, err)
+  })
+
+  t.Run("malformed transition", func(t *testing.T) {
+    _, err := ParseWorkflow("writing:approved,in_review")
+    assert.Error(t, err)
+  })
+
+  t.Run("invalid state", func(t *testing.T) {
+    _, err := ParseWorkflow("writing:Approved!")
+    assert.Error(t, err)
+  })
+
+  t.Run("transition to the same state", func(t *testing.T) {
+    _, err := ParseWorkflow("writing:writing,writing:approved")
+    assert.Error(t, err)
+  })
+}
+
+func TestDefaultWorkflow(t *testing.T) {
+  w := DefaultWorkflow()
+  assert.Equal(t, []string{"approved", "changes_requested", "in_review", "writing"}, w.States())
+  assert.True(t, w.allows("in_review", "approved"))
+  assert.False(t, w.allows("writing", "approved"))
+}
@@ -0,0 +1,117 @@
+package shortcode
+
+import (
+  "context"
+  "fmt"
+  "fontseca.dev/model"
+  "html"
+  "net/url"
+  "regexp"
+  "time"
+)
+
+func init() {
+  Register("callout", Callout)
+  Register("figure", Figure)
+  Register("youtube", YouTube)
+  Register("project", Project(nil))
+}
+
+// calloutTitles are the kinds of callouts and their default titles.
+var calloutTitles = map[string]string{
+  "note":   "Note",
+  "tip":    "Tip",
+  "warn":   "Warning",
+  "danger": "Danger",
+}
+
+// Callout highlights its content as a note, a tip, a warning or a danger:
+//
+//	{{< callout warn "Optional title" >}}
+//	Markdown content.
+//	{{< /callout >}}
+var Callout = Handler{
+  Paired: true,
+  Render: func(s *Shortcode, inner string) string {
+    kind := s.Get("kind", 0)
+
+    title, ok := calloutTitles[kind]
+    if !ok {
+      kind, title = "note", calloutTitles["note"]
+    }
+
+    if custom := s.Get("title", 1); "" != custom {
+      title = custom
+    }
+
+    return fmt.Sprintf(`<aside class="callout callout-%s"><p class="callout-title">%s</p>%s</aside>`, kind, html.EscapeString(title), inner)
+  },
+  Text: func(s *Shortcode) string {
+    return Text(s.Inner)
+  },
+}
+
+// Figure is an image with a caption:
+//
+//	{{< figure src="/public/images/lorem.png" alt="Lorem" caption="Lorem ipsum." >}}
+//
+// The arguments can also be positional, in that order.
+var Figure = Handler{
+  Render: func(s *Shortcode, _ string) string {
+    figure := fmt.Sprintf(`<figure><img src="%s" alt="%s" loading="lazy">`, html.EscapeString(s.Get("src", 0)), html.EscapeString(s.Get("alt", 1)))
+
+    if caption := s.Get("caption", 2); "" != caption {
+      figure += fmt.Sprintf(`<figcaption><div class="caption"><p>%s</p></div></figcaption>`, html.EscapeString(caption))
+    }
+
+    return figure + "</figure>"
+  },
+  Text: func(s *Shortcode) string {
+    return s.Get("caption", 2)
+  },
+}
+
+var youTubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// YouTube embeds a YouTube video by its ID, without cookies:
+//
+//	{{< youtube dQw4w9WgXcQ "Optional title" >}}
+var YouTube = Handler{
+  Render: func(s *Shortcode, _ string) string {
+    id := s.Get("id", 0)
+    if !youTubeID.MatchString(id) {
+      return ""
+    }
+
+    title := s.Get("title", 1)
+    if "" == title {
+      title = "YouTube video"
+    }
+
+    return fmt.Sprintf(`<div class="video"><iframe src="https://www.youtube-nocookie.com/embed/%s" title="%s" width="560" height="315" allow="accelerometer; clipboard-write; encrypted-media; gyroscope; picture-in-picture" allowfullscreen loading="lazy"></iframe></div>`, id, html.EscapeString(title))
+  },
+}
+
+// Project links to a project of the work page, as a card with its name and
+// summary if lookup finds it, or with its slug otherwise:
+//
+//	{{< project lorem-ipsum >}}
+func Project(lookup func(ctx context.Context, slug string) (*model.Project, error)) Handler {
+  return Handler{
+    Render: func(s *Shortcode, _ string) string {
+      slug := s.Get("slug", 0)
+      href := html.EscapeString("/work/" + url.PathEscape(slug))
+
+      if nil != lookup {
+        ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+        defer cancel()
+
+        if project, err := lookup(ctx, slug); nil == err && nil != project {
+          return fmt.Sprintf(`<a class="project-card" href="%s"><strong>%s</strong><span>%s</span></a>`, href, html.EscapeString(project.Name), html.EscapeString(project.Summary))
+        }
+      }
+
+      return fmt.Sprintf(`<a class="project-card" href="%s"><strong>%s</strong></a>`, href, html.EscapeString(slug))
+    },
+  }
+}
@@ -0,0 +1,328 @@
+package shortcode
+
+import (
+  "crypto/rand"
+  "encoding/hex"
+  "strconv"
+  "strings"
+  "sync"
+)
+
+// Shortcode is a call to a handler in Markdown content, in the form
+// '{{< name arg key="value" >}}'. Paired shortcodes wrap content up to
+// their closing tag, '{{< /name >}}'.
+type Shortcode struct {
+  Name   string
+  Args   []string          // the positional arguments
+  Params map[string]string // the named arguments
+  Inner  string            // the Markdown between the tags of a paired shortcode
+}
+
+// Get retrieves the named argument key or, if there is none, the
+// positional argument at position.
+func (s *Shortcode) Get(key string, position int) string {
+  if value, ok := s.Params[key]; ok {
+    return value
+  }
+
+  if 0 <= position && position < len(s.Args) {
+    return s.Args[position]
+  }
+
+  return ""
+}
+
+// Handler renders a shortcode.
+type Handler struct {
+  // Paired tells whether the shortcode wraps content up to a closing tag.
+  Paired bool
+
+  // Render renders a shortcode into HTML. inner is the HTML of the content
+  // of a paired shortcode.
+  Render func(s *Shortcode, inner string) string
+
+  // Text is the text of a shortcode that is read, and so counts towards
+  // the reading time of an article. If nil, a shortcode has none.
+  Text func(s *Shortcode) string
+}
+
+var (
+  mu       sync.RWMutex
+  handlers = make(map[string]*Handler)
+)
+
+// Register registers the handler of the shortcodes called name, replacing
+// any previous one.
+func Register(name string, handler Handler) {
+  mu.Lock()
+  defer mu.Unlock()
+  handlers[name] = &handler
+}
+
+func lookup(name string) *Handler {
+  mu.RLock()
+  defer mu.RUnlock()
+  return handlers[name]
+}
+
+// Render renders Markdown content with shortcodes into HTML. Shortcodes
+// are replaced with placeholders before toHTML renders md, so that their
+// HTML is not altered by the Markdown renderer, and the placeholders are
+// then replaced with the HTML of the shortcodes. A shortcode that fills a
+// paragraph replaces the whole paragraph.
+func Render(md string, toHTML func(md string) string) string {
+  var (
+    prefix     = placeholderPrefix()
+    rendered   = make([]string, 0)
+    withMarker = expand(md, func(s *Shortcode, h *Handler) string {
+      inner := ""
+      if h.Paired {
+        inner = Render(s.Inner, toHTML)
+      }
+
+      rendered = append(rendered, h.Render(s, inner))
+
+      return prefix + strconv.Itoa(len(rendered)-1) + "Z"
+    })
+  )
+
+  html := toHTML(withMarker)
+
+  for i := len(rendered) - 1; 0 <= i; i-- {
+    marker := prefix + strconv.Itoa(i) + "Z"
+    html = strings.ReplaceAll(html, "<p>"+marker+"</p>", rendered[i])
+    html = strings.ReplaceAll(html, marker, rendered[i])
+  }
+
+  return html
+}
+
+// Text replaces the shortcodes in md with the text that is read of them.
+func Text(md string) string {
+  return expand(md, func(s *Shortcode, h *Handler) string {
+    if nil == h.Text {
+      return ""
+    }
+
+    return h.Text(s)
+  })
+}
+
+// placeholderPrefix is a random prefix of placeholders, made of letters
+// and numbers only so Markdown leaves it as is, that cannot be guessed
+// by authors.
+func placeholderPrefix() string {
+  var b = make([]byte, 8)
+  _, _ = rand.Read(b)
+  return "SHORTCODE" + hex.EncodeToString(b) + "N"
+}
+
+// expand replaces the registered shortcodes in md, outside of fenced code
+// blocks, with the result of replace. Unregistered shortcodes are left as
+// they are, and '{{</* name */>}}' is unescaped into '{{< name >}}'.
+func expand(md string, replace func(s *Shortcode, h *Handler) string) string {
+  var (
+    b     strings.Builder
+    lines = strings.SplitAfter(md, "\n")
+    fence string
+    prose strings.Builder
+  )
+
+  flush := func() {
+    b.WriteString(expandProse(prose.String(), replace))
+    prose.Reset()
+  }
+
+  for _, line := range lines {
+    trimmed := strings.TrimSpace(line)
+
+    switch {
+    case "" == fence && (strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")):
+      flush()
+      fence = trimmed[:3]
+      b.WriteString(line)
+    case "" != fence:
+      if strings.HasPrefix(trimmed, fence) {
+        fence = ""
+      }
+      b.WriteString(line)
+    default:
+      prose.WriteString(line)
+    }
+  }
+
+  flush()
+
+  return b.String()
+}
+
+func expandProse(md string, replace func(s *Shortcode, h *Handler) string) string {
+  var b strings.Builder
+
+  for {
+    start := strings.Index(md, "{{<")
+    if -1 == start {
+      b.WriteString(md)
+      return b.String()
+    }
+
+    end := strings.Index(md[start:], ">}}")
+    if -1 == end {
+      b.WriteString(md)
+      return b.String()
+    }
+
+    end += start + len(">}}")
+    body := strings.TrimSpace(md[start+len("{{<") : end-len(">}}")])
+
+    b.WriteString(md[:start])
+
+    if strings.HasPrefix(body, "/*") && strings.HasSuffix(body, "*/") {
+      b.WriteString("{{< " + strings.TrimSpace(body[2:len(body)-2]) + " >}}")
+      md = md[end:]
+      continue
+    }
+
+    s := parse(body)
+    h := (*Handler)(nil)
+
+    if nil != s {
+      h = lookup(s.Name)
+    }
+
+    if nil == h {
+      b.WriteString(md[start:end])
+      md = md[end:]
+      continue
+    }
+
+    md = md[end:]
+
+    if h.Paired {
+      inner, rest, ok := closing(md, s.Name)
+      if ok {
+        s.Inner = inner
+        md = rest
+      }
+    }
+
+    b.WriteString(replace(s, h))
+  }
+}
+
+// closing finds the closing tag of the paired shortcode name in md, which
+// follows its opening tag, taking nested shortcodes of the same name into
+// account.
+func closing(md, name string) (inner, rest string, ok bool) {
+  depth := 0
+
+  for i := 0; i < len(md); {
+    start := strings.Index(md[i:], "{{<")
+    if -1 == start {
+      return "", md, false
+    }
+
+    start += i
+
+    end := strings.Index(md[start:], ">}}")
+    if -1 == end {
+      return "", md, false
+    }
+
+    end += start + len(">}}")
+    fields := strings.Fields(md[start+len("{{<") : end-len(">}}")])
+
+    switch {
+    case 0 < len(fields) && "/"+name == fields[0]:
+      if 0 == depth {
+        return md[:start], md[end:], true
+      }
+      depth--
+    case 0 < len(fields) && name == fields[0]:
+      depth++
+    }
+
+    i = end
+  }
+
+  return "", md, false
+}
+
+// parse parses the body of a shortcode, between '{{<' and '>}}'. Arguments
+// are separated by spaces, unless they are double-quoted.
+func parse(body string) *Shortcode {
+  tokens := tokenize(body)
+  if 0 == len(tokens) || strings.HasPrefix(tokens[0], "/") {
+    return nil
+  }
+
+  s := &Shortcode{Name: tokens[0], Args: make([]string, 0), Params: make(map[string]string)}
+
+  for _, token := range tokens[1:] {
+    if key, value, ok := strings.Cut(token, "="); ok && isIdentifier(key) {
+      s.Params[key] = unquote(value)
+      continue
+    }
+
+    s.Args = append(s.Args, unquote(token))
+  }
+
+  return s
+}
+
+func tokenize(body string) []string {
+  var (
+    tokens  = make([]string, 0)
+    token   strings.Builder
+    quoted  bool
+    escaped bool
+  )
+
+  for _, r := range body {
+    switch {
+    case escaped:
+      token.WriteRune(r)
+      escaped = false
+    case '\\' == r && quoted:
+      token.WriteRune(r)
+      escaped = true
+    case '"' == r:
+      token.WriteRune(r)
+      quoted = !quoted
+    case (' ' == r || '\t' == r || '\n' == r) && !quoted:
+      if 0 < token.Len() {
+        tokens = append(tokens, token.String())
+        token.Reset()
+      }
+    default:
+      token.WriteRune(r)
+    }
+  }
+
+  if 0 < token.Len() {
+    tokens = append(tokens, token.String())
+  }
+
+  return tokens
+}
+
+func unquote(value string) string {
+  if unquoted, err := strconv.Unquote(value); nil == err {
+    return unquoted
+  }
+  return value
+}
+
+func isIdentifier(key string) bool {
+  if "" == key {
+    return false
+  }
+
+  for _, r := range key {
+    if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || '_' == r || '-' == r) {
+      return false
+    }
+  }
+
+  return true
+}
@@ -0,0 +1,91 @@
+package shortcode
+
+import (
+  "context"
+  "errors"
+  "fontseca.dev/model"
+  "github.com/stretchr/testify/assert"
+  "github.com/stretchr/testify/require"
+  "strings"
+  "testing"
+)
+
+// paragraphs is a stand-in Markdown renderer that wraps each paragraph
+// in <p> tags.
+func paragraphs(md string) string {
+  var b strings.Builder
+
+  for _, p := range strings.Split(strings.TrimSpace(md), "\n\n") {
+    if p = strings.TrimSpace(p); "" != p {
+      b.WriteString("<p>" + p + "</p>")
+    }
+  }
+
+  return b.String()
+}
+
+func Test_parse(t *testing.T) {
+  s := parse(`figure /lorem.png "Lorem \"ipsum\"" caption="Dolor sit amet."`)
+  require.NotNil(t, s)
+  assert.Equal(t, "figure", s.Name)
+  assert.Equal(t, []string{"/lorem.png", `Lorem "ipsum"`}, s.Args)
+  assert.Equal(t, map[string]string{"caption": "Dolor sit amet."}, s.Params)
+
+  assert.Equal(t, "/lorem.png", s.Get("src", 0))
+  assert.Equal(t, "Dolor sit amet.", s.Get("caption", 2))
+  assert.Equal(t, "", s.Get("width", 3))
+
+  assert.Nil(t, parse(""))
+  assert.Nil(t, parse("/callout"))
+}
+
+func TestRender(t *testing.T) {
+  t.Run("replaces paragraphs", func(t *testing.T) {
+    html := Render("Lorem.\n\n{{< youtube dQw4w9WgXcQ >}}\n\nIpsum.", paragraphs)
+    assert.True(t, strings.HasPrefix(html, `<p>Lorem.</p><div class="video"><iframe src="https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ"`))
+    assert.True(t, strings.HasSuffix(html, `</iframe></div><p>Ipsum.</p>`))
+  })
+
+  t.Run("renders inline", func(t *testing.T) {
+    html := Render("See {{< project lorem-ipsum >}}.", paragraphs)
+    assert.Equal(t, `<p>See <a class="project-card" href="/work/lorem-ipsum"><strong>lorem-ipsum</strong></a>.</p>`, html)
+  })
+
+  t.Run("renders the content of paired shortcodes", func(t *testing.T) {
+    html := Render("{{< callout warn >}}\nLorem.\n\n{{< callout tip \"Ipsum\" >}}\nDolor.\n{{< /callout >}}\n{{< /callout >}}", paragraphs)
+    assert.Equal(t, `<aside class="callout callout-warn"><p class="callout-title">Warning</p><p>Lorem.</p>`+
+      `<aside class="callout callout-tip"><p class="callout-title">Ipsum</p><p>Dolor.</p></aside></aside>`, html)
+  })
+
+  t.Run("escapes arguments", func(t *testing.T) {
+    html := Render(`{{< figure src="x.png" alt="<script>" caption="</p>" >}}`, paragraphs)
+    assert.Equal(t, `<figure><img src="x.png" alt="&lt;script&gt;" loading="lazy"><figcaption><div class="caption"><p>&lt;/p&gt;</p></div></figcaption></figure>`, html)
+    assert.Equal(t, "", Render(`{{< youtube "x><script>" >}}`, paragraphs))
+  })
+
+  t.Run("leaves code and unknown shortcodes", func(t *testing.T) {
+    md := "```\n{{< youtube dQw4w9WgXcQ >}}\n```\n\n{{< lorem >}} {{< /callout >}}"
+    assert.Equal(t, md, Render(md, func(md string) string { return md }))
+  })
+
+  t.Run("unescapes shortcodes", func(t *testing.T) {
+    assert.Equal(t, "<p>{{< youtube dQw4w9WgXcQ >}}</p>", Render("{{</* youtube dQw4w9WgXcQ */>}}", paragraphs))
+  })
+}
+
+func TestProject(t *testing.T) {
+  h := Project(func(_ context.Context, slug string) (*model.Project, error) {
+    if "lorem" != slug {
+      return nil, errors.New("not found")
+    }
+    return &model.Project{Name: "Lorem", Summary: "Ipsum & dolor."}, nil
+  })
+
+  assert.Equal(t, `<a class="project-card" href="/work/lorem"><strong>Lorem</strong><span>Ipsum &amp; dolor.</span></a>`, h.Render(&Shortcode{Args: []string{"lorem"}}, ""))
+  assert.Equal(t, `<a class="project-card" href="/work/ipsum"><strong>ipsum</strong></a>`, h.Render(&Shortcode{Args: []string{"ipsum"}}, ""))
+}
+
+func TestText(t *testing.T) {
+  md := "Lorem.\n\n{{< callout >}}\nIpsum {{< figure x.png \"\" \"Dolor sit.\" >}}\n{{< /callout >}}\n\n{{< youtube dQw4w9WgXcQ >}}"
+  assert.Equal(t, 4, len(strings.Fields(Text(md))))
+}
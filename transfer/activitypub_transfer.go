@@ -0,0 +1,102 @@
+package transfer
+
+import (
+  "time"
+)
+
+// ActivityStreams is the JSON-LD context of every ActivityPub document.
+const ActivityStreams = "https://www.w3.org/ns/activitystreams"
+
+// Public is the special collection used to address activities to anyone.
+const Public = ActivityStreams + "#Public"
+
+// WebFinger is the JSON Resource Descriptor used to discover the
+// ActivityPub actor behind an 'acct:' URI.
+type WebFinger struct {
+  Subject string          `json:"subject"`
+  Aliases []string        `json:"aliases,omitempty"`
+  Links   []WebFingerLink `json:"links"`
+}
+
+// WebFingerLink is a link of a WebFinger resource.
+type WebFingerLink struct {
+  Rel  string `json:"rel"`
+  Type string `json:"type,omitempty"`
+  Href string `json:"href,omitempty"`
+}
+
+// Actor is an ActivityPub actor document.
+type Actor struct {
+  Context           any       `json:"@context,omitempty"`
+  ID                string    `json:"id"`
+  Type              string    `json:"type"`
+  PreferredUsername string    `json:"preferredUsername,omitempty"`
+  Name              string    `json:"name,omitempty"`
+  Summary           string    `json:"summary,omitempty"`
+  URL               string    `json:"url,omitempty"`
+  Inbox             string    `json:"inbox"`
+  Outbox            string    `json:"outbox,omitempty"`
+  Followers         string    `json:"followers,omitempty"`
+  Endpoints         *struct {
+    SharedInbox string `json:"sharedInbox,omitempty"`
+  } `json:"endpoints,omitempty"`
+  PublicKey *ActorKey `json:"publicKey,omitempty"`
+}
+
+// ActorKey is the public key an actor signs its requests with.
+type ActorKey struct {
+  ID           string `json:"id"`
+  Owner        string `json:"owner"`
+  PublicKeyPem string `json:"publicKeyPem"`
+}
+
+// Activity is an ActivityPub activity. Object is either the ID of an
+// object or the object itself, which may be another activity.
+type Activity struct {
+  Context   any        `json:"@context,omitempty"`
+  ID        string     `json:"id"`
+  Type      string     `json:"type"`
+  Actor     string     `json:"actor"`
+  Published *time.Time `json:"published,omitempty"`
+  To        []string   `json:"to,omitempty"`
+  CC        []string   `json:"cc,omitempty"`
+  Object    any        `json:"object"`
+}
+
+// ArticleObject is the ActivityPub representation of an article.
+type ArticleObject struct {
+  Context      any        `json:"@context,omitempty"`
+  ID           string     `json:"id"`
+  Type         string     `json:"type"`
+  AttributedTo string     `json:"attributedTo"`
+  Name         string     `json:"name"`
+  Summary      string     `json:"summary,omitempty"`
+  Content      string     `json:"content,omitempty"`
+  MediaType    string     `json:"mediaType,omitempty"`
+  URL          string     `json:"url"`
+  Published    *time.Time `json:"published,omitempty"`
+  Updated      *time.Time `json:"updated,omitempty"`
+  To           []string   `json:"to,omitempty"`
+  CC           []string   `json:"cc,omitempty"`
+  Tag          []Hashtag  `json:"tag,omitempty"`
+}
+
+// Hashtag is a tag of an ActivityPub object.
+type Hashtag struct {
+  Type string `json:"type"`
+  Href string `json:"href"`
+  Name string `json:"name"`
+}
+
+// OrderedCollection is an ActivityPub collection, or a page of it if
+// PartOf is not empty.
+type OrderedCollection struct {
+  Context      any    `json:"@context,omitempty"`
+  ID           string `json:"id"`
+  Type         string `json:"type"`
+  TotalItems   *int64 `json:"totalItems,omitempty"`
+  First        string `json:"first,omitempty"`
+  Next         string `json:"next,omitempty"`
+  PartOf       string `json:"partOf,omitempty"`
+  OrderedItems []any  `json:"orderedItems,omitempty"`
+}
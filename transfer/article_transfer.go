@@ -7,25 +7,29 @@ import (
 
 // ArticleCreation represents the data required to create a new article entry.
 type ArticleCreation struct {
-  Title    string `json:"title" binding:"required,max=256"`
+  Title    string `json:"title" binding:"max=256"`
   Slug     string
   ReadTime int
   Content  string `json:"content"`
   Summary  string `json:"summary"`
   CoverURL string `json:"cover_url"`
   CoverCap string `json:"cover_caption"`
+  Template string `json:"template_id"` // to seed the rest of the fields with
+  Topic    string // only seeded from the template
 }
 
 // ArticleRevision represents the data required to update an existing article entry.
 type ArticleRevision struct {
-  Title    string `json:"title"`
-  Topic    string `json:"topic_id"`
-  Slug     string
-  ReadTime int
-  Content  string `json:"content"`
-  Summary  string `json:"summary"`
-  CoverURL string `json:"cover_url"`
-  CoverCap string `json:"cover_caption"`
+  Title        string `json:"title"`
+  Topic        string `json:"topic_id"`
+  Slug         string
+  ReadTime     int
+  Content      string `json:"content"`
+  Summary      string `json:"summary"`
+  CoverURL     string `json:"cover_url"`
+  CoverCap     string `json:"cover_caption"`
+  CanonicalURL string `json:"canonical_url"`
+  Version      int    `json:"version" binding:"min=0"` // the one revised; 0 revises any
 }
 
 // Article is a shallow article entry for transferring metadata.
@@ -38,7 +42,9 @@ type Article struct {
     URL  string `json:"url"` // in the form: 'https://fontseca.dev/archive/:topic'
   } `json:"topic"`
   URL         string     `json:"url"` // in the form: 'https://fontseca.dev/archive/:topic/:year/:month/:slug'
+  Slug        string     `json:"-"`
   IsPinned    bool       `json:"is_pinned"`
+  IsOutdated  bool       `json:"is_outdated"`
   PublishedAt *time.Time `json:"published_at"`
   Summary     string     `json:"summary"`
   CoverURL    string     `json:"cover_url"`
@@ -51,14 +57,58 @@ type Publication struct {
   Year  int
 }
 
+// ArticleSort is the order in which articles are listed.
+type ArticleSort string
+
+const (
+  // SortNewest lists pinned articles first and then the most recently
+  // published ones. It is the default sort mode.
+  SortNewest ArticleSort = "newest"
+
+  // SortOldest lists the least recently published articles first.
+  SortOldest ArticleSort = "oldest"
+
+  // SortMostViewed lists the most viewed articles first.
+  SortMostViewed ArticleSort = "views"
+
+  // SortRecentlyModified lists the most recently modified articles
+  // first. Articles that have never been modified are sorted by
+  // their publication date.
+  SortRecentlyModified ArticleSort = "modified"
+
+  // SortReadingTime lists the quickest articles to read first.
+  SortReadingTime ArticleSort = "read_time"
+)
+
+// ArticleSorts holds every valid ArticleSort.
+var ArticleSorts = []ArticleSort{
+  SortNewest,
+  SortOldest,
+  SortMostViewed,
+  SortRecentlyModified,
+  SortReadingTime,
+}
+
 // ArticleFilter represents the parameters used to query articles.
+//
+// Articles are paginated with Cursor, which is the NextCursor of a
+// previous page. For compatibility, if Page is greater than zero,
+// articles are paginated by offset instead and Cursor is ignored.
 type ArticleFilter struct {
-  Search      string
-  Topic       string
-  Tag         string
-  Publication *Publication
-  Page        int
-  RPP         int // records per page
+  Search       string
+  Topic        string
+  ExcludeTopic string
+  Tags         []string
+  MatchAllTags bool // if true, articles must have every tag in Tags; otherwise, any of them
+  Publication  *Publication
+  After        *time.Time // published at or after
+  Before       *time.Time // published before
+  MinReadTime  int        // in minutes
+  MaxReadTime  int        // in minutes
+  Sort         ArticleSort
+  Cursor       string
+  Page         int
+  RPP          int // records per page
 }
 
 // ArticleRequest represents the parameters used to query one
@@ -68,3 +118,36 @@ type ArticleRequest struct {
   Publication *Publication
   Slug        string
 }
+
+// LintViolation is a problem found in an article draft by one of the
+// rules it is checked against before it is published.
+type LintViolation struct {
+  Rule     string `json:"rule"`
+  Severity string `json:"severity"` // either 'error', which keeps the draft from being published, or 'warning'
+  Message  string `json:"message"`
+}
+
+// WorkflowTransition represents the data required to move a draft or a
+// patch to another state of the editorial workflow.
+type WorkflowTransition struct {
+  State   string `json:"state" binding:"required,max=32"`
+  Actor   string `json:"actor" binding:"required,max=64"`
+  Comment string `json:"comment" binding:"max=512"`
+}
+
+// ShareableLinkCreation represents the data required to share a draft
+// or a patch through a new link.
+type ShareableLinkCreation struct {
+  Label     string `json:"label" binding:"max=64"`
+  ExpiresIn int    `json:"expires_in" binding:"min=0,max=90"` // in days
+  Password  string `json:"password" binding:"max=72"`
+}
+
+// FeedbackCreation represents the data required to leave feedback on a
+// shared draft or patch.
+type FeedbackCreation struct {
+  Anchor    string `json:"anchor" binding:"required,max=128"`
+  Selection string `json:"selection" binding:"max=1024"`
+  Reviewer  string `json:"reviewer" binding:"required,max=64"`
+  Comment   string `json:"comment" binding:"required,max=2048"`
+}
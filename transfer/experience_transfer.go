@@ -22,4 +22,5 @@ type ExperienceUpdate struct {
   Summary         string `json:"summary"`
   Active          bool   `json:"-"`
   Hidden          bool   `json:"-"`
+  Version         int    `json:"version" binding:"min=0"` // the one updated; 0 updates any
 }
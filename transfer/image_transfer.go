@@ -0,0 +1,5 @@
+package transfer
+
+// ImageWidths are the widths, in pixels, of the derivatives of the images
+// of the media library, from the narrowest to the widest.
+var ImageWidths = []int{320, 640, 960, 1280, 1920}
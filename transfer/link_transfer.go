@@ -0,0 +1,22 @@
+package transfer
+
+import (
+  "time"
+)
+
+// LinkReport lists the broken links of an article or a project.
+type LinkReport struct {
+  Type  string        `json:"type"`
+  ID    string        `json:"id"`
+  Title string        `json:"title"`
+  Links []*BrokenLink `json:"links"`
+}
+
+// BrokenLink is a link that could not be reached in its last check, or
+// whose server responded with an error.
+type BrokenLink struct {
+  URL       string    `json:"url"`
+  Status    *int      `json:"status"`
+  Error     *string   `json:"error"`
+  CheckedAt time.Time `json:"checked_at"`
+}
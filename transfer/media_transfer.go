@@ -0,0 +1,8 @@
+package transfer
+
+// MediaUpload represents the data of a file uploaded to the media library.
+type MediaUpload struct {
+  Name    string `json:"-"`
+  AltText string `json:"alt_text" binding:"max=512"`
+  Content []byte `json:"-"`
+}
@@ -0,0 +1,33 @@
+package transfer
+
+import (
+  "encoding/xml"
+)
+
+// OEmbed is an oEmbed response, as described at https://oembed.com/.
+// It is encoded either as JSON or as XML.
+type OEmbed struct {
+  XMLName         xml.Name `json:"-" xml:"oembed"`
+  Version         string   `json:"version" xml:"version"`
+  Type            string   `json:"type" xml:"type"`
+  Title           string   `json:"title,omitempty" xml:"title,omitempty"`
+  AuthorName      string   `json:"author_name,omitempty" xml:"author_name,omitempty"`
+  AuthorURL       string   `json:"author_url,omitempty" xml:"author_url,omitempty"`
+  ProviderName    string   `json:"provider_name" xml:"provider_name"`
+  ProviderURL     string   `json:"provider_url" xml:"provider_url"`
+  CacheAge        int      `json:"cache_age,omitempty" xml:"cache_age,omitempty"`
+  ThumbnailURL    string   `json:"thumbnail_url,omitempty" xml:"thumbnail_url,omitempty"`
+  ThumbnailWidth  int      `json:"thumbnail_width,omitempty" xml:"thumbnail_width,omitempty"`
+  ThumbnailHeight int      `json:"thumbnail_height,omitempty" xml:"thumbnail_height,omitempty"`
+  HTML            string   `json:"html" xml:"html"`
+  Width           int      `json:"width" xml:"width"`
+  Height          int      `json:"height" xml:"height"`
+}
+
+// OEmbedRequest represents the parameters of an oEmbed request. A zero
+// MaxWidth or MaxHeight means there is no limit.
+type OEmbedRequest struct {
+  URL       string
+  MaxWidth  int
+  MaxHeight int
+}
@@ -8,9 +8,25 @@ type OG struct {
   URL         string
   ImageURL    string
   ImageAlt    string
+  CardURL     string // of the generated preview image, used if ImageURL is not set
   Type        string
 
   ArticlePublishedTime string
   ArticleAuthor        string
   ArticlePublisher     string
+
+  // PrevURL and NextURL are not part of the protocol, but they are
+  // rendered as rel=prev and rel=next links for paginated pages.
+  PrevURL string
+  NextURL string
+
+  // CanonicalURL, if not empty, overrides URL as the rel=canonical link
+  // of pages first published elsewhere. SyndicationURLs are rendered as
+  // rel=syndication links to the copies of the page published elsewhere.
+  CanonicalURL    string
+  SyndicationURLs []string
+
+  // Embeddable tells whether URL can be resolved by the oEmbed endpoint,
+  // in which case the oEmbed discovery links are rendered.
+  Embeddable bool
 }
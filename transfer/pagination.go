@@ -0,0 +1,55 @@
+package transfer
+
+import (
+  "encoding/base64"
+  "encoding/json"
+  "errors"
+)
+
+// Page is the envelope of every list method. It holds a slice of
+// records and an opaque cursor to retrieve the records that follow
+// the last one in Items. An empty NextCursor means there are no
+// more records to retrieve.
+type Page[T any] struct {
+  Items      []T    `json:"items"`
+  NextCursor string `json:"next_cursor"`
+}
+
+// EncodeCursor makes an opaque cursor out of the sort key of the last
+// record of a page. The values of the key are kept in order, so they
+// must be decoded with DecodeCursor in that same order.
+func EncodeCursor(key ...any) string {
+  data, err := json.Marshal(key)
+  if nil != err {
+    return ""
+  }
+
+  return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes an opaque cursor made by EncodeCursor into the
+// values pointed to by key.
+func DecodeCursor(cursor string, key ...any) error {
+  data, err := base64.RawURLEncoding.DecodeString(cursor)
+  if nil != err {
+    return err
+  }
+
+  var values []json.RawMessage
+
+  if err = json.Unmarshal(data, &values); nil != err {
+    return err
+  }
+
+  if len(values) != len(key) {
+    return errors.New("cursor does not match the sort key")
+  }
+
+  for i, value := range values {
+    if err = json.Unmarshal(value, key[i]); nil != err {
+      return err
+    }
+  }
+
+  return nil
+}
@@ -39,4 +39,5 @@ type ProjectUpdate struct {
   PlaygroundURL   string
   Archived        bool
   Finished        bool
+  Version         int `json:"version" binding:"min=0"` // the one updated; 0 updates any
 }
@@ -0,0 +1,22 @@
+package transfer
+
+// TemplateCreation represents the data required to create a new draft template.
+type TemplateCreation struct {
+  Name         string   `json:"name" binding:"required,max=64"`
+  TitlePattern string   `json:"title_pattern" binding:"max=256"`
+  Summary      string   `json:"summary" binding:"max=512"`
+  Content      string   `json:"content"`
+  Topic        string   `json:"topic_id" binding:"max=32"`
+  Tags         []string `json:"tags"` // the IDs of the default tags
+}
+
+// TemplateUpdate represents the data required to update an existing draft
+// template. A nil Tags keeps the default tags as they are.
+type TemplateUpdate struct {
+  Name         string   `json:"name" binding:"max=64"`
+  TitlePattern string   `json:"title_pattern" binding:"max=256"`
+  Summary      string   `json:"summary" binding:"max=512"`
+  Content      string   `json:"content"`
+  Topic        string   `json:"topic_id" binding:"max=32"`
+  Tags         []string `json:"tags"`
+}